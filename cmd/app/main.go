@@ -1,38 +1,165 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"newproject/internal/attachmentService"
+	"newproject/internal/auditService"
+	"newproject/internal/authService"
+	"newproject/internal/blobstore"
+	"newproject/internal/collabService"
+	"newproject/internal/commentService"
 	"newproject/internal/database"
 	"newproject/internal/handlers"
+	"newproject/internal/identity"
+	"newproject/internal/outbox"
+	"newproject/internal/policy"
+	"newproject/internal/projectService"
+	"newproject/internal/streamService"
 	"newproject/internal/taskService"
+	"newproject/internal/tenant"
 	"newproject/internal/userService"
+	"newproject/internal/web/attachments"
+	"newproject/internal/web/audit"
+	"newproject/internal/web/auth"
+	"newproject/internal/web/collab"
+	"newproject/internal/web/comments"
+	"newproject/internal/web/events"
+	"newproject/internal/web/projects"
 	"newproject/internal/web/tasks"
+	"newproject/internal/web/trash"
 	"newproject/internal/web/users"
+	"newproject/internal/web/webhooks"
+	"newproject/internal/web/workspaces"
+	"newproject/internal/webhookService"
+	"newproject/internal/workspaceService"
+	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
 
+// publicRoutes — маршруты, доступные без access-токена
+var publicRoutes = map[string]bool{
+	http.MethodPost + " /auth/login":   true,
+	http.MethodPost + " /auth/refresh": true,
+	http.MethodPost + " /auth/logout":  true,
+	http.MethodPost + " /users":        true,
+}
+
 func main() {
+	jwtSecret := os.Getenv("JWT_SECRET")
+	if jwtSecret == "" {
+		log.Fatal("JWT_SECRET is not set")
+	}
+	// Как выполнение задачи связано с подзадачами: block (по умолчанию), cascade или off
+	completionMode, err := taskService.ParseCompletionMode(os.Getenv("TASK_COMPLETION_MODE"))
+	if err != nil {
+		log.Fatalf("invalid TASK_COMPLETION_MODE: %v", err)
+	}
+	blobStore, err := blobstore.FromEnv()
+	if err != nil {
+		log.Fatalf("failed to init attachment storage: %v", err)
+	}
+	natsPublisher, err := outbox.NATSFromEnv()
+	if err != nil {
+		log.Fatalf("failed to init NATS publisher: %v", err)
+	}
+	maxAttachmentSize := attachmentService.DefaultMaxSize
+	if v := os.Getenv("ATTACHMENT_MAX_BYTES"); v != "" {
+		maxAttachmentSize, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || maxAttachmentSize <= 0 {
+			log.Fatalf("invalid ATTACHMENT_MAX_BYTES: %q", v)
+		}
+	}
+
 	database.InitDB()
-	if err := database.DB.AutoMigrate(&userService.User{}, &taskService.Task{}); err != nil {
+	if err := database.DB.Use(tenant.Plugin{}); err != nil {
+		log.Fatalf("failed to init tenant isolation: %v", err)
+	}
+	if err := database.DB.Use(auditService.Plugin{}); err != nil {
+		log.Fatalf("failed to init audit log: %v", err)
+	}
+	if err := database.DB.Use(outbox.Plugin{}); err != nil {
+		log.Fatalf("failed to init outbox: %v", err)
+	}
+	if err := database.DB.AutoMigrate(&workspaceService.Workspace{}, &userService.User{}, &workspaceService.WorkspaceMember{}, &taskService.TaskStatus{}, &taskService.Task{}, &taskService.TaskDependency{}, &taskService.TaskAssignee{}, &taskService.TaskEvent{}, &taskService.Tag{}, &taskService.TaskSeries{}, &projectService.Project{}, &commentService.Comment{}, &commentService.CommentEdit{}, &attachmentService.Attachment{}, &authService.RefreshToken{}, &auditService.AuditEntry{}, &webhookService.Webhook{}, &webhookService.WebhookDelivery{}, &outbox.OutboxMessage{}); err != nil {
 		log.Fatalf("failed to migrate database: %v", err)
 	}
 
 	e := echo.New()
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
+	e.Use(auditService.RequestID())
+	// Ограничиваем тело загрузки заранее, чтобы большой файл не лег на диск целиком.
+	// Запас в 1 МБ — на заголовки multipart.
+	e.Use(middleware.BodyLimitWithConfig(middleware.BodyLimitConfig{
+		Skipper: func(c echo.Context) bool {
+			return c.Request().Method+" "+c.Path() != http.MethodPost+" /tasks/:id/attachments"
+		},
+		Limit: strconv.FormatInt(maxAttachmentSize+1<<20, 10),
+	}))
 
 	taskRepo := taskService.NewTaskRepository(database.DB)
 	userRepo := userService.NewUserRepository(database.DB)
+	refreshTokenRepo := authService.NewRefreshTokenRepository(database.DB)
+	projectRepo := projectService.NewProjectRepository(database.DB)
+	commentRepo := commentService.NewCommentRepository(database.DB)
+	attachmentRepo := attachmentService.NewAttachmentRepository(database.DB)
+	workspaceRepo := workspaceService.NewWorkspaceRepository(database.DB)
+	auditRepo := auditService.NewAuditRepository(database.DB)
+	webhookRepo := webhookService.NewWebhookRepository(database.DB)
+	webhookDispatcher := webhookService.NewDispatcher(webhookRepo, &http.Client{Timeout: 10 * time.Second}, webhookService.DispatcherConfig{})
+	// События из outbox расходятся подписчикам внутри процесса, вебхукам и, если задан NATS_URL, в брокер
+	eventBus := outbox.NewBus(0)
+	publishers := outbox.Fanout{eventBus, webhookService.NewPublisher(webhookRepo)}
+	if natsPublisher != nil {
+		publishers = append(publishers, natsPublisher)
+	}
+	outboxRelay := outbox.NewRelay(outbox.NewOutboxRepository(database.DB), publishers, outbox.RelayConfig{})
+	streamHub := streamService.NewHub(taskRepo, 0)
+	eventBus.Subscribe(streamHub.Publish)
 
-	taskService := taskService.NewTaskService(taskRepo)
+	taskService := taskService.NewTaskService(taskRepo, projectRepo, userRepo, completionMode)
 	userService := userService.NewUserService(userRepo, taskService)
+	projectService := projectService.NewProjectService(projectRepo, taskService)
+	commentService := commentService.NewCommentService(commentRepo, taskService)
+	attachmentService := attachmentService.NewAttachmentService(attachmentRepo, blobStore, taskService, maxAttachmentSize)
+	workspaceService := workspaceService.NewWorkspaceService(workspaceRepo, userRepo, taskService)
+	auditService := auditService.NewAuditService(auditRepo)
+	webhookService := webhookService.NewWebhookService(webhookRepo)
+	collabService := collabService.NewService(taskService, userRepo)
+	streamHub.Listen(collabService.Publish)
+	accessPolicy := policy.NewPolicy(userRepo)
+	authService := authService.NewAuthService(refreshTokenRepo, userService, jwtSecret)
+
+	e.Use(identity.Middleware(authService, func(c echo.Context) bool {
+		return publicRoutes[c.Request().Method+" "+c.Path()]
+	}))
+	// Управление пространствами не привязано к пространству запроса: его ID в пути
+	e.Use(tenant.Middleware(workspaceService, func(c echo.Context) bool {
+		return publicRoutes[c.Request().Method+" "+c.Path()] || strings.HasPrefix(c.Path(), "/workspaces")
+	}))
+
+	taskHandler := handlers.NewTaskHandler(taskService, userService, accessPolicy)
+	userHandler := handlers.NewUserHandler(userService, workspaceService, accessPolicy)
+	authHandler := handlers.NewAuthHandler(authService)
+	projectHandler := handlers.NewProjectHandler(projectService)
+	commentHandler := handlers.NewCommentHandler(commentService)
+	attachmentHandler := handlers.NewAttachmentHandler(attachmentService)
+	workspaceHandler := handlers.NewWorkspaceHandler(workspaceService)
+	trashHandler := handlers.NewTrashHandler(taskService, userService, accessPolicy)
+	auditHandler := handlers.NewAuditHandler(auditService, accessPolicy)
+	webhookHandler := handlers.NewWebhookHandler(webhookService, accessPolicy)
+	eventHandler := handlers.NewEventHandler(streamHub, 15*time.Second)
+	collabHandler := handlers.NewCollabHandler(collabService)
 
-	taskHandler := handlers.NewTaskHandler(taskService, userService)
-	userHandler := handlers.NewUserHandler(userService)
+	authStrictHandler := auth.NewStrictHandler(authHandler, nil)
+	auth.RegisterHandlers(e, authStrictHandler)
 
 	userStrictHandler := users.NewStrictHandler(userHandler, nil)
 	users.RegisterHandlers(e, userStrictHandler)
@@ -40,19 +167,35 @@ func main() {
 	taskStrictHandler := tasks.NewStrictHandler(taskHandler, nil)
 	tasks.RegisterHandlers(e, taskStrictHandler)
 
-	e.GET("/users/:user_id/tasks", func(c echo.Context) error {
-		userID, err := strconv.Atoi(c.Param("user_id"))
-		if err != nil {
-			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid user ID"})
-		}
+	projectStrictHandler := projects.NewStrictHandler(projectHandler, nil)
+	projects.RegisterHandlers(e, projectStrictHandler)
 
-		tasks, err := taskService.GetTasksByUserID(uint(userID))
-		if err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
-		}
+	commentStrictHandler := comments.NewStrictHandler(commentHandler, nil)
+	comments.RegisterHandlers(e, commentStrictHandler)
+
+	attachmentStrictHandler := attachments.NewStrictHandler(attachmentHandler, nil)
+	attachments.RegisterHandlers(e, attachmentStrictHandler)
+
+	workspaceStrictHandler := workspaces.NewStrictHandler(workspaceHandler, nil)
+	workspaces.RegisterHandlers(e, workspaceStrictHandler)
+
+	trashStrictHandler := trash.NewStrictHandler(trashHandler, nil)
+	trash.RegisterHandlers(e, trashStrictHandler)
+
+	auditStrictHandler := audit.NewStrictHandler(auditHandler, nil)
+	audit.RegisterHandlers(e, auditStrictHandler)
+
+	webhookStrictHandler := webhooks.NewStrictHandler(webhookHandler, nil)
+	webhooks.RegisterHandlers(e, webhookStrictHandler)
+
+	// SSE-лента пишет ответ по частям, а WebSocket переключает протокол сам:
+	// строгий обработчик им не подходит
+	events.RegisterHandlers(e, eventHandler)
+	collab.RegisterHandlers(e, collabHandler)
 
-		return c.JSON(http.StatusOK, tasks)
-	})
+	// События публикуются и доставки вебхуков уходят в фоне, получатели не тормозят запросы к API
+	go outboxRelay.Run(context.Background())
+	go webhookDispatcher.Run(context.Background())
 
 	if err := e.Start(":8080"); err != nil {
 		log.Fatalf("failed to start with err: %v", err)
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"newproject/internal/auditService"
+	"newproject/internal/database"
+	"newproject/internal/models"
+	"newproject/internal/outbox"
+	"newproject/internal/tenant"
+	"newproject/internal/userService"
+)
+
+// grant-admin выдает роль администратора пользователю с указанным ID.
+// Нужна, чтобы назначить администратора, когда первый зарегистрированный
+// пользователь им быть не должен или недоступен. Пользователя выбирают по ID,
+// а не по email: email при регистрации не подтверждается.
+func main() {
+	var userID uint
+	flag.UintVar(&userID, "user-id", 0, "ID of the user who becomes an admin")
+	flag.Parse()
+	if userID == 0 {
+		log.Fatal("-user-id is required")
+	}
+
+	database.InitDB()
+	if err := database.DB.Use(tenant.Plugin{}); err != nil {
+		log.Fatalf("failed to init tenant isolation: %v", err)
+	}
+	if err := database.DB.Use(auditService.Plugin{}); err != nil {
+		log.Fatalf("failed to init audit log: %v", err)
+	}
+	if err := database.DB.Use(outbox.Plugin{}); err != nil {
+		log.Fatalf("failed to init outbox: %v", err)
+	}
+
+	// Роль действует во всей системе, а не в одном рабочем пространстве
+	ctx := tenant.WithAllWorkspaces(context.Background())
+	user, err := userService.NewUserRepository(database.DB).UpdateUserByID(ctx, userID, userService.User{Role: models.RoleAdmin})
+	if err != nil {
+		log.Fatalf("failed to grant admin role: %v", err)
+	}
+	log.Printf("User %d (%s) is now an admin", user.ID, user.Email)
+}
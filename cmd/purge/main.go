@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"newproject/internal/attachmentService"
+	"newproject/internal/auditService"
+	"newproject/internal/blobstore"
+	"newproject/internal/database"
+	"newproject/internal/outbox"
+	"newproject/internal/taskService"
+	"newproject/internal/tenant"
+	"newproject/internal/userService"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// defaultRetention — сколько удаленные задачи и пользователи лежат в корзине,
+// если не задан TRASH_RETENTION
+const defaultRetention = 30 * 24 * time.Hour
+
+// purge окончательно удаляет задачи и пользователей, пролежавших в корзине
+// дольше срока хранения, вместе с файлами их вложений, а заодно сообщения
+// outbox, опубликованные раньше того же срока. Запускается по расписанию.
+func main() {
+	retention := defaultRetention
+	if v := os.Getenv("TRASH_RETENTION"); v != "" {
+		var err error
+		retention, err = time.ParseDuration(v)
+		if err != nil {
+			log.Fatalf("invalid TRASH_RETENTION: %q", v)
+		}
+	}
+	flag.DurationVar(&retention, "retention", retention, "how long deleted items stay in the trash")
+	flag.Parse()
+	if retention <= 0 {
+		log.Fatalf("retention must be positive, got %s", retention)
+	}
+
+	blobStore, err := blobstore.FromEnv()
+	if err != nil {
+		log.Fatalf("failed to init attachment storage: %v", err)
+	}
+	database.InitDB()
+	if err := database.DB.Use(tenant.Plugin{}); err != nil {
+		log.Fatalf("failed to init tenant isolation: %v", err)
+	}
+	if err := database.DB.Use(auditService.Plugin{}); err != nil {
+		log.Fatalf("failed to init audit log: %v", err)
+	}
+
+	// Корзина очищается во всех рабочих пространствах сразу
+	ctx := tenant.WithAllWorkspaces(context.Background())
+	before := time.Now().Add(-retention)
+
+	var keys []string
+	var tasks, users, messages int64
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		// Ключи собираются до удаления: записи вложений уйдут каскадом
+		if keys, err = attachmentService.NewAttachmentRepository(tx).GetPurgedStorageKeys(ctx, before); err != nil {
+			return err
+		}
+		if tasks, err = taskService.NewTaskRepository(tx).PurgeTasks(ctx, before); err != nil {
+			return err
+		}
+		if users, err = userService.NewUserRepository(tx).PurgeUsers(ctx, before); err != nil {
+			return err
+		}
+		messages, err = outbox.NewOutboxRepository(tx).PurgePublished(ctx, before)
+		return err
+	})
+	if err != nil {
+		log.Fatalf("failed to purge trash: %v", err)
+	}
+
+	// Файлы удаляются после записей: забытый объект безопаснее записи без содержимого
+	removed := 0
+	for _, key := range keys {
+		if err := blobStore.Delete(ctx, key); err != nil {
+			log.Printf("Error removing blob %s: %v", key, err)
+			continue
+		}
+		removed++
+	}
+
+	log.Printf("Purged %d tasks and %d users deleted before %s, removed %d of %d attachment files and %d published outbox messages",
+		tasks, users, before.Format(time.RFC3339), removed, len(keys), messages)
+}
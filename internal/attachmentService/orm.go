@@ -0,0 +1,17 @@
+package attachmentService
+
+import "time"
+
+// Attachment — метаданные файла, приложенного к задаче. Само содержимое
+// лежит в BlobStore под ключом StorageKey.
+type Attachment struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	TaskID      uint      `gorm:"index" json:"task_id"`
+	UserID      uint      `gorm:"index" json:"user_id"` // кто загрузил файл
+	FileName    string    `gorm:"size:255" json:"file_name"`
+	ContentType string    `gorm:"size:255" json:"content_type"`
+	Size        int64     `json:"size"`
+	Checksum    string    `gorm:"size:64" json:"checksum"` // sha256 содержимого в hex
+	StorageKey  string    `gorm:"size:255;uniqueIndex" json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
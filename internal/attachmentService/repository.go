@@ -0,0 +1,71 @@
+package attachmentService
+
+import (
+	"context"
+	"newproject/internal/tenant"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AttachmentRepository interface {
+	CreateAttachment(attachment Attachment) (Attachment, error)
+	GetAttachmentsByTaskID(taskID uint) ([]Attachment, error)
+	GetAttachmentByID(id uint) (Attachment, error)
+	DeleteAttachmentByID(id uint) error
+	GetPurgedStorageKeys(ctx context.Context, before time.Time) ([]string, error)
+}
+
+type attachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) *attachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+func (r *attachmentRepository) CreateAttachment(attachment Attachment) (Attachment, error) {
+	err := r.db.Create(&attachment).Error
+	return attachment, err
+}
+
+func (r *attachmentRepository) GetAttachmentsByTaskID(taskID uint) ([]Attachment, error) {
+	var attachments []Attachment
+	err := r.db.Where("task_id = ?", taskID).Order("created_at, id").Find(&attachments).Error
+	return attachments, err
+}
+
+func (r *attachmentRepository) GetAttachmentByID(id uint) (Attachment, error) {
+	var attachment Attachment
+	err := r.db.First(&attachment, id).Error
+	return attachment, err
+}
+
+func (r *attachmentRepository) DeleteAttachmentByID(id uint) error {
+	result := r.db.Delete(&Attachment{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetPurgedStorageKeys возвращает ключи файлов, чьи записи исчезнут при очистке
+// корзины от удаленного раньше before: вложения таких задач, задач таких
+// пользователей и загруженные такими пользователями. Учитываются только задачи
+// пространства из контекста, очистка всей корзины идет с tenant.WithAllWorkspaces.
+func (r *attachmentRepository) GetPurgedStorageKeys(ctx context.Context, before time.Time) ([]string, error) {
+	inWorkspace, err := tenant.Condition(ctx, "t.workspace_id")
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	err = r.db.WithContext(ctx).Raw(`
+		SELECT a.storage_key FROM attachments a JOIN tasks t ON t.id = a.task_id
+		WHERE ? AND (t.deleted_at < ?
+			OR t.user_id IN (SELECT id FROM users WHERE deleted_at < ?)
+			OR a.user_id IN (SELECT id FROM users WHERE deleted_at < ?))`, inWorkspace, before, before, before).Scan(&keys).Error
+	return keys, err
+}
@@ -0,0 +1,70 @@
+package attachmentService
+
+import (
+	"context"
+	"errors"
+	"newproject/internal/tenant"
+	"slices"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestPurgedStorageKeysStayInWorkspace(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.Use(tenant.Plugin{}); err != nil {
+		t.Fatalf("tenant plugin: %v", err)
+	}
+	if err := db.AutoMigrate(&Attachment{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	// Вложениям нужны только пространство и время удаления задач и пользователей
+	for _, stmt := range []string{
+		"CREATE TABLE users (id integer PRIMARY KEY, deleted_at datetime)",
+		"CREATE TABLE tasks (id integer PRIMARY KEY, workspace_id integer NOT NULL, user_id integer, deleted_at datetime)",
+		"INSERT INTO users (id) VALUES (1)",
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	deleted := time.Now().Add(-48 * time.Hour)
+	db.Exec("INSERT INTO tasks (id, workspace_id, user_id, deleted_at) VALUES (1, 1, 1, ?), (2, 2, 1, ?)", deleted, deleted)
+
+	repo := NewAttachmentRepository(db)
+	for _, a := range []Attachment{{TaskID: 1, UserID: 1, StorageKey: "own"}, {TaskID: 2, UserID: 1, StorageKey: "foreign"}} {
+		if _, err := repo.CreateAttachment(a); err != nil {
+			t.Fatalf("create attachment: %v", err)
+		}
+	}
+
+	before := time.Now().Add(-24 * time.Hour)
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want []string
+	}{
+		{name: "workspace", ctx: tenant.WithWorkspace(context.Background(), 1), want: []string{"own"}},
+		{name: "all workspaces", ctx: tenant.WithAllWorkspaces(context.Background()), want: []string{"foreign", "own"}},
+	}
+	for _, tt := range tests {
+		keys, err := repo.GetPurgedStorageKeys(tt.ctx, before)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		slices.Sort(keys)
+		if !slices.Equal(keys, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, keys, tt.want)
+		}
+	}
+
+	if _, err := repo.GetPurgedStorageKeys(context.Background(), before); !errors.Is(err, tenant.ErrNoWorkspace) {
+		t.Errorf("without workspace: got %v, want %v", err, tenant.ErrNoWorkspace)
+	}
+}
@@ -0,0 +1,205 @@
+package attachmentService
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"newproject/internal/blobstore"
+	"newproject/internal/identity"
+	"newproject/internal/taskService"
+	"path/filepath"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// DefaultMaxSize — предельный размер файла, если он не задан в конфигурации
+const DefaultMaxSize int64 = 10 << 20
+
+var (
+	// ErrAttachmentNotFound возвращается и для вложений чужих задач
+	ErrAttachmentNotFound = fmt.Errorf("attachment not found: %w", gorm.ErrRecordNotFound)
+	// ErrTooLarge — файл больше допустимого размера
+	ErrTooLarge = errors.New("attachment is too large")
+	// ErrEmptyFile — загружен пустой файл
+	ErrEmptyFile = errors.New("attachment is empty")
+	// ErrInvalidFileName — у файла нет имени
+	ErrInvalidFileName = errors.New("attachment file name is required")
+)
+
+type AttachmentService struct {
+	repo        AttachmentRepository
+	store       blobstore.BlobStore
+	taskService *taskService.TaskService
+	maxSize     int64
+}
+
+func NewAttachmentService(repo AttachmentRepository, store blobstore.BlobStore, taskService *taskService.TaskService, maxSize int64) *AttachmentService {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	return &AttachmentService{repo: repo, store: store, taskService: taskService, maxSize: maxSize}
+}
+
+// MaxSize возвращает предельный размер файла в байтах
+func (s *AttachmentService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// Upload сохраняет файл и его метаданные. Тип содержимого определяется по
+// самим данным, а не по заголовку клиента.
+func (s *AttachmentService) Upload(ctx context.Context, taskID uint, fileName string, body io.Reader) (Attachment, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return Attachment{}, err
+	}
+	if _, err := s.taskService.GetTaskByID(ctx, taskID); err != nil {
+		return Attachment{}, err
+	}
+
+	fileName = filepath.Base(strings.ReplaceAll(strings.TrimSpace(fileName), `\`, "/"))
+	if fileName == "" || fileName == "." || fileName == "/" {
+		return Attachment{}, ErrInvalidFileName
+	}
+
+	// Читаем на байт больше лимита, чтобы отличить файл ровно в лимит от большего
+	data, err := io.ReadAll(io.LimitReader(body, s.maxSize+1))
+	if err != nil {
+		return Attachment{}, err
+	}
+	if int64(len(data)) > s.maxSize {
+		return Attachment{}, ErrTooLarge
+	}
+	if len(data) == 0 {
+		return Attachment{}, ErrEmptyFile
+	}
+
+	sum := sha256.Sum256(data)
+	key, err := storageKey(taskID)
+	if err != nil {
+		return Attachment{}, err
+	}
+	attachment := Attachment{
+		TaskID:      taskID,
+		UserID:      caller.UserID,
+		FileName:    fileName,
+		ContentType: sniffContentType(data, fileName),
+		Size:        int64(len(data)),
+		Checksum:    hex.EncodeToString(sum[:]),
+		StorageKey:  key,
+	}
+
+	if err := s.store.Put(ctx, key, bytes.NewReader(data), attachment.Size, attachment.ContentType); err != nil {
+		return Attachment{}, fmt.Errorf("store attachment: %w", err)
+	}
+	created, err := s.repo.CreateAttachment(attachment)
+	if err != nil {
+		// Без записи в базе объект никому не виден, убираем его
+		if delErr := s.store.Delete(context.WithoutCancel(ctx), key); delErr != nil {
+			log.Printf("Error removing orphaned blob %s: %v", key, delErr)
+		}
+		return Attachment{}, err
+	}
+	return created, nil
+}
+
+// GetAttachments возвращает вложения задачи в порядке загрузки
+func (s *AttachmentService) GetAttachments(ctx context.Context, taskID uint) ([]Attachment, error) {
+	if _, err := s.taskService.GetTaskByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAttachmentsByTaskID(taskID)
+}
+
+// Open возвращает метаданные вложения и его содержимое, закрыть которое должен вызывающий
+func (s *AttachmentService) Open(ctx context.Context, taskID, id uint) (Attachment, io.ReadCloser, error) {
+	attachment, err := s.getAttachment(ctx, taskID, id)
+	if err != nil {
+		return Attachment{}, nil, err
+	}
+
+	body, err := s.store.Get(ctx, attachment.StorageKey)
+	if errors.Is(err, blobstore.ErrNotFound) {
+		return Attachment{}, nil, fmt.Errorf("attachment %d has no content: %w", id, err)
+	} else if err != nil {
+		return Attachment{}, nil, err
+	}
+	return attachment, body, nil
+}
+
+// DeleteAttachment удаляет вложение. Сначала удаляется запись, потом объект:
+// забытый объект в хранилище безопаснее записи без содержимого.
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, taskID, id uint) error {
+	attachment, err := s.getAttachment(ctx, taskID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteAttachmentByID(id); err != nil {
+		return err
+	}
+	if err := s.store.Delete(ctx, attachment.StorageKey); err != nil {
+		log.Printf("Error removing blob %s of attachment %d: %v", attachment.StorageKey, id, err)
+	}
+	return nil
+}
+
+// getAttachment проверяет доступ к задаче и загружает ее вложение
+func (s *AttachmentService) getAttachment(ctx context.Context, taskID, id uint) (Attachment, error) {
+	if _, err := s.taskService.GetTaskByID(ctx, taskID); err != nil {
+		return Attachment{}, err
+	}
+
+	attachment, err := s.repo.GetAttachmentByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Attachment{}, ErrAttachmentNotFound
+	} else if err != nil {
+		return Attachment{}, err
+	}
+
+	if attachment.TaskID != taskID {
+		return Attachment{}, ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+// sniffContentType определяет тип по содержимому. Расширение файла
+// учитывается, только если по содержимому тип не распознан.
+func sniffContentType(data []byte, fileName string) string {
+	sniffed := http.DetectContentType(data)
+	if sniffed != "application/octet-stream" {
+		return sniffed
+	}
+	if byExt := mime.TypeByExtension(filepath.Ext(fileName)); byExt != "" && !isActiveContent(byExt) {
+		return byExt
+	}
+	return sniffed
+}
+
+// isActiveContent — типы, которые браузер может исполнить, их по одному
+// расширению не выдаем
+func isActiveContent(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/html", "application/xhtml+xml", "image/svg+xml", "text/javascript", "application/javascript", "text/xml", "application/xml":
+		return true
+	}
+	return false
+}
+
+// storageKey генерирует случайный ключ объекта в пространстве задачи
+func storageKey(taskID uint) (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tasks/%d/%s", taskID, hex.EncodeToString(b[:])), nil
+}
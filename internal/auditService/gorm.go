@@ -0,0 +1,348 @@
+package auditService
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"newproject/internal/identity"
+	"newproject/internal/tenant"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Auditable — модель, изменения которой пишутся в журнал.
+// AuditEntity возвращает имя сущности в журнале, например EntityTask.
+type Auditable interface {
+	AuditEntity() string
+}
+
+// Plugin пишет в журнал создание, изменение и удаление строк моделей,
+// реализующих Auditable. Запись делается в той же транзакции, что и само
+// изменение, поэтому откат изменения откатывает и запись.
+// Строки снимаются до и после запроса, в журнал попадают только изменившиеся
+// колонки. Колонки с тегом json:"-" (например, хеш пароля) не раскрываются.
+// Raw и Exec в журнал не попадают.
+type Plugin struct{}
+
+func (Plugin) Name() string {
+	return "audit"
+}
+
+func (Plugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().After("gorm:create").Before("gorm:commit_or_rollback_transaction").
+		Register("audit:create", recordCreate); err != nil {
+		return err
+	}
+	// Снимок до изменения делается после условий изоляции пространств
+	if err := callbacks.Update().Before("gorm:update").After("tenant:update").Register("audit:capture_update", capture); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:update").Before("gorm:commit_or_rollback_transaction").
+		Register("audit:update", record); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").After("tenant:delete").Register("audit:capture_delete", capture); err != nil {
+		return err
+	}
+	return callbacks.Delete().After("gorm:delete").Before("gorm:commit_or_rollback_transaction").
+		Register("audit:delete", record)
+}
+
+// Ключи в настройках запроса: снимок строк до изменения и сделанные записи
+const (
+	beforeKey  = "audit:before"
+	entriesKey = "audit:entries"
+)
+
+// maxRequestIDLength — длина колонки request_id, ID от клиента обрезается
+const maxRequestIDLength = 64
+
+// row — строка таблицы, снятая в журнал
+type row = map[string]any
+
+// auditEntity возвращает имя сущности модели запроса, если она участвует в журнале
+func auditEntity(db *gorm.DB) (string, bool) {
+	if db.Error != nil || db.Statement.Schema == nil || db.Statement.Schema.PrioritizedPrimaryField == nil {
+		return "", false
+	}
+	auditable, ok := reflect.New(db.Statement.Schema.ModelType).Interface().(Auditable)
+	if !ok {
+		return "", false
+	}
+	return auditable.AuditEntity(), true
+}
+
+// capture снимает строки, которые затронет изменение
+func capture(db *gorm.DB) {
+	if _, ok := auditEntity(db); !ok {
+		return
+	}
+
+	var where []clause.Expression
+	if c, ok := db.Statement.Clauses["WHERE"]; ok {
+		if w, ok := c.Expression.(clause.Where); ok {
+			where = append(where, w.Exprs...)
+		}
+	}
+	// Первичный ключ переданной записи GORM добавит в условие позже, сам
+	if ids := identities(db); len(ids) > 0 {
+		where = append(where, clause.IN{Column: clause.Column{Table: db.Statement.Table, Name: primaryKey(db)}, Values: ids})
+	}
+
+	rows, err := snapshot(db, where)
+	if err != nil {
+		db.AddError(fmt.Errorf("audit: %w", err))
+		return
+	}
+	db.InstanceSet(beforeKey, rows)
+}
+
+// record сравнивает снимок до изменения со строками после и пишет записи журнала
+func record(db *gorm.DB) {
+	entity, ok := auditEntity(db)
+	if !ok {
+		return
+	}
+
+	value, _ := db.InstanceGet(beforeKey)
+	before, _ := value.([]row)
+	if len(before) == 0 {
+		return
+	}
+
+	ids := make([]any, 0, len(before))
+	for _, r := range before {
+		ids = append(ids, r[primaryKey(db)])
+	}
+	after, err := snapshot(db, []clause.Expression{clause.IN{Column: clause.Column{Table: db.Statement.Table, Name: primaryKey(db)}, Values: ids}})
+	if err != nil {
+		db.AddError(fmt.Errorf("audit: %w", err))
+		return
+	}
+	write(db, entity, before, after)
+}
+
+// recordCreate пишет в журнал созданные строки
+func recordCreate(db *gorm.DB) {
+	entity, ok := auditEntity(db)
+	if !ok {
+		return
+	}
+
+	ids := identities(db)
+	if len(ids) == 0 {
+		return
+	}
+	after, err := snapshot(db, []clause.Expression{clause.IN{Column: clause.Column{Table: db.Statement.Table, Name: primaryKey(db)}, Values: ids}})
+	if err != nil {
+		db.AddError(fmt.Errorf("audit: %w", err))
+		return
+	}
+	write(db, entity, nil, after)
+}
+
+// snapshot читает колонки модели у строк, подходящих под условия, включая
+// удаленные мягко. Запрос идет в той же транзакции, что и изменение.
+func snapshot(db *gorm.DB, where []clause.Expression) ([]row, error) {
+	model := reflect.New(db.Statement.Schema.ModelType).Interface()
+	tx := db.Session(&gorm.Session{NewDB: true}).Unscoped().Model(model).Select(db.Statement.Schema.DBNames)
+	if len(where) > 0 {
+		tx.Statement.AddClause(clause.Where{Exprs: where})
+	}
+
+	var rows []row
+	err := tx.Find(&rows).Error
+	return rows, err
+}
+
+// write сопоставляет строки до и после по первичному ключу и сохраняет записи
+func write(db *gorm.DB, entity string, before, after []row) {
+	key := primaryKey(db)
+	afterByID := make(map[string]row, len(after))
+	for _, r := range after {
+		afterByID[fmt.Sprint(r[key])] = r
+	}
+
+	var entries []AuditEntry
+	if before == nil {
+		for _, r := range after {
+			entries = appendEntry(db, entries, entity, nil, r)
+		}
+	}
+	for _, r := range before {
+		entries = appendEntry(db, entries, entity, r, afterByID[fmt.Sprint(r[key])])
+	}
+	if len(entries) == 0 {
+		return
+	}
+
+	if err := db.Session(&gorm.Session{NewDB: true}).Create(&entries).Error; err != nil {
+		db.AddError(fmt.Errorf("audit: %w", err))
+		return
+	}
+	db.InstanceSet(entriesKey, entries)
+}
+
+// Entries возвращает записи журнала, сделанные запросом. Нужна плагинам,
+// которые реагируют на изменения в той же транзакции, например вебхукам:
+// их колбэки регистрируются после audit:create, audit:update и audit:delete.
+func Entries(db *gorm.DB) []AuditEntry {
+	value, _ := db.InstanceGet(entriesKey)
+	entries, _ := value.([]AuditEntry)
+	return entries
+}
+
+// appendEntry добавляет запись об изменении строки, если оно есть
+func appendEntry(db *gorm.DB, entries []AuditEntry, entity string, before, after row) []AuditEntry {
+	action := ActionUpdate
+	switch {
+	case before == nil:
+		action = ActionCreate
+	case after == nil:
+		action = ActionDelete
+	case before["deleted_at"] == nil && after["deleted_at"] != nil:
+		action = ActionDelete
+	case before["deleted_at"] != nil && after["deleted_at"] == nil:
+		action = ActionRestore
+	}
+
+	diffBefore, diffAfter := diff(db.Statement.Schema, before, after)
+	if diffBefore == nil && diffAfter == nil {
+		return entries
+	}
+
+	source := after
+	if source == nil {
+		source = before
+	}
+	entry := AuditEntry{
+		Entity:    entity,
+		EntityID:  toUint(source[primaryKey(db)]),
+		Action:    action,
+		Before:    diffBefore,
+		After:     diffAfter,
+		RequestID: RequestIDFromContext(db.Statement.Context),
+	}
+	if len(entry.RequestID) > maxRequestIDLength {
+		entry.RequestID = entry.RequestID[:maxRequestIDLength]
+	}
+	if caller, ok := identity.FromContext(db.Statement.Context); ok {
+		entry.ActorID = &caller.UserID
+	}
+	// Пространство берется из самой строки, а у моделей вне пространств — из запроса
+	if workspaceID := toUint(source["workspace_id"]); workspaceID != 0 {
+		entry.WorkspaceID = &workspaceID
+	} else if workspaceID, ok := tenant.FromContext(db.Statement.Context); ok {
+		entry.WorkspaceID = &workspaceID
+	}
+	return append(entries, entry)
+}
+
+// diff возвращает JSON изменившихся колонок до и после. У созданной строки
+// нет «до», у окончательно удаленной — «после». updated_at не сравнивается.
+func diff(s *schema.Schema, before, after row) (*string, *string) {
+	changedBefore := make(map[string]json.RawMessage)
+	changedAfter := make(map[string]json.RawMessage)
+	for _, column := range s.DBNames {
+		if column == "updated_at" && before != nil && after != nil {
+			continue
+		}
+		b, a := encode(before, column), encode(after, column)
+		if before != nil && after != nil && bytes.Equal(b, a) {
+			continue
+		}
+		if redacted(s, column) {
+			b, a = json.RawMessage(`"[redacted]"`), json.RawMessage(`"[redacted]"`)
+		}
+		if before != nil {
+			changedBefore[column] = b
+		}
+		if after != nil {
+			changedAfter[column] = a
+		}
+	}
+
+	if len(changedBefore) == 0 && len(changedAfter) == 0 {
+		return nil, nil
+	}
+	return marshal(before, changedBefore), marshal(after, changedAfter)
+}
+
+func encode(r row, column string) json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	value := r[column]
+	if b, ok := value.([]byte); ok {
+		value = string(b)
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return json.RawMessage(`null`)
+	}
+	return raw
+}
+
+func marshal(r row, changed map[string]json.RawMessage) *string {
+	if r == nil {
+		return nil
+	}
+	raw, _ := json.Marshal(changed)
+	s := string(raw)
+	return &s
+}
+
+// redacted сообщает, скрыто ли значение колонки: такие поля не отдаются и в API
+func redacted(s *schema.Schema, column string) bool {
+	field := s.LookUpField(column)
+	return field != nil && field.Tag.Get("json") == "-"
+}
+
+// identities возвращает ненулевые первичные ключи записей, переданных в запрос
+func identities(db *gorm.DB) []any {
+	field := db.Statement.Schema.PrioritizedPrimaryField
+	value := db.Statement.ReflectValue
+
+	var ids []any
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if id, isZero := field.ValueOf(db.Statement.Context, reflect.Indirect(value.Index(i))); !isZero {
+				ids = append(ids, id)
+			}
+		}
+	case reflect.Struct:
+		if id, isZero := field.ValueOf(db.Statement.Context, value); !isZero {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func primaryKey(db *gorm.DB) string {
+	return db.Statement.Schema.PrioritizedPrimaryField.DBName
+}
+
+// toUint приводит значение колонки из снимка к ID
+func toUint(value any) uint {
+	switch v := value.(type) {
+	case int64:
+		return uint(v)
+	case int32:
+		return uint(v)
+	case int:
+		return uint(v)
+	case uint:
+		return v
+	case uint64:
+		return uint(v)
+	case uint32:
+		return uint(v)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,256 @@
+package auditService
+
+import (
+	"context"
+	"errors"
+	"newproject/internal/identity"
+	"newproject/internal/tenant"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// note — модель пространства для тестов
+type note struct {
+	ID          uint
+	WorkspaceID uint `gorm:"not null;tenant"`
+	Text        string
+	Done        bool
+	DeletedAt   gorm.DeletedAt
+	UpdatedAt   time.Time
+}
+
+func (note) AuditEntity() string {
+	return EntityTask
+}
+
+// account — модель вне пространств со скрытой колонкой
+type account struct {
+	ID       uint
+	Email    string
+	Password string `json:"-"`
+}
+
+func (account) AuditEntity() string {
+	return EntityUser
+}
+
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_txlock=immediate&_busy_timeout=5000"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.Use(tenant.Plugin{}); err != nil {
+		t.Fatalf("tenant plugin: %v", err)
+	}
+	if err := db.Use(Plugin{}); err != nil {
+		t.Fatalf("audit plugin: %v", err)
+	}
+	if err := db.AutoMigrate(&note{}, &account{}, &AuditEntry{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// entries возвращает записи журнала в порядке создания
+func entries(t *testing.T, db *gorm.DB) []AuditEntry {
+	t.Helper()
+	var entries []AuditEntry
+	if err := db.Order("id").Find(&entries).Error; err != nil {
+		t.Fatalf("entries: %v", err)
+	}
+	return entries
+}
+
+func str(s *string) string {
+	if s == nil {
+		return "<nil>"
+	}
+	return *s
+}
+
+func TestPluginRecordsChangedColumns(t *testing.T) {
+	db := openTestDB(t)
+	ctx := tenant.WithWorkspace(context.Background(), 1)
+	ws := db.WithContext(ctx)
+
+	n := note{Text: "a"}
+	if err := ws.Create(&n).Error; err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := ws.Model(&note{}).Where("id = ?", n.ID).Update("text", "b").Error; err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	// Без изменившихся колонок записи нет, updated_at не в счет
+	if err := ws.Model(&note{}).Where("id = ?", n.ID).Update("text", "b").Error; err != nil {
+		t.Fatalf("update unchanged: %v", err)
+	}
+	// Из переданных колонок в запись попадают только изменившиеся
+	if err := ws.Model(&note{}).Where("id = ?", n.ID).Updates(map[string]any{"text": "b", "done": true}).Error; err != nil {
+		t.Fatalf("update two columns: %v", err)
+	}
+
+	got := entries(t, db)
+	if len(got) != 3 {
+		t.Fatalf("entries: got %d, want 3", len(got))
+	}
+	tests := []struct {
+		action, before, after string
+	}{
+		{action: ActionCreate, before: "<nil>"},
+		{action: ActionUpdate, before: `{"text":"a"}`, after: `{"text":"b"}`},
+		{action: ActionUpdate, before: `{"done":false}`, after: `{"done":true}`},
+	}
+	for i, tt := range tests {
+		entry := got[i]
+		if entry.Action != tt.action || entry.Entity != EntityTask || entry.EntityID != n.ID {
+			t.Errorf("entry %d: got %s %s %d, want %s task %d", i, entry.Action, entry.Entity, entry.EntityID, tt.action, n.ID)
+		}
+		if str(entry.Before) != tt.before {
+			t.Errorf("entry %d before: got %s, want %s", i, str(entry.Before), tt.before)
+		}
+		if tt.after != "" && str(entry.After) != tt.after {
+			t.Errorf("entry %d after: got %s, want %s", i, str(entry.After), tt.after)
+		}
+	}
+}
+
+func TestPluginRedactsHiddenColumns(t *testing.T) {
+	db := openTestDB(t)
+	a := account{Email: "a@x", Password: "hash"}
+	if err := db.Create(&a).Error; err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := db.Model(&account{}).Where("id = ?", a.ID).Update("password", "new hash").Error; err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	got := entries(t, db)
+	if len(got) != 2 {
+		t.Fatalf("entries: got %d, want 2", len(got))
+	}
+	if want := `{"email":"a@x","id":1,"password":"[redacted]"}`; str(got[0].After) != want {
+		t.Errorf("create after: got %s, want %s", str(got[0].After), want)
+	}
+	want := `{"password":"[redacted]"}`
+	if str(got[1].Before) != want || str(got[1].After) != want {
+		t.Errorf("update: got %s → %s, want %s → %s", str(got[1].Before), str(got[1].After), want, want)
+	}
+}
+
+func TestPluginRecordsDeleteAndRestore(t *testing.T) {
+	db := openTestDB(t)
+	ws := db.WithContext(tenant.WithWorkspace(context.Background(), 1))
+
+	n := note{Text: "a"}
+	if err := ws.Create(&n).Error; err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := ws.Delete(&note{}, n.ID).Error; err != nil {
+		t.Fatalf("soft delete: %v", err)
+	}
+	if err := ws.Unscoped().Model(&note{}).Where("id = ?", n.ID).Update("deleted_at", nil).Error; err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if err := ws.Unscoped().Delete(&note{}, n.ID).Error; err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+
+	got := entries(t, db)
+	want := []string{ActionCreate, ActionDelete, ActionRestore, ActionDelete}
+	if len(got) != len(want) {
+		t.Fatalf("entries: got %d, want %d", len(got), len(want))
+	}
+	for i, action := range want {
+		if got[i].Action != action {
+			t.Errorf("entry %d: got %s, want %s", i, got[i].Action, action)
+		}
+	}
+	if got[1].After == nil {
+		t.Errorf("soft delete: got no after, want deleted_at")
+	}
+	if got[3].Before == nil || got[3].After != nil {
+		t.Errorf("purge: got %s → %s, want only before", str(got[3].Before), str(got[3].After))
+	}
+}
+
+func TestPluginRollbackDropsEntries(t *testing.T) {
+	db := openTestDB(t)
+	ws := db.WithContext(tenant.WithWorkspace(context.Background(), 1))
+	n := note{Text: "a"}
+	if err := ws.Create(&n).Error; err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	failure := errors.New("failure")
+	err := ws.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&note{Text: "b"}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&note{}).Where("id = ?", n.ID).Update("text", "c").Error; err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("transaction: got %v, want %v", err, failure)
+	}
+	if got := entries(t, db); len(got) != 1 {
+		t.Errorf("entries: got %d, want only the first create", len(got))
+	}
+}
+
+func TestPluginAttributesEntries(t *testing.T) {
+	db := openTestDB(t)
+	ctx := identity.WithCaller(tenant.WithWorkspace(context.Background(), 3), identity.Caller{UserID: 7})
+	ctx = WithRequestID(ctx, "req-1")
+
+	if err := db.WithContext(tenant.WithWorkspace(context.Background(), 2)).Create(&note{Text: "a"}).Error; err != nil {
+		t.Fatalf("create note: %v", err)
+	}
+	if err := db.WithContext(ctx).Create(&account{Email: "a@x"}).Error; err != nil {
+		t.Fatalf("create account in workspace: %v", err)
+	}
+	if err := db.Create(&account{Email: "b@x"}).Error; err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+
+	id := func(v uint) *uint { return &v }
+	tests := []struct {
+		name      string
+		workspace *uint
+		actor     *uint
+		requestID string
+	}{
+		// Пространство задачи берется из строки, пользователя — из запроса
+		{name: "note", workspace: id(2)},
+		{name: "account in workspace", workspace: id(3), actor: id(7), requestID: "req-1"},
+		{name: "account outside workspaces"},
+	}
+	got := entries(t, db)
+	if len(got) != len(tests) {
+		t.Fatalf("entries: got %d, want %d", len(got), len(tests))
+	}
+	for i, tt := range tests {
+		entry := got[i]
+		if !equal(entry.WorkspaceID, tt.workspace) {
+			t.Errorf("%s: got workspace %v, want %v", tt.name, entry.WorkspaceID, tt.workspace)
+		}
+		if !equal(entry.ActorID, tt.actor) {
+			t.Errorf("%s: got actor %v, want %v", tt.name, entry.ActorID, tt.actor)
+		}
+		if entry.RequestID != tt.requestID {
+			t.Errorf("%s: got request %q, want %q", tt.name, entry.RequestID, tt.requestID)
+		}
+	}
+}
+
+func equal(a, b *uint) bool {
+	return (a == nil) == (b == nil) && (a == nil || *a == *b)
+}
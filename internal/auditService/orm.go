@@ -0,0 +1,40 @@
+package auditService
+
+import "time"
+
+// Сущности журнала
+const (
+	EntityTask = "task"
+	EntityUser = "user"
+)
+
+// Действия над сущностью
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+)
+
+// AuditEntry — запись журнала об изменении одной строки. Before и After —
+// JSON-объекты с изменившимися колонками: у создания есть только After,
+// у окончательного удаления — только Before.
+type AuditEntry struct {
+	ID uint `gorm:"primaryKey" json:"id"`
+	// ActorID — кто внес изменение, nil у регистрации и служебных задач
+	ActorID *uint `gorm:"index" json:"actor_id"`
+	// WorkspaceID — пространство изменения, nil у изменений пользователей вне пространства
+	WorkspaceID *uint   `gorm:"index" json:"workspace_id"`
+	Entity      string  `gorm:"size:32;not null;index:idx_audit_entries_entity" json:"entity"`
+	EntityID    uint    `gorm:"not null;index:idx_audit_entries_entity" json:"entity_id"`
+	Action      string  `gorm:"size:16;not null" json:"action"`
+	Before      *string `gorm:"type:jsonb" json:"before"`
+	After       *string `gorm:"type:jsonb" json:"after"`
+	// RequestID связывает записи одного HTTP-запроса, пуст у служебных задач
+	RequestID string    `gorm:"size:64;not null;default:''" json:"request_id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+func validEntity(entity string) bool {
+	return entity == EntityTask || entity == EntityUser
+}
@@ -0,0 +1,71 @@
+package auditService
+
+import (
+	"context"
+	"newproject/internal/pagination"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EntryFilter — условия выборки журнала. Нулевые поля выборку не ограничивают.
+type EntryFilter struct {
+	Entity   string
+	EntityID *uint
+	ActorID  *uint
+	From     *time.Time
+	To       *time.Time
+	// WorkspaceID — записи этого пространства и изменения его участников вне пространств
+	WorkspaceID *uint
+	// AllOutsideWorkspaces добавляет к WorkspaceID все изменения вне пространств,
+	// а не только касающиеся его участников. Только для администраторов системы.
+	AllOutsideWorkspaces bool
+}
+
+func (f EntryFilter) apply(db *gorm.DB) *gorm.DB {
+	if f.Entity != "" {
+		db = db.Where("entity = ?", f.Entity)
+	}
+	if f.EntityID != nil {
+		db = db.Where("entity_id = ?", *f.EntityID)
+	}
+	if f.ActorID != nil {
+		db = db.Where("actor_id = ?", *f.ActorID)
+	}
+	if f.From != nil {
+		db = db.Where("created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		db = db.Where("created_at < ?", *f.To)
+	}
+	if f.WorkspaceID != nil && f.AllOutsideWorkspaces {
+		db = db.Where("workspace_id = ? OR workspace_id IS NULL", *f.WorkspaceID)
+	} else if f.WorkspaceID != nil {
+		db = db.Where("workspace_id = ? OR (workspace_id IS NULL AND entity = ? AND entity_id IN (SELECT user_id FROM workspace_members WHERE workspace_id = ?))",
+			*f.WorkspaceID, EntityUser, *f.WorkspaceID)
+	}
+	return db
+}
+
+type AuditRepository interface {
+	GetEntries(ctx context.Context, filter EntryFilter, page pagination.Page) ([]AuditEntry, string, error)
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) *auditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) GetEntries(ctx context.Context, filter EntryFilter, page pagination.Page) ([]AuditEntry, string, error) {
+	var entries []AuditEntry
+	if err := pagination.Apply(filter.apply(r.db.WithContext(ctx)), page).Find(&entries).Error; err != nil {
+		return nil, "", err
+	}
+	entries, next := pagination.Trim(entries, page, func(e AuditEntry) pagination.Cursor {
+		return page.CursorFor(e.CreatedAt, e.ID)
+	})
+	return entries, next, nil
+}
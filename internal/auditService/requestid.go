@@ -0,0 +1,31 @@
+package auditService
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+type requestIDContextKey struct{}
+
+// WithRequestID кладет ID запроса в контекст
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestIDFromContext достает ID запроса из контекста или пустую строку
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+// RequestID выдает запросу ID (или берет X-Request-ID клиента), возвращает его
+// в ответе и кладет в контекст, чтобы журнал связал изменения с запросом
+func RequestID() echo.MiddlewareFunc {
+	return middleware.RequestIDWithConfig(middleware.RequestIDConfig{
+		RequestIDHandler: func(c echo.Context, requestID string) {
+			c.SetRequest(c.Request().WithContext(WithRequestID(c.Request().Context(), requestID)))
+		},
+	})
+}
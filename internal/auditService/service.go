@@ -0,0 +1,46 @@
+package auditService
+
+import (
+	"context"
+	"errors"
+	"newproject/internal/identity"
+	"newproject/internal/pagination"
+	"newproject/internal/tenant"
+)
+
+var (
+	// ErrInvalidEntity — сущность не из списка task/user
+	ErrInvalidEntity = errors.New("entity must be one of task, user")
+	// ErrInvalidRange — начало периода позже его конца
+	ErrInvalidRange = errors.New("from must be before to")
+)
+
+type AuditService struct {
+	repo AuditRepository
+}
+
+func NewAuditService(repo AuditRepository) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// GetEntries возвращает страницу журнала рабочего пространства из контекста.
+// Изменения вне пространств, например регистрация, видны по участникам
+// пространства, а администратору системы — все. Права на чтение журнала
+// проверяет вызывающий.
+func (s *AuditService) GetEntries(ctx context.Context, filter EntryFilter, page pagination.Page) ([]AuditEntry, string, error) {
+	if filter.Entity != "" && !validEntity(filter.Entity) {
+		return nil, "", ErrInvalidEntity
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, "", ErrInvalidRange
+	}
+
+	workspaceID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	filter.WorkspaceID = &workspaceID
+	caller, _ := identity.FromContext(ctx)
+	filter.AllOutsideWorkspaces = caller.IsPlatformAdmin()
+	return s.repo.GetEntries(ctx, filter, page)
+}
@@ -0,0 +1,58 @@
+package auditService
+
+import (
+	"context"
+	"newproject/internal/identity"
+	"newproject/internal/pagination"
+	"newproject/internal/tenant"
+	"testing"
+)
+
+func TestEntriesOutsideWorkspaces(t *testing.T) {
+	db := openTestDB(t)
+	if err := db.Exec("CREATE TABLE workspace_members (workspace_id integer, user_id integer)").Error; err != nil {
+		t.Fatalf("create members: %v", err)
+	}
+	// Пользователь 1 состоит в пространстве 1, пользователь 2 — только в 2
+	if err := db.Exec("INSERT INTO workspace_members VALUES (1, 1), (2, 2)").Error; err != nil {
+		t.Fatalf("insert members: %v", err)
+	}
+	id := func(v uint) *uint { return &v }
+	seed := []AuditEntry{
+		{Entity: EntityTask, EntityID: 1, Action: ActionCreate, WorkspaceID: id(1)},
+		{Entity: EntityTask, EntityID: 2, Action: ActionCreate, WorkspaceID: id(2)},
+		{Entity: EntityUser, EntityID: 1, Action: ActionCreate},
+		{Entity: EntityUser, EntityID: 2, Action: ActionCreate},
+		{Entity: EntityUser, EntityID: 3, Action: ActionUpdate},
+	}
+	if err := db.Create(&seed).Error; err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	service := NewAuditService(NewAuditRepository(db))
+	ws := tenant.WithWorkspace(context.Background(), 1)
+	tests := []struct {
+		name   string
+		caller identity.Caller
+		want   []uint
+	}{
+		{name: "workspace admin", caller: identity.Caller{UserID: 1, Role: "user", WorkspaceRole: "admin"}, want: []uint{seed[0].ID, seed[2].ID}},
+		{name: "platform admin", caller: identity.Caller{UserID: 1, Role: "admin", WorkspaceRole: "admin"}, want: []uint{seed[0].ID, seed[2].ID, seed[3].ID, seed[4].ID}},
+	}
+	page := pagination.Page{Limit: 10, Order: pagination.ByCreatedAt}
+	for _, tt := range tests {
+		entries, _, err := service.GetEntries(identity.WithCaller(ws, tt.caller), EntryFilter{}, page)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(entries) != len(tt.want) {
+			t.Errorf("%s: got %d entries, want %v", tt.name, len(entries), tt.want)
+			continue
+		}
+		for i, entry := range entries {
+			if entry.ID != tt.want[i] {
+				t.Errorf("%s: entry %d: got %d, want %d", tt.name, i, entry.ID, tt.want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package authService
+
+import (
+	"errors"
+	"net/http"
+	"newproject/internal/identity"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Authenticate реализует identity.Authenticator для Bearer access-токенов
+func (s *AuthService) Authenticate(r *http.Request) (identity.Caller, error) {
+	header := r.Header.Get("Authorization")
+	token, found := strings.CutPrefix(header, "Bearer ")
+	if !found || token == "" {
+		return identity.Caller{}, identity.ErrNoCredentials
+	}
+
+	userID, err := s.ParseAccessToken(token)
+	if err != nil {
+		return identity.Caller{}, err
+	}
+
+	// Пользователь мог быть удален после выдачи токена
+	user, err := s.userService.GetUserByIDAnyWorkspace(r.Context(), userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return identity.Caller{}, ErrInvalidToken
+	} else if err != nil {
+		return identity.Caller{}, err
+	}
+
+	return identity.Caller{UserID: user.ID, Email: user.Email, Role: user.Role}, nil
+}
@@ -0,0 +1,58 @@
+package authService
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"newproject/internal/identity"
+	"newproject/internal/userService"
+	"testing"
+	"time"
+)
+
+func TestAuthenticate(t *testing.T) {
+	s, db, user := newTestService(t)
+	pair, err := s.Login(context.Background(), "a@x", "secret")
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	ghost, err := s.newAccessToken(user.ID+1, time.Now())
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		header  string
+		wantErr error
+	}{
+		{name: "no header", wantErr: identity.ErrNoCredentials},
+		{name: "not bearer", header: "Basic " + pair.AccessToken, wantErr: identity.ErrNoCredentials},
+		{name: "empty bearer", header: "Bearer ", wantErr: identity.ErrNoCredentials},
+		{name: "refresh token as access token", header: "Bearer " + pair.RefreshToken, wantErr: ErrInvalidToken},
+		{name: "unknown user", header: "Bearer " + ghost, wantErr: ErrInvalidToken},
+		{name: "valid", header: "Bearer " + pair.AccessToken},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		if tt.header != "" {
+			req.Header.Set("Authorization", tt.header)
+		}
+		caller, err := s.Authenticate(req)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (caller.UserID != user.ID || caller.Email != user.Email || caller.Role != user.Role) {
+			t.Errorf("%s: got caller %+v, want user %+v", tt.name, caller, user)
+		}
+	}
+
+	// Токен удаленного пользователя перестает действовать сразу
+	db.Delete(&userService.User{}, user.ID)
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer "+pair.AccessToken)
+	if _, err := s.Authenticate(req); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("deleted user: got %v, want %v", err, ErrInvalidToken)
+	}
+}
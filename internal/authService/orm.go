@@ -0,0 +1,14 @@
+package authService
+
+import "time"
+
+// RefreshToken — выданный refresh-токен. В базе хранится только SHA-256 хеш
+// самого токена, поэтому утечка таблицы не позволяет продлить сессию.
+type RefreshToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	TokenHash string     `json:"-" gorm:"not null;uniqueIndex"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,48 @@
+package authService
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RefreshTokenRepository interface {
+	CreateRefreshToken(token RefreshToken) (RefreshToken, error)
+	GetRefreshTokenByHash(hash string) (RefreshToken, error)
+	RevokeRefreshToken(id uint) (bool, error)
+	RevokeAllForUser(userID uint) error
+}
+
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+func (r *refreshTokenRepository) CreateRefreshToken(token RefreshToken) (RefreshToken, error) {
+	err := r.db.Create(&token).Error
+	return token, err
+}
+
+func (r *refreshTokenRepository) GetRefreshTokenByHash(hash string) (RefreshToken, error) {
+	var token RefreshToken
+	err := r.db.Where("token_hash = ?", hash).First(&token).Error
+	return token, err
+}
+
+// RevokeRefreshToken отзывает токен и сообщает, был ли он активен до этого.
+// Условие на revoked_at делает ротацию атомарной при параллельных запросах.
+func (r *refreshTokenRepository) RevokeRefreshToken(id uint) (bool, error) {
+	result := r.db.Model(&RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *refreshTokenRepository) RevokeAllForUser(userID uint) error {
+	return r.db.Model(&RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
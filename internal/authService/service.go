@@ -0,0 +1,143 @@
+package authService
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"newproject/internal/userService"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultAccessTTL  = 15 * time.Minute
+	defaultRefreshTTL = 30 * 24 * time.Hour
+)
+
+// ErrInvalidToken возвращается для поддельных, просроченных или отозванных токенов
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// TokenPair — результат входа или обновления токенов
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+}
+
+type AuthService struct {
+	repo        RefreshTokenRepository
+	userService *userService.UserService
+	secret      []byte
+	accessTTL   time.Duration
+	refreshTTL  time.Duration
+}
+
+func NewAuthService(repo RefreshTokenRepository, userService *userService.UserService, secret string) *AuthService {
+	return &AuthService{
+		repo:        repo,
+		userService: userService,
+		secret:      []byte(secret),
+		accessTTL:   defaultAccessTTL,
+		refreshTTL:  defaultRefreshTTL,
+	}
+}
+
+// Login проверяет учетные данные и выдает новую пару токенов
+func (s *AuthService) Login(ctx context.Context, email, password string) (TokenPair, error) {
+	user, err := s.userService.VerifyPassword(ctx, email, password)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	return s.issueTokens(user.ID)
+}
+
+// Refresh меняет refresh-токен на новую пару токенов. Старый токен отзывается;
+// повторное предъявление уже отозванного токена считается утечкой,
+// и тогда отзываются все сессии пользователя.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (TokenPair, error) {
+	stored, err := s.repo.GetRefreshTokenByHash(hashRefreshToken(refreshToken))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return TokenPair{}, ErrInvalidToken
+	} else if err != nil {
+		return TokenPair{}, fmt.Errorf("error fetching refresh token: %w", err)
+	}
+
+	if stored.RevokedAt != nil {
+		s.revokeAll(stored.UserID)
+		return TokenPair{}, ErrInvalidToken
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return TokenPair{}, ErrInvalidToken
+	}
+
+	revoked, err := s.repo.RevokeRefreshToken(stored.ID)
+	if err != nil {
+		return TokenPair{}, fmt.Errorf("error revoking refresh token: %w", err)
+	}
+	if !revoked {
+		// Токен успели использовать параллельным запросом
+		s.revokeAll(stored.UserID)
+		return TokenPair{}, ErrInvalidToken
+	}
+
+	if _, err := s.userService.GetUserByIDAnyWorkspace(ctx, stored.UserID); errors.Is(err, gorm.ErrRecordNotFound) {
+		return TokenPair{}, ErrInvalidToken
+	} else if err != nil {
+		return TokenPair{}, err
+	}
+
+	return s.issueTokens(stored.UserID)
+}
+
+// Logout отзывает refresh-токен. Неизвестный токен ошибкой не считается.
+// Access-токен остается валидным до истечения своего короткого срока.
+func (s *AuthService) Logout(refreshToken string) error {
+	stored, err := s.repo.GetRefreshTokenByHash(hashRefreshToken(refreshToken))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("error fetching refresh token: %w", err)
+	}
+
+	if _, err := s.repo.RevokeRefreshToken(stored.ID); err != nil {
+		return fmt.Errorf("error revoking refresh token: %w", err)
+	}
+	return nil
+}
+
+func (s *AuthService) issueTokens(userID uint) (TokenPair, error) {
+	now := time.Now()
+
+	accessToken, err := s.newAccessToken(userID, now)
+	if err != nil {
+		return TokenPair{}, fmt.Errorf("error signing access token: %w", err)
+	}
+
+	refreshToken, err := newRefreshToken()
+	if err != nil {
+		return TokenPair{}, fmt.Errorf("error generating refresh token: %w", err)
+	}
+
+	_, err = s.repo.CreateRefreshToken(RefreshToken{
+		UserID:    userID,
+		TokenHash: hashRefreshToken(refreshToken),
+		ExpiresAt: now.Add(s.refreshTTL),
+	})
+	if err != nil {
+		return TokenPair{}, fmt.Errorf("error storing refresh token: %w", err)
+	}
+
+	return TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    now.Add(s.accessTTL),
+	}, nil
+}
+
+func (s *AuthService) revokeAll(userID uint) {
+	if err := s.repo.RevokeAllForUser(userID); err != nil {
+		log.Printf("Error revoking refresh tokens for user %d: %v", userID, err)
+	}
+}
@@ -0,0 +1,188 @@
+package authService
+
+import (
+	"context"
+	"errors"
+	"newproject/internal/models"
+	"newproject/internal/tenant"
+	"newproject/internal/userService"
+	"newproject/internal/workspaceService"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const testSecret = "test-secret"
+
+// newTestService создает AuthService на SQLite с одним пользователем a@x
+// и паролем secret
+func newTestService(t *testing.T) (*AuthService, *gorm.DB, models.User) {
+	t.Helper()
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_txlock=immediate&_busy_timeout=5000"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.Use(tenant.Plugin{}); err != nil {
+		t.Fatalf("tenant plugin: %v", err)
+	}
+	if err := db.AutoMigrate(&workspaceService.Workspace{}, &userService.User{}, &workspaceService.WorkspaceMember{}, &RefreshToken{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	users := userService.NewUserService(userService.NewUserRepository(db), nil)
+	user, err := users.CreateUser(context.Background(), models.User{Email: "a@x", Password: "secret"})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return NewAuthService(NewRefreshTokenRepository(db), users, testSecret), db, user
+}
+
+func TestLogin(t *testing.T) {
+	s, _, user := newTestService(t)
+
+	tests := []struct {
+		name, email, password string
+		wantErr               error
+	}{
+		{name: "valid credentials", email: "a@x", password: "secret"},
+		{name: "wrong password", email: "a@x", password: "guess", wantErr: userService.ErrInvalidCredentials},
+		{name: "unknown email", email: "b@x", password: "secret", wantErr: userService.ErrInvalidCredentials},
+	}
+	for _, tt := range tests {
+		pair, err := s.Login(context.Background(), tt.email, tt.password)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if id, err := s.ParseAccessToken(pair.AccessToken); err != nil || id != user.ID {
+			t.Errorf("%s: access token: got user %d, %v, want %d", tt.name, id, err, user.ID)
+		}
+		if pair.RefreshToken == "" || !pair.ExpiresAt.After(time.Now()) {
+			t.Errorf("%s: got refresh %q expiring %v", tt.name, pair.RefreshToken, pair.ExpiresAt)
+		}
+	}
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	s, _, user := newTestService(t)
+	ctx := context.Background()
+
+	first, err := s.Login(ctx, "a@x", "secret")
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	second, err := s.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Error("refresh token was not rotated")
+	}
+	if id, err := s.ParseAccessToken(second.AccessToken); err != nil || id != user.ID {
+		t.Errorf("access token: got user %d, %v, want %d", id, err, user.ID)
+	}
+	third, err := s.Refresh(ctx, second.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh rotated token: %v", err)
+	}
+
+	// Повтор отозванного токена — признак утечки: отзываются все сессии
+	other, err := s.Login(ctx, "a@x", "secret")
+	if err != nil {
+		t.Fatalf("second login: %v", err)
+	}
+	if _, err := s.Refresh(ctx, first.RefreshToken); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("reuse: got %v, want %v", err, ErrInvalidToken)
+	}
+	for name, token := range map[string]string{"rotated session": third.RefreshToken, "other session": other.RefreshToken} {
+		if _, err := s.Refresh(ctx, token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s after reuse: got %v, want %v", name, err, ErrInvalidToken)
+		}
+	}
+}
+
+func TestRefreshRejectsInvalidTokens(t *testing.T) {
+	s, db, user := newTestService(t)
+	ctx := context.Background()
+
+	expired, err := newRefreshToken()
+	if err != nil {
+		t.Fatalf("token: %v", err)
+	}
+	if _, err := s.repo.CreateRefreshToken(RefreshToken{UserID: user.ID, TokenHash: hashRefreshToken(expired), ExpiresAt: time.Now().Add(-time.Minute)}); err != nil {
+		t.Fatalf("store expired: %v", err)
+	}
+
+	deleted, err := s.Login(ctx, "a@x", "secret")
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	loggedOut, err := s.Login(ctx, "a@x", "secret")
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if err := s.Logout(loggedOut.RefreshToken); err != nil {
+		t.Fatalf("logout: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		setup func()
+	}{
+		{name: "unknown", token: "unknown"},
+		{name: "expired", token: expired},
+		{name: "logged out", token: loggedOut.RefreshToken},
+		{name: "deleted user", token: deleted.RefreshToken, setup: func() {
+			db.Delete(&userService.User{}, user.ID)
+		}},
+	}
+	for _, tt := range tests {
+		if tt.setup != nil {
+			tt.setup()
+		}
+		if _, err := s.Refresh(ctx, tt.token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, ErrInvalidToken)
+		}
+	}
+}
+
+func TestLogout(t *testing.T) {
+	s, db, _ := newTestService(t)
+	ctx := context.Background()
+
+	pair, err := s.Login(ctx, "a@x", "secret")
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if err := s.Logout(pair.RefreshToken); err != nil {
+		t.Fatalf("logout: %v", err)
+	}
+	var stored RefreshToken
+	if err := db.Where("token_hash = ?", hashRefreshToken(pair.RefreshToken)).First(&stored).Error; err != nil {
+		t.Fatalf("get token: %v", err)
+	}
+	if stored.RevokedAt == nil {
+		t.Error("logout did not revoke the token")
+	}
+
+	tests := []struct {
+		name, token string
+	}{
+		{name: "already logged out", token: pair.RefreshToken},
+		{name: "unknown token", token: "unknown"},
+	}
+	for _, tt := range tests {
+		if err := s.Logout(tt.token); err != nil {
+			t.Errorf("%s: got %v, want nil", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package authService
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestParseAccessToken(t *testing.T) {
+	s := &AuthService{secret: []byte(testSecret), accessTTL: time.Minute}
+	now := time.Now()
+
+	sign := func(method jwt.SigningMethod, key any, claims jwt.RegisteredClaims) string {
+		t.Helper()
+		token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		if err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		return token
+	}
+	valid, err := s.newAccessToken(7, now)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	claims := func(subject string, expires time.Time) jwt.RegisteredClaims {
+		return jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   subject,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expires),
+		}
+	}
+	parts := strings.Split(valid, ".")
+	tamperedPayload := sign(jwt.SigningMethodHS256, []byte(testSecret), claims("8", now.Add(time.Minute)))
+	tampered := parts[0] + "." + strings.Split(tamperedPayload, ".")[1] + "." + parts[2]
+
+	tests := []struct {
+		name   string
+		token  string
+		wantID uint
+	}{
+		{name: "valid", token: valid, wantID: 7},
+		{name: "expired", token: sign(jwt.SigningMethodHS256, []byte(testSecret), claims("7", now.Add(-time.Minute)))},
+		{name: "no expiry", token: sign(jwt.SigningMethodHS256, []byte(testSecret), jwt.RegisteredClaims{Issuer: tokenIssuer, Subject: "7"})},
+		{name: "other secret", token: sign(jwt.SigningMethodHS256, []byte("other"), claims("7", now.Add(time.Minute)))},
+		{name: "tampered payload", token: tampered},
+		{name: "other algorithm", token: sign(jwt.SigningMethodHS512, []byte(testSecret), claims("7", now.Add(time.Minute)))},
+		{name: "unsigned", token: sign(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, claims("7", now.Add(time.Minute)))},
+		{name: "other issuer", token: sign(jwt.SigningMethodHS256, []byte(testSecret), jwt.RegisteredClaims{Issuer: "other", Subject: "7", ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute))})},
+		{name: "subject is not an id", token: sign(jwt.SigningMethodHS256, []byte(testSecret), claims("admin", now.Add(time.Minute)))},
+		{name: "zero subject", token: sign(jwt.SigningMethodHS256, []byte(testSecret), claims("0", now.Add(time.Minute)))},
+		{name: "garbage", token: "not.a.token"},
+	}
+	for _, tt := range tests {
+		id, err := s.ParseAccessToken(tt.token)
+		if tt.wantID != 0 {
+			if err != nil || id != tt.wantID {
+				t.Errorf("%s: got %d, %v, want %d", tt.name, id, err, tt.wantID)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: got %d, %v, want %v", tt.name, id, err, ErrInvalidToken)
+		}
+	}
+}
+
+func TestRefreshTokensAreRandomAndStoredHashed(t *testing.T) {
+	a, err := newRefreshToken()
+	if err != nil {
+		t.Fatalf("token: %v", err)
+	}
+	b, err := newRefreshToken()
+	if err != nil {
+		t.Fatalf("token: %v", err)
+	}
+	if a == b {
+		t.Error("two refresh tokens are equal")
+	}
+	if hash := hashRefreshToken(a); hash == a || hash != hashRefreshToken(a) || hash == hashRefreshToken(b) {
+		t.Errorf("hash: got %q for %q", hash, a)
+	}
+}
@@ -0,0 +1,21 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound — объекта с таким ключом нет в хранилище
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore хранит содержимое файлов по ключу. Метаданные файлов лежат в базе,
+// хранилище знает только байты.
+type BlobStore interface {
+	// Put сохраняет size байт из body под ключом key, перезаписывая прежний объект
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Get открывает объект на чтение, закрыть его должен вызывающий
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete удаляет объект. Удаление отсутствующего объекта не считается ошибкой.
+	Delete(ctx context.Context, key string) error
+}
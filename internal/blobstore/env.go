@@ -0,0 +1,28 @@
+package blobstore
+
+import (
+	"fmt"
+	"os"
+)
+
+// FromEnv выбирает хранилище вложений по ATTACHMENT_STORE: local (по умолчанию) или s3
+func FromEnv() (BlobStore, error) {
+	switch store := os.Getenv("ATTACHMENT_STORE"); store {
+	case "", "local":
+		dir := os.Getenv("ATTACHMENT_DIR")
+		if dir == "" {
+			dir = "./data/attachments"
+		}
+		return NewLocalStore(dir)
+	case "s3":
+		return NewS3Store(S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			Region:    os.Getenv("S3_REGION"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		}, nil)
+	default:
+		return nil, fmt.Errorf("unknown ATTACHMENT_STORE %q", store)
+	}
+}
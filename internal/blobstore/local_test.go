@@ -0,0 +1,97 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStorePutGetDelete(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "1/2/report.txt", strings.NewReader("hello"), 5, "text/plain"); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	// Повторный Put перезаписывает объект
+	if err := store.Put(ctx, "1/2/report.txt", strings.NewReader("bye"), 3, "text/plain"); err != nil {
+		t.Fatalf("overwrite: %v", err)
+	}
+	if got := readBlob(t, store, "1/2/report.txt"); got != "bye" {
+		t.Errorf("get: got %q, want %q", got, "bye")
+	}
+
+	if err := store.Delete(ctx, "1/2/report.txt"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := store.Get(ctx, "1/2/report.txt"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("get after delete: got %v, want %v", err, ErrNotFound)
+	}
+	if err := store.Delete(ctx, "1/2/report.txt"); err != nil {
+		t.Errorf("delete missing: got %v, want nil", err)
+	}
+}
+
+func TestLocalStoreShortBodyLeavesNothing(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewLocalStore(root)
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "a/blob", strings.NewReader("abc"), 10, ""); err == nil {
+		t.Fatal("put with short body: got nil error")
+	}
+	if _, err := store.Get(ctx, "a/blob"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("get: got %v, want %v", err, ErrNotFound)
+	}
+	entries, err := os.ReadDir(filepath.Join(root, "a"))
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("leftover files: got %d, want 0", len(entries))
+	}
+}
+
+func TestLocalStoreRejectsKeysOutsideRoot(t *testing.T) {
+	store, err := NewLocalStore(filepath.Join(t.TempDir(), "blobs"))
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	ctx := context.Background()
+
+	for _, key := range []string{"", "../escape", "a/../../escape", "/etc/passwd", "a/.upload-1"} {
+		if err := store.Put(ctx, key, strings.NewReader("x"), 1, ""); err == nil {
+			t.Errorf("put %q: got nil error", key)
+		}
+		if _, err := store.Get(ctx, key); err == nil || errors.Is(err, ErrNotFound) {
+			t.Errorf("get %q: got %v, want invalid key", key, err)
+		}
+		if err := store.Delete(ctx, key); err == nil {
+			t.Errorf("delete %q: got nil error", key)
+		}
+	}
+}
+
+func readBlob(t *testing.T, store BlobStore, key string) string {
+	t.Helper()
+	r, err := store.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("get %s: %v", key, err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read %s: %v", key, err)
+	}
+	return string(data)
+}
@@ -0,0 +1,54 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestS3StoreMinIO проверяет S3Store на живом MinIO. Запускается, только если
+// задан MINIO_ENDPOINT; бакет MINIO_BUCKET (по умолчанию test) должен существовать.
+func TestS3StoreMinIO(t *testing.T) {
+	endpoint := os.Getenv("MINIO_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("MINIO_ENDPOINT is not set")
+	}
+	bucket := os.Getenv("MINIO_BUCKET")
+	if bucket == "" {
+		bucket = "test"
+	}
+	store, err := NewS3Store(S3Config{
+		Endpoint:  endpoint,
+		Bucket:    bucket,
+		AccessKey: os.Getenv("MINIO_ACCESS_KEY"),
+		SecretKey: os.Getenv("MINIO_SECRET_KEY"),
+	}, nil)
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	ctx := context.Background()
+	// Ключ с пробелом и не-ASCII проверяет кодирование пути в подписи
+	key := fmt.Sprintf("blobstore-test/%d/отчет 1.txt", time.Now().UnixNano())
+	t.Cleanup(func() { _ = store.Delete(ctx, key) })
+
+	if err := store.Put(ctx, key, strings.NewReader("hello"), 5, "text/plain"); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if got := readBlob(t, store, key); got != "hello" {
+		t.Errorf("get: got %q, want %q", got, "hello")
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("get after delete: got %v, want %v", err, ErrNotFound)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Errorf("delete missing: got %v, want nil", err)
+	}
+}
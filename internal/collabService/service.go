@@ -0,0 +1,281 @@
+package collabService
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"newproject/internal/identity"
+	"newproject/internal/streamService"
+	"newproject/internal/taskService"
+	"newproject/internal/tenant"
+	"sort"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// sessionBuffer — сколько сообщений ждут участника, прежде чем его отключат
+const sessionBuffer = 64
+
+var (
+	// ErrUserNotFound — владельца списка нет в пространстве запроса
+	ErrUserNotFound = fmt.Errorf("user not found: %w", gorm.ErrRecordNotFound)
+	// ErrInvalidOp — неизвестная операция или пустое название задачи
+	ErrInvalidOp = errors.New("op must be rename with a non-empty task or toggle_done")
+	// ErrNotInList — задача принадлежит не владельцу открытого списка
+	ErrNotInList = errors.New("task is not in this task list")
+)
+
+// Виды операций участников
+const (
+	OpRename     = "rename"
+	OpToggleDone = "toggle_done"
+)
+
+// Виды сообщений участникам
+const (
+	MessagePresence = "presence"
+	MessageTask     = "task"
+)
+
+// TaskEditor — то, через что Service читает и меняет задачи. Права
+// проверяет он сам, поэтому операции участников подчиняются тем же правилам, что и API.
+type TaskEditor interface {
+	GetTaskByID(ctx context.Context, id uint) (taskService.Task, error)
+	UpdateTaskByID(ctx context.Context, id uint, task taskService.Task) (taskService.Task, error)
+}
+
+// UserLookup — то, что Service нужно знать о пользователях, чтобы открыть их список
+type UserLookup interface {
+	// UserExists сообщает, есть ли пользователь в пространстве из контекста
+	UserExists(ctx context.Context, id uint) (bool, error)
+}
+
+// Viewer — пользователь, открывший список, и число его подключений
+type Viewer struct {
+	UserID      uint
+	Connections int
+}
+
+// Op — правка задачи участником
+type Op struct {
+	Kind   string
+	TaskID uint
+	// Task — новое название для rename
+	Task string
+}
+
+// Message — то, что получает участник: состав зрителей (presence) или
+// изменение задачи из ленты (task), в том числе чужой операцией
+type Message struct {
+	Kind    string
+	Viewers []Viewer
+	Event   streamService.Event
+}
+
+// roomKey — список задач одного владельца в одном пространстве
+type roomKey struct {
+	workspaceID uint
+	ownerID     uint
+}
+
+// Service связывает открытые списки задач: раздает участникам изменения
+// задач, следит, кто смотрит список.
+type Service struct {
+	tasks TaskEditor
+	users UserLookup
+
+	mu    sync.Mutex
+	rooms map[roomKey]map[*Session]struct{}
+}
+
+func NewService(tasks TaskEditor, users UserLookup) *Service {
+	return &Service{
+		tasks: tasks,
+		users: users,
+		rooms: make(map[roomKey]map[*Session]struct{}),
+	}
+}
+
+// Session — участник, открывший список задач
+type Session struct {
+	// OwnerID — чей список открыт
+	OwnerID uint
+	// C — сообщения участнику. Канал закрывается после Leave или если участник
+	// не успевает их забирать: тогда Slow сообщает, что его отключили за отставание.
+	C <-chan Message
+
+	service *Service
+	ch      chan Message
+	ctx     context.Context
+	caller  identity.Caller
+	room    roomKey
+	closed  bool
+	slow    bool
+}
+
+// Join открывает список задач пользователя userID (по умолчанию вызывающего)
+// в пространстве из контекста. Открыть можно список любого участника
+// пространства, но видны в нем только задачи, доступные вызывающему.
+// ctx остается у сессии: от имени вызывающего выполняются его операции.
+func (s *Service) Join(ctx context.Context, userID *uint) (*Session, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	workspaceID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerID := caller.UserID
+	if userID != nil && *userID != caller.UserID {
+		exists, err := s.users.UserExists(ctx, *userID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, ErrUserNotFound
+		}
+		ownerID = *userID
+	}
+
+	ch := make(chan Message, sessionBuffer)
+	session := &Session{
+		OwnerID: ownerID,
+		C:       ch,
+		service: s,
+		ch:      ch,
+		ctx:     ctx,
+		caller:  caller,
+		room:    roomKey{workspaceID: workspaceID, ownerID: ownerID},
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room := s.rooms[session.room]
+	if room == nil {
+		room = make(map[*Session]struct{})
+		s.rooms[session.room] = room
+	}
+	room[session] = struct{}{}
+	s.announce(session.room)
+	return session, nil
+}
+
+// Leave закрывает сессию, остальные участники получают новый состав зрителей
+func (sess *Session) Leave() {
+	s := sess.service
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room := s.rooms[sess.room]
+	if _, ok := room[sess]; !ok {
+		return
+	}
+	delete(room, sess)
+	sess.close()
+	if len(room) == 0 {
+		delete(s.rooms, sess.room)
+		return
+	}
+	s.announce(sess.room)
+}
+
+// Slow сообщает, что сессию закрыли из-за отставания. Значение имеет смысл
+// после закрытия C.
+func (sess *Session) Slow() bool {
+	return sess.slow
+}
+
+// Apply выполняет операцию от имени участника и возвращает задачу после
+// правки. Остальные зрители узнают о ней из ленты изменений через Publish.
+func (sess *Session) Apply(op Op) (taskService.Task, error) {
+	name := strings.TrimSpace(op.Task)
+	if op.Kind != OpToggleDone && (op.Kind != OpRename || name == "") {
+		return taskService.Task{}, ErrInvalidOp
+	}
+
+	s := sess.service
+	existing, err := s.tasks.GetTaskByID(sess.ctx, op.TaskID)
+	if err != nil {
+		return taskService.Task{}, err
+	}
+	if existing.UserID != sess.OwnerID {
+		return taskService.Task{}, ErrNotInList
+	}
+
+	// Остальные поля правка оставляет прежними, как PATCH без них
+	change := taskService.Task{Task: existing.Task, IsDone: existing.IsDone}
+	if op.Kind == OpRename {
+		change.Task = name
+	} else {
+		change.IsDone = !existing.IsDone
+	}
+	return s.tasks.UpdateTaskByID(sess.ctx, op.TaskID, change)
+}
+
+// Publish раздает изменение задачи открытым спискам ее владельца и прежнего
+// владельца. Участнику оно приходит, только если задача ему видна.
+// Реализует слушателя streamService.Hub.Listen.
+func (s *Service) Publish(event streamService.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	owners := []uint{event.OwnerID}
+	if event.PreviousOwnerID != event.OwnerID {
+		owners = append(owners, event.PreviousOwnerID)
+	}
+	for _, ownerID := range owners {
+		if ownerID == 0 {
+			continue
+		}
+		for sess := range s.rooms[roomKey{workspaceID: event.WorkspaceID, ownerID: ownerID}] {
+			if sess.caller.IsAdmin() || event.Concerns(sess.caller.UserID) {
+				sess.send(Message{Kind: MessageTask, Event: event})
+			}
+		}
+	}
+}
+
+// announce рассылает участникам комнаты состав зрителей. Вызывается под s.mu.
+func (s *Service) announce(key roomKey) {
+	counts := make(map[uint]int)
+	for sess := range s.rooms[key] {
+		counts[sess.caller.UserID]++
+	}
+	viewers := make([]Viewer, 0, len(counts))
+	for userID, n := range counts {
+		viewers = append(viewers, Viewer{UserID: userID, Connections: n})
+	}
+	sort.Slice(viewers, func(i, j int) bool { return viewers[i].UserID < viewers[j].UserID })
+
+	for sess := range s.rooms[key] {
+		sess.send(Message{Kind: MessagePresence, Viewers: viewers})
+	}
+}
+
+// send отдает сообщение участнику, не дожидаясь его. Участник с переполненным
+// каналом отключается, чтобы медленный клиент не держал остальных.
+// Вызывается под s.mu.
+func (sess *Session) send(msg Message) {
+	if sess.closed {
+		return
+	}
+	select {
+	case sess.ch <- msg:
+	default:
+		sess.slow = true
+		sess.close()
+	}
+}
+
+// close закрывает канал сессии. Вызывается под s.mu.
+func (sess *Session) close() {
+	if !sess.closed {
+		sess.closed = true
+		close(sess.ch)
+	}
+}
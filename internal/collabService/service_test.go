@@ -0,0 +1,260 @@
+package collabService
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"newproject/internal/identity"
+	"newproject/internal/outbox"
+	"newproject/internal/streamService"
+	"newproject/internal/taskService"
+	"newproject/internal/tenant"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// taskList — задачи в памяти, права не проверяются
+type taskList map[uint]taskService.Task
+
+func (l taskList) GetTaskByID(_ context.Context, id uint) (taskService.Task, error) {
+	task, ok := l[id]
+	if !ok {
+		return taskService.Task{}, taskService.ErrTaskNotFound
+	}
+	return task, nil
+}
+
+func (l taskList) UpdateTaskByID(_ context.Context, id uint, task taskService.Task) (taskService.Task, error) {
+	existing, ok := l[id]
+	if !ok {
+		return taskService.Task{}, taskService.ErrTaskNotFound
+	}
+	existing.Task, existing.IsDone = task.Task, task.IsDone
+	l[id] = existing
+	return existing, nil
+}
+
+// members — пользователи пространства
+type members map[uint]bool
+
+func (m members) UserExists(_ context.Context, id uint) (bool, error) {
+	return m[id], nil
+}
+
+// audiences — владелец и участники задач для ленты
+type audiences map[uint][]uint
+
+func (a audiences) TaskAudience(_ context.Context, id uint) (uint, []uint, error) {
+	audience, ok := a[id]
+	if !ok {
+		return 0, nil, gorm.ErrRecordNotFound
+	}
+	return audience[0], audience[1:], nil
+}
+
+func callerContext(userID uint, role string) context.Context {
+	ctx := tenant.WithWorkspace(context.Background(), 1)
+	return identity.WithCaller(ctx, identity.Caller{UserID: userID, Role: "user", WorkspaceRole: role})
+}
+
+// join открывает список владельца ownerID от имени userID
+func join(t *testing.T, s *Service, userID, ownerID uint, role string) *Session {
+	t.Helper()
+	session, err := s.Join(callerContext(userID, role), &ownerID)
+	if err != nil {
+		t.Fatalf("join %d to %d: %v", userID, ownerID, err)
+	}
+	return session
+}
+
+// drain забирает сообщения, уже ждущие участника
+func drain(session *Session) []Message {
+	var messages []Message
+	for {
+		select {
+		case msg, ok := <-session.C:
+			if !ok {
+				return messages
+			}
+			messages = append(messages, msg)
+		default:
+			return messages
+		}
+	}
+}
+
+// publish отправляет в ленту изменение задачи taskID в пространстве workspaceID
+func publish(t *testing.T, hub *streamService.Hub, id, workspaceID, taskID uint) {
+	t.Helper()
+	err := hub.Publish(context.Background(), outbox.OutboxMessage{
+		ID:          id,
+		Event:       outbox.EventTaskUpdated,
+		WorkspaceID: &workspaceID,
+		Data:        fmt.Sprintf(`{"id":%d,"after":{"task":"x"}}`, taskID),
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("publish %d: %v", id, err)
+	}
+}
+
+func TestJoinAndLeave(t *testing.T) {
+	s := NewService(taskList{}, members{1: true, 2: true})
+	owner := join(t, s, 1, 1, "member")
+	guest := join(t, s, 2, 1, "member")
+	second := join(t, s, 2, 1, "member")
+
+	tests := []struct {
+		name    string
+		session *Session
+		want    string
+	}{
+		{name: "owner", session: owner, want: "[[{1 1}] [{1 1} {2 1}] [{1 1} {2 2}]]"},
+		{name: "guest", session: guest, want: "[[{1 1} {2 1}] [{1 1} {2 2}]]"},
+		{name: "second connection", session: second, want: "[[{1 1} {2 2}]]"},
+	}
+	for _, tt := range tests {
+		var got [][]Viewer
+		for _, msg := range drain(tt.session) {
+			if msg.Kind != MessagePresence {
+				t.Errorf("%s: got %s message, want presence", tt.name, msg.Kind)
+			}
+			got = append(got, msg.Viewers)
+		}
+		if fmt.Sprint(got) != tt.want {
+			t.Errorf("%s: got presence %v, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	second.Leave()
+	if _, ok := <-second.C; ok {
+		t.Errorf("left session: channel is still open")
+	}
+	if second.Slow() {
+		t.Errorf("left session: got slow, want a normal close")
+	}
+	second.Leave()
+	if got := drain(owner); len(got) != 1 || fmt.Sprint(got[0].Viewers) != "[{1 1} {2 1}]" {
+		t.Errorf("after leave: got %v, want presence [{1 1} {2 1}]", got)
+	}
+
+	other := uint(3)
+	if _, err := s.Join(callerContext(1, "member"), &other); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("unknown user: got %v, want %v", err, ErrUserNotFound)
+	}
+	if _, err := s.Join(tenant.WithWorkspace(context.Background(), 1), nil); !errors.Is(err, identity.ErrUnauthenticated) {
+		t.Errorf("anonymous: got %v, want %v", err, identity.ErrUnauthenticated)
+	}
+}
+
+func TestPublishReachesViewersWhoSeeTheTask(t *testing.T) {
+	s := NewService(taskList{}, members{1: true, 2: true, 3: true, 4: true})
+	// Задача 10 пользователя 1, пользователь 2 — ее исполнитель
+	hub := streamService.NewHub(audiences{10: {1, 2}}, 8)
+	hub.Listen(s.Publish)
+
+	sessions := map[string]*Session{
+		"owner":           join(t, s, 1, 1, "member"),
+		"assignee":        join(t, s, 2, 1, "member"),
+		"stranger":        join(t, s, 3, 1, "member"),
+		"workspace admin": join(t, s, 4, 1, "admin"),
+		"other list":      join(t, s, 2, 2, "member"),
+	}
+	for _, session := range sessions {
+		drain(session)
+	}
+	publish(t, hub, 1, 1, 10)
+	// То же изменение в другом пространстве списку из пространства 1 не приходит
+	publish(t, hub, 2, 2, 10)
+
+	tests := []struct {
+		name string
+		want int
+	}{
+		{name: "owner", want: 1},
+		{name: "assignee", want: 1},
+		{name: "stranger"},
+		{name: "workspace admin", want: 1},
+		{name: "other list"},
+	}
+	for _, tt := range tests {
+		got := drain(sessions[tt.name])
+		if len(got) != tt.want {
+			t.Errorf("%s: got %d messages, want %d", tt.name, len(got), tt.want)
+			continue
+		}
+		if tt.want > 0 && (got[0].Kind != MessageTask || got[0].Event.ID != 1) {
+			t.Errorf("%s: got %+v, want task event 1", tt.name, got[0])
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	tasks := taskList{
+		10: {Task: "old", UserID: 1},
+		20: {Task: "foreign", UserID: 5},
+	}
+	s := NewService(tasks, members{1: true, 2: true})
+	owner := join(t, s, 1, 1, "member")
+	viewer := join(t, s, 2, 1, "admin")
+	drain(owner)
+	drain(viewer)
+
+	tests := []struct {
+		name     string
+		op       Op
+		wantTask string
+		wantDone bool
+		wantErr  error
+	}{
+		{name: "rename", op: Op{Kind: OpRename, TaskID: 10, Task: " new "}, wantTask: "new"},
+		{name: "toggle", op: Op{Kind: OpToggleDone, TaskID: 10}, wantTask: "new", wantDone: true},
+		{name: "empty name", op: Op{Kind: OpRename, TaskID: 10, Task: " "}, wantErr: ErrInvalidOp},
+		{name: "unknown op", op: Op{Kind: "delete", TaskID: 10}, wantErr: ErrInvalidOp},
+		{name: "task of another list", op: Op{Kind: OpToggleDone, TaskID: 20}, wantErr: ErrNotInList},
+		{name: "unknown task", op: Op{Kind: OpToggleDone, TaskID: 30}, wantErr: taskService.ErrTaskNotFound},
+	}
+	for _, tt := range tests {
+		got, err := owner.Apply(tt.op)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (got.Task != tt.wantTask || got.IsDone != tt.wantDone) {
+			t.Errorf("%s: got %q done %v, want %q done %v", tt.name, got.Task, got.IsDone, tt.wantTask, tt.wantDone)
+		}
+	}
+	// Остальные зрители узнают о правке из ленты, а не от Apply
+	if got := drain(viewer); len(got) != 0 {
+		t.Errorf("viewer: got %v, want no messages", got)
+	}
+}
+
+func TestSlowSessionIsDisconnected(t *testing.T) {
+	s := NewService(taskList{}, members{1: true, 2: true})
+	hub := streamService.NewHub(audiences{10: {1}}, 8)
+	hub.Listen(s.Publish)
+
+	slow := join(t, s, 1, 1, "member")
+	fast := join(t, s, 1, 1, "member")
+	for id := uint(1); id <= sessionBuffer+1; id++ {
+		drain(fast)
+		publish(t, hub, id, 1, 10)
+	}
+
+	// Первое сообщение — состав зрителей, за ним события до переполнения канала
+	got := drain(slow)
+	if len(got) != sessionBuffer {
+		t.Errorf("slow: got %d messages, want %d", len(got), sessionBuffer)
+	}
+	if _, ok := <-slow.C; ok || !slow.Slow() {
+		t.Errorf("slow: got open %v, slow %v, want closed for being slow", ok, slow.Slow())
+	}
+	if fast.Slow() {
+		t.Errorf("fast: got slow, want connected")
+	}
+	slow.Leave()
+	fast.Leave()
+}
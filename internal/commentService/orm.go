@@ -0,0 +1,27 @@
+package commentService
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Comment — комментарий к задаче. Текст хранится в markdown как есть,
+// отрисовка остается клиенту.
+type Comment struct {
+	gorm.Model
+	TaskID   uint   `gorm:"index" json:"task_id"`
+	AuthorID uint   `gorm:"index" json:"author_id"`
+	Body     string `gorm:"type:text" json:"body"`
+	// EditedAt — время последней правки, nil у комментариев без правок
+	EditedAt *time.Time    `json:"edited_at"`
+	Edits    []CommentEdit `gorm:"foreignKey:CommentID" json:"edits"`
+}
+
+// CommentEdit — предыдущая версия комментария, сохраняется при каждой правке
+type CommentEdit struct {
+	ID        uint      `gorm:"primaryKey" json:"-"`
+	CommentID uint      `gorm:"index" json:"-"`
+	Body      string    `gorm:"type:text" json:"-"`
+	EditedAt  time.Time `json:"edited_at"`
+}
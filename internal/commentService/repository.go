@@ -0,0 +1,75 @@
+package commentService
+
+import (
+	"context"
+	"newproject/internal/pagination"
+
+	"gorm.io/gorm"
+)
+
+type CommentRepository interface {
+	CreateComment(ctx context.Context, comment Comment) (Comment, error)
+	GetComments(ctx context.Context, taskID uint, page pagination.Page) ([]Comment, string, error)
+	GetCommentByID(ctx context.Context, id uint) (Comment, error)
+	UpdateComment(ctx context.Context, comment Comment, edit CommentEdit) (Comment, error)
+	DeleteCommentByID(ctx context.Context, id uint) error
+}
+
+type commentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) *commentRepository {
+	return &commentRepository{db: db}
+}
+
+func (r *commentRepository) CreateComment(ctx context.Context, comment Comment) (Comment, error) {
+	err := r.db.WithContext(ctx).Create(&comment).Error
+	return comment, err
+}
+
+func (r *commentRepository) GetComments(ctx context.Context, taskID uint, page pagination.Page) ([]Comment, string, error) {
+	var comments []Comment
+	err := pagination.Apply(r.db.WithContext(ctx).Where("task_id = ?", taskID), page).
+		Preload("Edits", withEditOrder).
+		Find(&comments).Error
+	if err != nil {
+		return nil, "", err
+	}
+	comments, next := pagination.Trim(comments, page, func(c Comment) pagination.Cursor {
+		return page.CursorFor(c.CreatedAt, c.ID)
+	})
+	return comments, next, nil
+}
+
+func (r *commentRepository) GetCommentByID(ctx context.Context, id uint) (Comment, error) {
+	var comment Comment
+	err := r.db.WithContext(ctx).Preload("Edits", withEditOrder).First(&comment, id).Error
+	return comment, err
+}
+
+// UpdateComment сохраняет новый текст и предыдущую версию в одной транзакции
+func (r *commentRepository) UpdateComment(ctx context.Context, comment Comment, edit CommentEdit) (Comment, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&edit).Error; err != nil {
+			return err
+		}
+		return tx.Model(&comment).Updates(map[string]any{
+			"body":      comment.Body,
+			"edited_at": comment.EditedAt,
+		}).Error
+	})
+	if err != nil {
+		return Comment{}, err
+	}
+	return r.GetCommentByID(ctx, comment.ID)
+}
+
+// DeleteCommentByID помечает комментарий удаленным, история правок остается
+func (r *commentRepository) DeleteCommentByID(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&Comment{}, id).Error
+}
+
+func withEditOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("edited_at, id")
+}
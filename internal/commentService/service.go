@@ -0,0 +1,134 @@
+package commentService
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"newproject/internal/identity"
+	"newproject/internal/pagination"
+	"newproject/internal/taskService"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// MaxBodyLength — предельная длина комментария в символах
+const MaxBodyLength = 10000
+
+var (
+	// ErrCommentNotFound возвращается и для комментариев чужих задач
+	ErrCommentNotFound = fmt.Errorf("comment not found: %w", gorm.ErrRecordNotFound)
+	// ErrInvalidBody — пустой или слишком длинный текст комментария
+	ErrInvalidBody = fmt.Errorf("comment body must be 1 to %d characters", MaxBodyLength)
+	// ErrNotAuthor — править комментарий может только его автор
+	ErrNotAuthor = errors.New("only the author can edit a comment")
+	// ErrForbidden — удалить комментарий может автор или администратор
+	ErrForbidden = errors.New("forbidden")
+)
+
+type CommentService struct {
+	repo        CommentRepository
+	taskService *taskService.TaskService
+}
+
+func NewCommentService(repo CommentRepository, taskService *taskService.TaskService) *CommentService {
+	return &CommentService{repo: repo, taskService: taskService}
+}
+
+// CreateComment добавляет комментарий к задаче. Комментировать может
+// каждый, кому задача доступна: владелец, исполнители, наблюдатели
+// и администратор.
+func (s *CommentService) CreateComment(ctx context.Context, taskID uint, body string) (Comment, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return Comment{}, err
+	}
+	if _, err := s.taskService.GetTaskByID(ctx, taskID); err != nil {
+		return Comment{}, err
+	}
+
+	body, err = validBody(body)
+	if err != nil {
+		return Comment{}, err
+	}
+	return s.repo.CreateComment(ctx, Comment{TaskID: taskID, AuthorID: caller.UserID, Body: body})
+}
+
+// GetComments возвращает страницу комментариев задачи от старых к новым
+func (s *CommentService) GetComments(ctx context.Context, taskID uint, page pagination.Page) ([]Comment, string, error) {
+	if _, err := s.taskService.GetTaskByID(ctx, taskID); err != nil {
+		return nil, "", err
+	}
+	return s.repo.GetComments(ctx, taskID, page)
+}
+
+// UpdateComment меняет текст комментария, прежний текст уходит в историю правок
+func (s *CommentService) UpdateComment(ctx context.Context, taskID, id uint, body string) (Comment, error) {
+	caller, comment, err := s.getComment(ctx, taskID, id)
+	if err != nil {
+		return Comment{}, err
+	}
+	if comment.AuthorID != caller.UserID {
+		return Comment{}, ErrNotAuthor
+	}
+
+	body, err = validBody(body)
+	if err != nil {
+		return Comment{}, err
+	}
+	if body == comment.Body {
+		return comment, nil
+	}
+
+	now := time.Now()
+	edit := CommentEdit{CommentID: comment.ID, Body: comment.Body, EditedAt: now}
+	comment.Body = body
+	comment.EditedAt = &now
+	return s.repo.UpdateComment(ctx, comment, edit)
+}
+
+// DeleteComment удаляет комментарий. Удалить может автор или администратор.
+func (s *CommentService) DeleteComment(ctx context.Context, taskID, id uint) error {
+	caller, comment, err := s.getComment(ctx, taskID, id)
+	if err != nil {
+		return err
+	}
+	if comment.AuthorID != caller.UserID && !caller.IsAdmin() {
+		return ErrForbidden
+	}
+	return s.repo.DeleteCommentByID(ctx, id)
+}
+
+// getComment проверяет доступ к задаче и загружает ее комментарий
+func (s *CommentService) getComment(ctx context.Context, taskID, id uint) (identity.Caller, Comment, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return identity.Caller{}, Comment{}, err
+	}
+	if _, err := s.taskService.GetTaskByID(ctx, taskID); err != nil {
+		return identity.Caller{}, Comment{}, err
+	}
+
+	comment, err := s.repo.GetCommentByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return identity.Caller{}, Comment{}, ErrCommentNotFound
+	} else if err != nil {
+		return identity.Caller{}, Comment{}, err
+	}
+
+	if comment.TaskID != taskID {
+		return identity.Caller{}, Comment{}, ErrCommentNotFound
+	}
+	return caller, comment, nil
+}
+
+// validBody обрезает пробелы по краям и проверяет длину текста
+func validBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > MaxBodyLength {
+		return "", ErrInvalidBody
+	}
+	return body, nil
+}
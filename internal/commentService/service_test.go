@@ -0,0 +1,94 @@
+package commentService
+
+import (
+	"context"
+	"errors"
+	"newproject/internal/identity"
+	"newproject/internal/taskService"
+	"newproject/internal/tenant"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type noProjects struct{}
+
+func (noProjects) ProjectOwner(context.Context, uint) (uint, bool, error) {
+	return 0, false, gorm.ErrRecordNotFound
+}
+
+type anyUser struct{}
+
+func (anyUser) UserExists(context.Context, uint) (bool, error) {
+	return true, nil
+}
+
+// newTestService создает сервисы задач и комментариев на SQLite
+func newTestService(t *testing.T) (*CommentService, *taskService.TaskService) {
+	t.Helper()
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_txlock=immediate&_busy_timeout=5000"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.Use(tenant.Plugin{}); err != nil {
+		t.Fatalf("tenant plugin: %v", err)
+	}
+	err = db.AutoMigrate(&taskService.TaskStatus{}, &taskService.Task{}, &taskService.TaskDependency{},
+		&taskService.TaskAssignee{}, &taskService.TaskEvent{}, &taskService.Tag{}, &taskService.TaskSeries{}, &Comment{}, &CommentEdit{})
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	tasks := taskService.NewTaskService(taskService.NewTaskRepository(db), noProjects{}, anyUser{}, taskService.CompletionBlock)
+	return NewCommentService(NewCommentRepository(db), tasks), tasks
+}
+
+// callerContext — контекст запроса в пространстве 1 с ролью role в нем
+func callerContext(userID uint, role string) context.Context {
+	ctx := tenant.WithWorkspace(context.Background(), 1)
+	return identity.WithCaller(ctx, identity.Caller{UserID: userID, Role: "user", WorkspaceRole: role})
+}
+
+func TestCreateCommentRights(t *testing.T) {
+	comments, tasks := newTestService(t)
+	owner := callerContext(1, "member")
+
+	task, err := tasks.CreateTask(owner, taskService.Task{Task: "task"})
+	if err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	if _, err := tasks.Assign(owner, task.ID, 2, taskService.RoleAssignee); err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+	if _, err := tasks.Assign(owner, task.ID, 3, taskService.RoleWatcher); err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+	}{
+		{name: "owner", ctx: owner},
+		{name: "assignee", ctx: callerContext(2, "member")},
+		{name: "watcher", ctx: callerContext(3, "member")},
+		{name: "workspace admin", ctx: callerContext(5, "admin")},
+		{name: "workspace owner", ctx: callerContext(6, "owner")},
+		{name: "stranger", ctx: callerContext(4, "member"), wantErr: taskService.ErrTaskNotFound},
+		{name: "platform admin as a member", ctx: identity.WithCaller(tenant.WithWorkspace(context.Background(), 1), identity.Caller{UserID: 7, Role: "admin", WorkspaceRole: "member"}), wantErr: taskService.ErrTaskNotFound},
+		{name: "anonymous", ctx: tenant.WithWorkspace(context.Background(), 1), wantErr: identity.ErrUnauthenticated},
+	}
+	for _, tt := range tests {
+		comment, err := comments.CreateComment(tt.ctx, task.ID, "hello from "+tt.name)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && comment.TaskID != task.ID {
+			t.Errorf("%s: got comment on task %d, want %d", tt.name, comment.TaskID, task.ID)
+		}
+	}
+}
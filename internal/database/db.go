@@ -12,7 +12,7 @@ import (
 type User struct {
 	ID       uint   `gorm:"primaryKey"`
 	Name     string `gorm:"not null"`
-	Email    string `gorm:"not null;unique"`
+	Email    string `gorm:"not null"` // уникальность среди неудаленных задает миграция
 	Password string `gorm:"not null"`
 }
 
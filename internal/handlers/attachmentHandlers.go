@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"newproject/internal/attachmentService"
+	"newproject/internal/identity"
+	"newproject/internal/taskService"
+	openapi "newproject/internal/web/attachments"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type AttachmentHandler struct {
+	attachmentService *attachmentService.AttachmentService
+}
+
+func NewAttachmentHandler(attachmentService *attachmentService.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{attachmentService: attachmentService}
+}
+
+// GetTasksIdAttachments возвращает вложения задачи
+func (h *AttachmentHandler) GetTasksIdAttachments(ctx context.Context, id int64) (openapi.AttachmentList, error) {
+	attachments, err := h.attachmentService.GetAttachments(ctx, uint(id))
+	if err != nil {
+		return openapi.AttachmentList{}, attachmentError(err, "error fetching attachments")
+	}
+
+	items := make([]openapi.Attachment, 0, len(attachments))
+	for _, a := range attachments {
+		items = append(items, toAttachmentResponse(a))
+	}
+	return openapi.AttachmentList{Items: items}, nil
+}
+
+// PostTasksIdAttachments загружает файл к задаче
+func (h *AttachmentHandler) PostTasksIdAttachments(ctx context.Context, id int64, req openapi.PostTasksIdAttachmentsMultipartBody) (openapi.Attachment, error) {
+	if req.File == nil {
+		return openapi.Attachment{}, echo.NewHTTPError(http.StatusBadRequest, "file is required")
+	}
+	// Размер из заголовка части позволяет отказать, не читая файл
+	if req.File.Size > h.attachmentService.MaxSize() {
+		return openapi.Attachment{}, attachmentError(attachmentService.ErrTooLarge, "")
+	}
+
+	file, err := req.File.Open()
+	if err != nil {
+		return openapi.Attachment{}, fmt.Errorf("error opening uploaded file: %w", err)
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentService.Upload(ctx, uint(id), req.File.Filename, file)
+	if err != nil {
+		log.Printf("Error uploading attachment to task %d: %v", id, err)
+		return openapi.Attachment{}, attachmentError(err, "error uploading attachment")
+	}
+	return toAttachmentResponse(attachment), nil
+}
+
+// GetTasksIdAttachmentsAttachmentId отдает содержимое вложения
+func (h *AttachmentHandler) GetTasksIdAttachmentsAttachmentId(ctx context.Context, id int64, attachmentId int64) (openapi.GetTasksIdAttachmentsAttachmentId200Response, error) {
+	attachment, body, err := h.attachmentService.Open(ctx, uint(id), uint(attachmentId))
+	if err != nil {
+		log.Printf("Error opening attachment with ID %d: %v", attachmentId, err)
+		return openapi.GetTasksIdAttachmentsAttachmentId200Response{}, attachmentError(err, "error downloading attachment")
+	}
+	return openapi.GetTasksIdAttachmentsAttachmentId200Response{
+		Body:          body,
+		ContentType:   attachment.ContentType,
+		ContentLength: attachment.Size,
+		FileName:      attachment.FileName,
+		Checksum:      attachment.Checksum,
+	}, nil
+}
+
+// DeleteTasksIdAttachmentsAttachmentId удаляет вложение
+func (h *AttachmentHandler) DeleteTasksIdAttachmentsAttachmentId(ctx context.Context, id int64, attachmentId int64) error {
+	if err := h.attachmentService.DeleteAttachment(ctx, uint(id), uint(attachmentId)); err != nil {
+		return attachmentError(err, "error deleting attachment")
+	}
+	return nil
+}
+
+// toAttachmentResponse преобразует attachmentService.Attachment в openapi.Attachment
+func toAttachmentResponse(a attachmentService.Attachment) openapi.Attachment {
+	return openapi.Attachment{
+		Id:          int64(a.ID),
+		TaskId:      int64(a.TaskID),
+		UserId:      int64(a.UserID),
+		FileName:    a.FileName,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		Checksum:    a.Checksum,
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
+// attachmentError переводит ошибки AttachmentService в HTTP-ошибки
+func attachmentError(err error, message string) error {
+	switch {
+	case errors.Is(err, identity.ErrUnauthenticated):
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	case errors.Is(err, attachmentService.ErrAttachmentNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "attachment not found")
+	case errors.Is(err, taskService.ErrTaskNotFound), errors.Is(err, gorm.ErrRecordNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "task not found")
+	case errors.Is(err, attachmentService.ErrTooLarge):
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, attachmentService.ErrEmptyFile), errors.Is(err, attachmentService.ErrInvalidFileName):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return fmt.Errorf("%s: %w", message, err)
+	}
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"newproject/internal/auditService"
+	"newproject/internal/pagination"
+	"newproject/internal/policy"
+	openapi "newproject/internal/web/audit"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AuditHandler struct {
+	auditService *auditService.AuditService
+	policy       *policy.Policy
+}
+
+func NewAuditHandler(auditService *auditService.AuditService, policy *policy.Policy) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+		policy:       policy,
+	}
+}
+
+// GetAudit возвращает журнал изменений рабочего пространства, новые записи первыми
+func (h *AuditHandler) GetAudit(ctx context.Context, params openapi.GetAuditParams) (openapi.AuditPage, error) {
+	if err := h.policy.CanViewAudit(ctx); err != nil {
+		return openapi.AuditPage{}, policyError(err)
+	}
+
+	page, err := pagination.NewPage(params.Limit, params.Cursor, pagination.ByNewest)
+	if err != nil {
+		return openapi.AuditPage{}, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	filter := auditService.EntryFilter{From: params.From, To: params.To}
+	if params.Entity != nil {
+		filter.Entity = string(*params.Entity)
+	}
+	if params.EntityId != nil {
+		entityID := uint(*params.EntityId)
+		filter.EntityID = &entityID
+	}
+	if params.ActorId != nil {
+		actorID := uint(*params.ActorId)
+		filter.ActorID = &actorID
+	}
+
+	entries, next, err := h.auditService.GetEntries(ctx, filter, page)
+	if errors.Is(err, auditService.ErrInvalidEntity) || errors.Is(err, auditService.ErrInvalidRange) {
+		return openapi.AuditPage{}, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	} else if err != nil {
+		return openapi.AuditPage{}, fmt.Errorf("error fetching audit log: %w", err)
+	}
+
+	items := make([]openapi.AuditEntry, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, toAuditEntryResponse(e))
+	}
+	return openapi.AuditPage{Items: items, NextCursor: cursorPtr(next)}, nil
+}
+
+func toAuditEntryResponse(e auditService.AuditEntry) openapi.AuditEntry {
+	resp := openapi.AuditEntry{
+		Id:        int64(e.ID),
+		Action:    openapi.AuditAction(e.Action),
+		Entity:    openapi.AuditEntity(e.Entity),
+		EntityId:  int64(e.EntityID),
+		Before:    jsonObject(e.Before),
+		After:     jsonObject(e.After),
+		RequestId: e.RequestID,
+		CreatedAt: e.CreatedAt,
+	}
+	if e.ActorID != nil {
+		resp.ActorId = int64Ptr(int64(*e.ActorID))
+	}
+	if e.WorkspaceID != nil {
+		resp.WorkspaceId = int64Ptr(int64(*e.WorkspaceID))
+	}
+	return resp
+}
+
+// jsonObject разбирает сохраненный JSON-объект, nil остается nil
+func jsonObject(raw *string) *map[string]interface{} {
+	if raw == nil {
+		return nil
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(*raw), &object); err != nil {
+		return nil
+	}
+	return &object
+}
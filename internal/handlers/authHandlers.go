@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"newproject/internal/authService"
+	"newproject/internal/userService"
+	openapi "newproject/internal/web/auth"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AuthHandler struct {
+	authService *authService.AuthService
+}
+
+func NewAuthHandler(authService *authService.AuthService) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+	}
+}
+
+// PostAuthLogin выдает пару токенов по email и паролю
+func (h *AuthHandler) PostAuthLogin(ctx context.Context, req openapi.LoginRequest) (openapi.TokenResponse, error) {
+	if req.Email == "" || req.Password == "" {
+		return openapi.TokenResponse{}, echo.NewHTTPError(http.StatusBadRequest, "email and password are required")
+	}
+
+	tokens, err := h.authService.Login(ctx, req.Email, req.Password)
+	if errors.Is(err, userService.ErrInvalidCredentials) {
+		return openapi.TokenResponse{}, echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	} else if err != nil {
+		log.Printf("Error logging in: %v", err)
+		return openapi.TokenResponse{}, fmt.Errorf("error logging in: %w", err)
+	}
+
+	return toTokenResponse(tokens), nil
+}
+
+// PostAuthRefresh меняет refresh-токен на новую пару токенов
+func (h *AuthHandler) PostAuthRefresh(ctx context.Context, req openapi.RefreshRequest) (openapi.TokenResponse, error) {
+	if req.RefreshToken == "" {
+		return openapi.TokenResponse{}, echo.NewHTTPError(http.StatusBadRequest, "refresh_token is required")
+	}
+
+	tokens, err := h.authService.Refresh(ctx, req.RefreshToken)
+	if errors.Is(err, authService.ErrInvalidToken) {
+		return openapi.TokenResponse{}, echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	} else if err != nil {
+		log.Printf("Error refreshing tokens: %v", err)
+		return openapi.TokenResponse{}, fmt.Errorf("error refreshing tokens: %w", err)
+	}
+
+	return toTokenResponse(tokens), nil
+}
+
+// PostAuthLogout отзывает refresh-токен
+func (h *AuthHandler) PostAuthLogout(ctx context.Context, req openapi.RefreshRequest) error {
+	if req.RefreshToken == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "refresh_token is required")
+	}
+
+	if err := h.authService.Logout(req.RefreshToken); err != nil {
+		log.Printf("Error logging out: %v", err)
+		return fmt.Errorf("error logging out: %w", err)
+	}
+	return nil
+}
+
+func toTokenResponse(tokens authService.TokenPair) openapi.TokenResponse {
+	return openapi.TokenResponse{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(time.Until(tokens.ExpiresAt).Seconds()),
+	}
+}
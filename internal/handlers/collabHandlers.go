@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"newproject/internal/collabService"
+	"newproject/internal/identity"
+	"newproject/internal/taskService"
+	"newproject/internal/tenant"
+	openapi "newproject/internal/web/collab"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// collabWriteWait ограничивает запись одного сообщения клиенту
+	collabWriteWait = 10 * time.Second
+	// collabPongWait — сколько ждать ответа на ping, прежде чем считать клиента пропавшим
+	collabPongWait = 60 * time.Second
+	// collabPingPeriod — как часто пинговать клиента, меньше collabPongWait
+	collabPingPeriod = 30 * time.Second
+	// collabMaxOpSize — предел размера операции клиента
+	collabMaxOpSize = 4 << 10
+)
+
+type CollabHandler struct {
+	collab   *collabService.Service
+	upgrader websocket.Upgrader
+}
+
+// NewCollabHandler создает обработчик совместной работы над списками задач.
+// Upgrader по умолчанию принимает только запросы со своего origin.
+func NewCollabHandler(collab *collabService.Service) *CollabHandler {
+	return &CollabHandler{
+		collab: collab,
+	}
+}
+
+// GetCollabTasks открывает WebSocket со списком задач пользователя. Права
+// проверяются до переключения протокола, чтобы ошибка ушла обычным HTTP-ответом.
+func (h *CollabHandler) GetCollabTasks(ctx echo.Context, params openapi.GetCollabTasksParams) error {
+	if !websocket.IsWebSocketUpgrade(ctx.Request()) {
+		return echo.NewHTTPError(http.StatusBadRequest, "websocket handshake expected")
+	}
+	var userID *uint
+	if params.UserId != nil {
+		id := uint(*params.UserId)
+		userID = &id
+	}
+
+	session, err := h.collab.Join(ctx.Request().Context(), userID)
+	if err != nil {
+		return collabError(err)
+	}
+	defer session.Leave()
+
+	conn, err := h.upgrader.Upgrade(ctx.Response(), ctx.Request(), nil)
+	if err != nil {
+		// Upgrader уже ответил клиенту
+		return nil
+	}
+	defer conn.Close()
+
+	// Операции читаются в своей горутине, ответы на них отправляет та же
+	// горутина, что и остальные сообщения: писать в соединение может только одна.
+	replies := make(chan openapi.CollabServerMessage)
+	readDone := make(chan struct{})
+	writeDone := make(chan struct{})
+	go func() {
+		defer close(readDone)
+		h.readOps(conn, session, replies, writeDone)
+	}()
+
+	h.writeMessages(conn, session, replies, readDone)
+	close(writeDone)
+	conn.Close()
+	<-readDone
+	return nil
+}
+
+// readOps выполняет операции клиента, пока он не отключится
+func (h *CollabHandler) readOps(conn *websocket.Conn, session *collabService.Session, replies chan<- openapi.CollabServerMessage, writeDone <-chan struct{}) {
+	conn.SetReadLimit(collabMaxOpSize)
+	_ = conn.SetReadDeadline(time.Now().Add(collabPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(collabPongWait))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var msg openapi.CollabClientMessage
+		var reply openapi.CollabServerMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			reply = openapi.CollabServerMessage{Type: openapi.CollabServerMessageTypeError, Message: stringPtr("invalid message: " + err.Error())}
+		} else {
+			reply = applyCollabOp(session, msg)
+		}
+
+		select {
+		case replies <- reply:
+		case <-writeDone:
+			return
+		}
+	}
+}
+
+// writeMessages отправляет клиенту сообщения сессии и ответы на его операции
+// и пингует его. Клиента, который не успевает читать, отключает с кодом 1013.
+func (h *CollabHandler) writeMessages(conn *websocket.Conn, session *collabService.Session, replies <-chan openapi.CollabServerMessage, readDone <-chan struct{}) {
+	ticker := time.NewTicker(collabPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		var err error
+		select {
+		case msg, ok := <-session.C:
+			if !ok {
+				code, reason := websocket.CloseNormalClosure, ""
+				if session.Slow() {
+					code, reason = websocket.CloseTryAgainLater, "client is too slow"
+				}
+				_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(collabWriteWait))
+				return
+			}
+			err = writeCollabMessage(conn, toCollabMessageResponse(msg))
+		case reply := <-replies:
+			err = writeCollabMessage(conn, reply)
+		case <-ticker.C:
+			err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(collabWriteWait))
+		case <-readDone:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func writeCollabMessage(conn *websocket.Conn, msg openapi.CollabServerMessage) error {
+	_ = conn.SetWriteDeadline(time.Now().Add(collabWriteWait))
+	return conn.WriteJSON(msg)
+}
+
+// applyCollabOp выполняет операцию клиента и готовит ответ ack или error
+func applyCollabOp(session *collabService.Session, msg openapi.CollabClientMessage) openapi.CollabServerMessage {
+	op := collabService.Op{Kind: string(msg.Type), TaskID: uint(msg.TaskId)}
+	if msg.Task != nil {
+		op.Task = *msg.Task
+	}
+
+	task, err := session.Apply(op)
+	if err != nil {
+		return openapi.CollabServerMessage{Type: openapi.CollabServerMessageTypeError, OpId: msg.OpId, Message: stringPtr(collabOpError(err))}
+	}
+	resp := toCollabTaskResponse(task)
+	return openapi.CollabServerMessage{Type: openapi.CollabServerMessageTypeAck, OpId: msg.OpId, Task: &resp}
+}
+
+func toCollabMessageResponse(msg collabService.Message) openapi.CollabServerMessage {
+	resp := openapi.CollabServerMessage{Type: openapi.CollabServerMessageType(msg.Kind)}
+	switch msg.Kind {
+	case collabService.MessagePresence:
+		viewers := make([]openapi.CollabViewer, 0, len(msg.Viewers))
+		for _, v := range msg.Viewers {
+			viewers = append(viewers, openapi.CollabViewer{UserId: int64(v.UserID), Connections: v.Connections})
+		}
+		resp.Viewers = &viewers
+	case collabService.MessageTask:
+		event := toStreamEventResponse(msg.Event)
+		resp.Event = &openapi.TaskStreamEvent{
+			Id:         event.Id,
+			Event:      openapi.TaskStreamEventEvent(event.Event),
+			TaskId:     event.TaskId,
+			ActorId:    event.ActorId,
+			OccurredAt: event.OccurredAt,
+			Before:     event.Before,
+			After:      event.After,
+		}
+	}
+	return resp
+}
+
+func toCollabTaskResponse(t taskService.Task) openapi.CollabTask {
+	resp := openapi.CollabTask{Id: int64(t.ID), Task: t.Task, IsDone: t.IsDone, UserId: int64(t.UserID)}
+	if t.StatusID != nil {
+		resp.StatusId = int64Ptr(int64(*t.StatusID))
+	}
+	return resp
+}
+
+// collabError переводит ошибки открытия списка в HTTP-ошибки
+func collabError(err error) error {
+	switch {
+	case errors.Is(err, identity.ErrUnauthenticated):
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	case errors.Is(err, tenant.ErrNoWorkspace):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, collabService.ErrUserNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "user not found")
+	default:
+		return fmt.Errorf("error opening task list: %w", err)
+	}
+}
+
+// collabOpError возвращает текст ошибки операции для клиента. Ошибки задач
+// описываются так же, как в ответах API, внутренние — не раскрываются.
+func collabOpError(err error) string {
+	if errors.Is(err, collabService.ErrInvalidOp) || errors.Is(err, collabService.ErrNotInList) {
+		return err.Error()
+	}
+	var httpErr *echo.HTTPError
+	if errors.As(taskError(err, "error applying op"), &httpErr) {
+		return fmt.Sprint(httpErr.Message)
+	}
+	log.Printf("Error applying collab op: %v", err)
+	return "internal error"
+}
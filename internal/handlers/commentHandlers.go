@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"newproject/internal/commentService"
+	"newproject/internal/identity"
+	"newproject/internal/pagination"
+	"newproject/internal/taskService"
+	openapi "newproject/internal/web/comments"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type CommentHandler struct {
+	commentService *commentService.CommentService
+}
+
+func NewCommentHandler(commentService *commentService.CommentService) *CommentHandler {
+	return &CommentHandler{commentService: commentService}
+}
+
+// GetTasksIdComments возвращает страницу комментариев задачи
+func (h *CommentHandler) GetTasksIdComments(ctx context.Context, id int64, params openapi.GetTasksIdCommentsParams) (openapi.CommentPage, error) {
+	page, err := pagination.NewPage(params.Limit, params.Cursor, pagination.ByCreatedAt)
+	if err != nil {
+		return openapi.CommentPage{}, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	comments, next, err := h.commentService.GetComments(ctx, uint(id), page)
+	if err != nil {
+		return openapi.CommentPage{}, commentError(err, "error fetching comments")
+	}
+
+	items := make([]openapi.Comment, 0, len(comments))
+	for _, c := range comments {
+		items = append(items, toCommentResponse(c))
+	}
+	return openapi.CommentPage{Items: items, NextCursor: cursorPtr(next)}, nil
+}
+
+// PostTasksIdComments добавляет комментарий к задаче
+func (h *CommentHandler) PostTasksIdComments(ctx context.Context, id int64, req openapi.CommentRequest) (openapi.Comment, error) {
+	comment, err := h.commentService.CreateComment(ctx, uint(id), req.Body)
+	if err != nil {
+		log.Printf("Error creating comment on task %d: %v", id, err)
+		return openapi.Comment{}, commentError(err, "error creating comment")
+	}
+	return toCommentResponse(comment), nil
+}
+
+// PatchTasksIdCommentsCommentId правит текст комментария
+func (h *CommentHandler) PatchTasksIdCommentsCommentId(ctx context.Context, id int64, commentId int64, req openapi.CommentRequest) (openapi.Comment, error) {
+	comment, err := h.commentService.UpdateComment(ctx, uint(id), uint(commentId), req.Body)
+	if err != nil {
+		log.Printf("Error updating comment with ID %d: %v", commentId, err)
+		return openapi.Comment{}, commentError(err, "error updating comment")
+	}
+	return toCommentResponse(comment), nil
+}
+
+// DeleteTasksIdCommentsCommentId удаляет комментарий
+func (h *CommentHandler) DeleteTasksIdCommentsCommentId(ctx context.Context, id int64, commentId int64) error {
+	if err := h.commentService.DeleteComment(ctx, uint(id), uint(commentId)); err != nil {
+		return commentError(err, "error deleting comment")
+	}
+	return nil
+}
+
+// toCommentResponse преобразует commentService.Comment в openapi.Comment
+func toCommentResponse(c commentService.Comment) openapi.Comment {
+	history := make([]time.Time, 0, len(c.Edits))
+	for _, e := range c.Edits {
+		history = append(history, e.EditedAt)
+	}
+	return openapi.Comment{
+		Id:          int64(c.ID),
+		TaskId:      int64(c.TaskID),
+		AuthorId:    int64(c.AuthorID),
+		Body:        c.Body,
+		CreatedAt:   c.CreatedAt,
+		EditedAt:    c.EditedAt,
+		EditHistory: history,
+	}
+}
+
+// commentError переводит ошибки CommentService в HTTP-ошибки
+func commentError(err error, message string) error {
+	switch {
+	case errors.Is(err, identity.ErrUnauthenticated):
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	case errors.Is(err, commentService.ErrCommentNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "comment not found")
+	case errors.Is(err, taskService.ErrTaskNotFound), errors.Is(err, gorm.ErrRecordNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "task not found")
+	case errors.Is(err, commentService.ErrNotAuthor), errors.Is(err, commentService.ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, commentService.ErrInvalidBody):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return fmt.Errorf("%s: %w", message, err)
+	}
+}
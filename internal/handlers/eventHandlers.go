@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"newproject/internal/identity"
+	"newproject/internal/streamService"
+	"newproject/internal/tenant"
+	openapi "newproject/internal/web/events"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type EventHandler struct {
+	hub       *streamService.Hub
+	heartbeat time.Duration
+}
+
+// NewEventHandler создает обработчик ленты событий. Раз в heartbeat в ленту
+// пишется комментарий, чтобы прокси не закрывали молчащее соединение.
+func NewEventHandler(hub *streamService.Hub, heartbeat time.Duration) *EventHandler {
+	return &EventHandler{
+		hub:       hub,
+		heartbeat: heartbeat,
+	}
+}
+
+// GetEventsStream открывает SSE-ленту изменений задач пользователя. Ответ
+// пишется по мере поступления событий, поэтому обработчик не строгий.
+func (h *EventHandler) GetEventsStream(ctx echo.Context, params openapi.GetEventsStreamParams) error {
+	var userID *uint
+	if params.UserId != nil {
+		id := uint(*params.UserId)
+		userID = &id
+	}
+	var lastEventID string
+	if params.LastEventID != nil {
+		lastEventID = *params.LastEventID
+	}
+
+	sub, err := h.hub.Subscribe(ctx.Request().Context(), userID, lastEventID)
+	if err != nil {
+		return streamError(err)
+	}
+	defer sub.Close()
+
+	w := ctx.Response()
+	header := w.Header()
+	header.Set(echo.HeaderContentType, "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// Ошибка записи значит, что клиент ушел: отвечать уже некому
+	if sub.Reset {
+		id := ""
+		if sub.LatestID != 0 {
+			id = strconv.FormatUint(uint64(sub.LatestID), 10)
+		}
+		if _, err := fmt.Fprintf(w, "id: %s\nevent: reset\ndata: {}\n\n", id); err != nil {
+			return nil
+		}
+	}
+	for _, event := range sub.Backlog {
+		if err := writeStreamEvent(w, event); err != nil {
+			return nil
+		}
+	}
+	w.Flush()
+
+	var heartbeat <-chan time.Time
+	if h.heartbeat > 0 {
+		ticker := time.NewTicker(h.heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+	done := ctx.Request().Context().Done()
+	for {
+		select {
+		case <-done:
+			return nil
+		case event, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+			if err := writeStreamEvent(w, event); err != nil {
+				return nil
+			}
+		case <-heartbeat:
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+		}
+		w.Flush()
+	}
+}
+
+// writeStreamEvent пишет событие в формате text/event-stream
+func writeStreamEvent(w io.Writer, event streamService.Event) error {
+	data, err := json.Marshal(toStreamEventResponse(event))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+func toStreamEventResponse(e streamService.Event) openapi.TaskStreamEvent {
+	resp := openapi.TaskStreamEvent{
+		Id:         int64(e.ID),
+		Event:      openapi.TaskStreamEventEvent(e.Type),
+		TaskId:     int64(e.TaskID),
+		OccurredAt: e.OccurredAt,
+	}
+	if e.ActorID != nil {
+		resp.ActorId = int64Ptr(int64(*e.ActorID))
+	}
+	if len(e.Before) > 0 {
+		before := string(e.Before)
+		resp.Before = jsonObject(&before)
+	}
+	if len(e.After) > 0 {
+		after := string(e.After)
+		resp.After = jsonObject(&after)
+	}
+	return resp
+}
+
+// streamError переводит ошибки ленты в HTTP-ошибки
+func streamError(err error) error {
+	switch {
+	case errors.Is(err, identity.ErrUnauthenticated):
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	case errors.Is(err, tenant.ErrNoWorkspace):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, streamService.ErrUserNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "user not found")
+	case errors.Is(err, streamService.ErrInvalidLastEventID):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return fmt.Errorf("error opening event stream: %w", err)
+	}
+}
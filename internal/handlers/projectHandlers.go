@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"newproject/internal/identity"
+	"newproject/internal/pagination"
+	"newproject/internal/projectService"
+	"newproject/internal/taskService"
+	openapi "newproject/internal/web/projects"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ProjectHandler struct {
+	projectService *projectService.ProjectService
+}
+
+func NewProjectHandler(projectService *projectService.ProjectService) *ProjectHandler {
+	return &ProjectHandler{projectService: projectService}
+}
+
+// GetProjects возвращает страницу проектов
+func (h *ProjectHandler) GetProjects(ctx context.Context, params openapi.GetProjectsParams) (openapi.ProjectPage, error) {
+	page, err := pagination.NewPage(params.Limit, params.Cursor, pagination.ByCreatedAt)
+	if err != nil {
+		return openapi.ProjectPage{}, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	includeArchived := params.Archived != nil && *params.Archived
+	projects, next, err := h.projectService.GetProjects(ctx, includeArchived, page)
+	if err != nil {
+		log.Printf("Error fetching projects: %v", err)
+		return openapi.ProjectPage{}, projectError(err, "error fetching projects")
+	}
+
+	items := make([]openapi.Project, 0, len(projects))
+	for _, p := range projects {
+		items = append(items, toProjectResponse(p))
+	}
+	return openapi.ProjectPage{Items: items, NextCursor: cursorPtr(next)}, nil
+}
+
+// PostProjects создает проект
+func (h *ProjectHandler) PostProjects(ctx context.Context, req openapi.NewProjectRequest) (openapi.Project, error) {
+	project := projectService.Project{Name: req.Name}
+	if req.Description != nil {
+		project.Description = *req.Description
+	}
+
+	created, err := h.projectService.CreateProject(ctx, project)
+	if err != nil {
+		log.Printf("Error creating project: %v", err)
+		return openapi.Project{}, projectError(err, "error creating project")
+	}
+	return toProjectResponse(created), nil
+}
+
+// GetProjectsId возвращает проект по ID
+func (h *ProjectHandler) GetProjectsId(ctx context.Context, id int64) (openapi.Project, error) {
+	project, err := h.projectService.GetProjectByID(ctx, uint(id))
+	if err != nil {
+		return openapi.Project{}, projectError(err, "error fetching project")
+	}
+	return toProjectResponse(project), nil
+}
+
+// PatchProjectsId обновляет проект или переносит его в архив
+func (h *ProjectHandler) PatchProjectsId(ctx context.Context, id int64, req openapi.UpdateProjectRequest) (openapi.Project, error) {
+	project, err := h.projectService.UpdateProject(ctx, uint(id), projectService.ProjectUpdate{
+		Name:        req.Name,
+		Description: req.Description,
+		Archived:    req.Archived,
+	})
+	if err != nil {
+		log.Printf("Error updating project with ID %d: %v", id, err)
+		return openapi.Project{}, projectError(err, "error updating project")
+	}
+	return toProjectResponse(project), nil
+}
+
+// DeleteProjectsId удаляет проект по ID
+func (h *ProjectHandler) DeleteProjectsId(ctx context.Context, id int64) error {
+	if err := h.projectService.DeleteProjectByID(ctx, uint(id)); err != nil {
+		return projectError(err, "error deleting project")
+	}
+	return nil
+}
+
+// GetProjectsIdTasks возвращает страницу задач проекта в ручном порядке
+func (h *ProjectHandler) GetProjectsIdTasks(ctx context.Context, id int64, params openapi.GetProjectsIdTasksParams) (openapi.TaskPage, error) {
+	page, err := pagination.NewPage(params.Limit, params.Cursor, projectService.ByPosition)
+	if err != nil {
+		return openapi.TaskPage{}, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	tasks, next, err := h.projectService.GetProjectTasks(ctx, uint(id), page)
+	if err != nil {
+		return openapi.TaskPage{}, projectError(err, "error fetching project tasks")
+	}
+
+	items := make([]openapi.Task, 0, len(tasks))
+	for _, t := range tasks {
+		items = append(items, toProjectTaskResponse(t))
+	}
+	return openapi.TaskPage{Items: items, NextCursor: cursorPtr(next)}, nil
+}
+
+// PutProjectsIdTasksOrder задает ручной порядок задач проекта
+func (h *ProjectHandler) PutProjectsIdTasksOrder(ctx context.Context, id int64, req openapi.TaskOrderRequest) error {
+	taskIDs := make([]uint, 0, len(req.TaskIds))
+	for _, taskID := range req.TaskIds {
+		taskIDs = append(taskIDs, uint(taskID))
+	}
+
+	if err := h.projectService.ReorderTasks(ctx, uint(id), taskIDs); err != nil {
+		return projectError(err, "error reordering project tasks")
+	}
+	return nil
+}
+
+// toProjectResponse преобразует projectService.ProjectSummary в openapi.Project
+func toProjectResponse(p projectService.ProjectSummary) openapi.Project {
+	return openapi.Project{
+		Id:          int64(p.ID),
+		Name:        p.Name,
+		Description: p.Description,
+		UserId:      int64(p.UserID),
+		CreatedAt:   p.CreatedAt,
+		ArchivedAt:  p.ArchivedAt,
+		OpenTasks:   p.Open,
+		DoneTasks:   p.Done,
+	}
+}
+
+// toProjectTaskResponse преобразует taskService.Task в задачу из API проектов
+func toProjectTaskResponse(t taskService.Task) openapi.Task {
+	resp := openapi.Task{
+		Id:          int64Ptr(int64(t.ID)),
+		Task:        stringPtr(t.Task),
+		IsDone:      boolPtr(t.IsDone),
+		UserId:      int64Ptr(int64(t.UserID)),
+		DueAt:       t.DueAt,
+		Priority:    stringPtr(t.Priority),
+		CompletedAt: t.CompletedAt,
+		Position:    &t.Position,
+	}
+	tags := make([]openapi.Tag, 0, len(t.Tags))
+	for _, tag := range t.Tags {
+		tags = append(tags, openapi.Tag{Id: int64(tag.ID), Name: tag.Name, Color: tag.Color})
+	}
+	resp.Tags = &tags
+	if t.ParentID != nil {
+		resp.ParentId = int64Ptr(int64(*t.ParentID))
+	}
+	if t.ProjectID != nil {
+		resp.ProjectId = int64Ptr(int64(*t.ProjectID))
+	}
+	if t.SeriesID != nil {
+		resp.SeriesId = int64Ptr(int64(*t.SeriesID))
+	}
+	if t.Recurrence != "" {
+		resp.Recurrence = stringPtr(t.Recurrence)
+	}
+	if t.StatusID != nil {
+		resp.StatusId = int64Ptr(int64(*t.StatusID))
+	}
+	if t.Status != nil {
+		resp.Status = &openapi.TaskStatusRef{Id: int64(t.Status.ID), Name: t.Status.Name, Category: t.Status.Category}
+	}
+	assignees := make([]openapi.TaskAssignee, 0, len(t.Assignees))
+	for _, a := range t.Assignees {
+		assignees = append(assignees, openapi.TaskAssignee{UserId: int64(a.UserID), Role: a.Role})
+	}
+	resp.Assignees = &assignees
+	return resp
+}
+
+// projectError переводит ошибки ProjectService в HTTP-ошибки
+func projectError(err error, message string) error {
+	switch {
+	case errors.Is(err, identity.ErrUnauthenticated):
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	case errors.Is(err, projectService.ErrProjectNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "project not found")
+	case errors.Is(err, projectService.ErrInvalidName), errors.Is(err, projectService.ErrInvalidOrder):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return fmt.Errorf("%s: %w", message, err)
+	}
+}
@@ -5,52 +5,70 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"newproject/internal/identity"
 	"newproject/internal/models"
+	"newproject/internal/pagination"
+	"newproject/internal/policy"
 	"newproject/internal/taskService"
 	"newproject/internal/userService"
 	openapi "newproject/internal/web/tasks"
+	"strings"
 
+	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
 
 type TaskHandler struct {
 	taskService *taskService.TaskService
 	userService *userService.UserService
+	policy      *policy.Policy
 }
 
-func NewTaskHandler(taskService *taskService.TaskService, userService *userService.UserService) *TaskHandler {
+func NewTaskHandler(taskService *taskService.TaskService, userService *userService.UserService, policy *policy.Policy) *TaskHandler {
 	return &TaskHandler{
 		taskService: taskService,
 		userService: userService,
+		policy:      policy,
 	}
 }
 
-// GetUsers возвращает всех пользователей
-func (h *TaskHandler) GetUsers(ctx context.Context) ([]openapi.User, error) {
+// GetUsers возвращает страницу пользователей
+func (h *TaskHandler) GetUsers(ctx context.Context, params openapi.GetUsersParams) (openapi.UserPage, error) {
 	// Проверяем, что userService инициализирован
 	if h.userService == nil {
 		log.Println("userService is nil")
-		return nil, fmt.Errorf("user service is not initialized")
+		return openapi.UserPage{}, fmt.Errorf("user service is not initialized")
 	}
 
-	// Получаем всех пользователей через userService
-	users, err := h.userService.GetAllUsers()
+	if err := h.policy.CanListUsers(ctx); err != nil {
+		return openapi.UserPage{}, policyError(err)
+	}
+
+	page, err := pagination.NewPage(params.Limit, params.Cursor, pagination.ByCreatedAt)
+	if err != nil {
+		return openapi.UserPage{}, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	// Получаем пользователей через userService
+	users, next, err := h.userService.GetAllUsers(ctx, page)
 	if err != nil {
 		log.Printf("Error fetching users: %v", err)
-		return nil, fmt.Errorf("error fetching users: %w", err)
+		return openapi.UserPage{}, fmt.Errorf("error fetching users: %w", err)
 	}
 
 	// Преобразуем []models.User в []openapi.User
-	var response []openapi.User
+	response := make([]openapi.User, 0, len(users))
 	for _, u := range users {
 		response = append(response, openapi.User{
 			Id:       int64Ptr(int64(u.ID)),
 			Username: stringPtr(u.Name),
 			Email:    stringPtr(u.Email),
+			Role:     stringPtr(u.Role),
 		})
 	}
 
-	return response, nil
+	return openapi.UserPage{Items: response, NextCursor: cursorPtr(next)}, nil
 }
 
 // PostUsers создает нового пользователя
@@ -69,7 +87,7 @@ func (h *TaskHandler) PostUsers(ctx context.Context, req openapi.NewUserRequest)
 		Password: *req.Password,
 	}
 
-	createdUser, err := h.userService.CreateUser(user)
+	createdUser, err := h.userService.CreateUser(ctx, user)
 	if err != nil {
 		log.Printf("Error creating user: %v", err)
 		return openapi.User{}, fmt.Errorf("error creating user: %w", err)
@@ -79,87 +97,427 @@ func (h *TaskHandler) PostUsers(ctx context.Context, req openapi.NewUserRequest)
 		Id:       int64Ptr(int64(createdUser.ID)),
 		Username: stringPtr(createdUser.Name),
 		Email:    stringPtr(createdUser.Email),
+		Role:     stringPtr(createdUser.Role),
 	}, nil
 }
 
-// GetTasks возвращает все задачи
-func (h *TaskHandler) GetTasks(ctx context.Context) ([]openapi.Task, error) {
+// GetTasks возвращает страницу задач вызывающего, а администратору — всех задач,
+// с учетом фильтров и сортировки
+func (h *TaskHandler) GetTasks(ctx context.Context, params openapi.GetTasksParams) (openapi.TaskPage, error) {
 	if h.taskService == nil {
-		return nil, fmt.Errorf("task service is not initialized")
+		return openapi.TaskPage{}, fmt.Errorf("task service is not initialized")
 	}
 
-	taskList, err := h.taskService.GetAllTasks()
+	filter, page, err := taskQueryFromParams(params)
 	if err != nil {
-		log.Printf("Error fetching tasks: %v", err)
-		return nil, fmt.Errorf("error fetching tasks: %w", err)
+		return openapi.TaskPage{}, echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	var response []openapi.Task
-	for _, t := range taskList {
-		response = append(response, openapi.Task{
-			Id:     int64Ptr(int64(t.ID)),
-			IsDone: boolPtr(t.IsDone),
-			Task:   stringPtr(t.Task),
-			UserId: int64Ptr(int64(t.UserID)),
-		})
+	var taskList []taskService.Task
+	var next string
+	// Только отказ в доступе означает «свои задачи», остальные ошибки — ошибки
+	switch err = h.policy.CanListAllTasks(ctx); {
+	case err == nil:
+		taskList, next, err = h.taskService.GetAllTasks(ctx, filter, page)
+	case errors.Is(err, policy.ErrForbidden):
+		taskList, next, err = h.taskService.GetTasks(ctx, filter, page)
+	default:
+		return openapi.TaskPage{}, policyError(err)
+	}
+	if err != nil {
+		log.Printf("Error fetching tasks: %v", err)
+		return openapi.TaskPage{}, taskError(err, "error fetching tasks")
 	}
 
-	return response, nil
+	return toTaskPage(taskList, next), nil
 }
 
-// GetTasksByUserID возвращает задачи пользователя по user_id
-func (h *TaskHandler) GetTasksByUserID(ctx context.Context, userID int64) ([]openapi.Task, error) {
+// GetTasksByUserID возвращает страницу задач пользователя по user_id
+func (h *TaskHandler) GetTasksByUserID(ctx context.Context, userID int64, params openapi.GetTasksParams) (openapi.TaskPage, error) {
 	if h.taskService == nil {
-		return nil, fmt.Errorf("task service is not initialized")
+		return openapi.TaskPage{}, fmt.Errorf("task service is not initialized")
 	}
 
-	tasks, err := h.taskService.GetTasksByUserID(uint(userID))
+	page, err := pagination.NewPage(params.Limit, params.Cursor, pagination.ByCreatedAt)
 	if err != nil {
+		return openapi.TaskPage{}, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	tasks, next, err := h.taskService.GetTasksByUserID(ctx, uint(userID), page)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return openapi.TaskPage{}, echo.NewHTTPError(http.StatusNotFound, "user not found")
+	} else if err != nil {
 		log.Printf("Error fetching tasks for user: %v", err)
-		return nil, fmt.Errorf("error fetching tasks for user: %w", err)
+		return openapi.TaskPage{}, taskError(err, "error fetching tasks for user")
 	}
 
-	var response []openapi.Task
-	for _, t := range tasks {
-		response = append(response, openapi.Task{
-			Id:     int64Ptr(int64(t.ID)),
-			IsDone: boolPtr(t.IsDone),
-			Task:   stringPtr(t.Task),
-			UserId: int64Ptr(int64(t.UserID)),
+	return toTaskPage(tasks, next), nil
+}
+
+// GetTasksSearch ищет задачи по тексту и возвращает их по убыванию релевантности
+func (h *TaskHandler) GetTasksSearch(ctx context.Context, params openapi.GetTasksSearchParams) (openapi.TaskSearchResults, error) {
+	if h.taskService == nil {
+		return openapi.TaskSearchResults{}, fmt.Errorf("task service is not initialized")
+	}
+
+	limit := taskService.DefaultSearchLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+
+	results, err := h.taskService.SearchTasks(ctx, params.Q, limit)
+	if errors.Is(err, taskService.ErrInvalidSearch) {
+		return openapi.TaskSearchResults{}, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	} else if err != nil {
+		log.Printf("Error searching tasks: %v", err)
+		return openapi.TaskSearchResults{}, taskError(err, "error searching tasks")
+	}
+
+	response := make([]openapi.TaskSearchResult, 0, len(results))
+	for _, r := range results {
+		response = append(response, openapi.TaskSearchResult{
+			Task:    toTaskResponse(r.Task),
+			Rank:    float32(r.Rank),
+			Snippet: r.Snippet,
 		})
 	}
+	return openapi.TaskSearchResults{Items: response}, nil
+}
+
+// GetTasksIdSubtasks возвращает страницу прямых подзадач задачи
+func (h *TaskHandler) GetTasksIdSubtasks(ctx context.Context, id int64, params openapi.GetTasksIdSubtasksParams) (openapi.TaskPage, error) {
+	if h.taskService == nil {
+		return openapi.TaskPage{}, fmt.Errorf("task service is not initialized")
+	}
 
-	return response, nil
+	page, err := pagination.NewPage(params.Limit, params.Cursor, pagination.ByCreatedAt)
+	if err != nil {
+		return openapi.TaskPage{}, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	tasks, next, err := h.taskService.GetSubtasks(ctx, uint(id), page)
+	if err != nil {
+		return openapi.TaskPage{}, taskError(err, "error fetching subtasks")
+	}
+	return toTaskPage(tasks, next), nil
 }
 
-// PostTasks создает новую задачу
-func (h *TaskHandler) PostTasks(ctx context.Context, req openapi.NewTaskRequest) (openapi.Task, error) {
+// PostTasksIdSubtasks создает подзадачу
+func (h *TaskHandler) PostTasksIdSubtasks(ctx context.Context, id int64, req openapi.NewTaskRequest) (openapi.Task, error) {
 	if h.taskService == nil {
 		return openapi.Task{}, fmt.Errorf("task service is not initialized")
 	}
 
-	if req.Task == nil || req.IsDone == nil || req.UserId == nil {
-		return openapi.Task{}, fmt.Errorf("task, isDone, and userId are required")
+	task, err := newTaskFromRequest(req)
+	if err != nil {
+		return openapi.Task{}, err
+	}
+
+	createdTask, err := h.taskService.CreateSubtask(ctx, uint(id), task)
+	if err != nil {
+		log.Printf("Error creating subtask: %v", err)
+		return openapi.Task{}, taskError(err, "error creating subtask")
 	}
+	return toTaskResponse(createdTask), nil
+}
 
-	task := taskService.Task{
-		Task:   *req.Task,
-		IsDone: *req.IsDone,
-		UserID: uint(*req.UserId),
+// GetTasksIdActivity возвращает страницу ленты активности задачи
+func (h *TaskHandler) GetTasksIdActivity(ctx context.Context, id int64, params openapi.GetTasksIdActivityParams) (openapi.TaskEventPage, error) {
+	if h.taskService == nil {
+		return openapi.TaskEventPage{}, fmt.Errorf("task service is not initialized")
+	}
+
+	page, err := pagination.NewPage(params.Limit, params.Cursor, pagination.ByCreatedAt)
+	if err != nil {
+		return openapi.TaskEventPage{}, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	events, next, err := h.taskService.GetActivity(ctx, uint(id), page)
+	if err != nil {
+		return openapi.TaskEventPage{}, taskError(err, "error fetching task activity")
+	}
+
+	items := make([]openapi.TaskEvent, len(events))
+	for i, e := range events {
+		items[i] = toTaskEventResponse(e)
+	}
+	return openapi.TaskEventPage{Items: items, NextCursor: cursorPtr(next)}, nil
+}
+
+// GetTasksIdDependencies возвращает блокирующие и зависящие задачи
+func (h *TaskHandler) GetTasksIdDependencies(ctx context.Context, id int64) (openapi.TaskDependencies, error) {
+	if h.taskService == nil {
+		return openapi.TaskDependencies{}, fmt.Errorf("task service is not initialized")
+	}
+
+	deps, err := h.taskService.GetDependencies(ctx, uint(id))
+	if err != nil {
+		return openapi.TaskDependencies{}, taskError(err, "error fetching dependencies")
+	}
+	return toDependenciesResponse(deps), nil
+}
+
+// PostTasksIdDependencies объявляет, что задача blocked_by блокирует задачу id
+func (h *TaskHandler) PostTasksIdDependencies(ctx context.Context, id int64, req openapi.NewDependencyRequest) (openapi.TaskDependencies, error) {
+	if h.taskService == nil {
+		return openapi.TaskDependencies{}, fmt.Errorf("task service is not initialized")
+	}
+
+	if req.BlockedBy == 0 {
+		return openapi.TaskDependencies{}, echo.NewHTTPError(http.StatusBadRequest, "blocked_by is required")
+	}
+
+	if err := h.taskService.AddDependency(ctx, uint(req.BlockedBy), uint(id)); err != nil {
+		log.Printf("Error adding dependency: %v", err)
+		return openapi.TaskDependencies{}, taskError(err, "error adding dependency")
+	}
+	return h.GetTasksIdDependencies(ctx, id)
+}
+
+// DeleteTasksIdDependenciesBlockerId удаляет блокировку задачи id задачей blocker_id
+func (h *TaskHandler) DeleteTasksIdDependenciesBlockerId(ctx context.Context, id int64, blockerId int64) error {
+	if h.taskService == nil {
+		return fmt.Errorf("task service is not initialized")
+	}
+
+	err := h.taskService.RemoveDependency(ctx, uint(blockerId), uint(id))
+	if err != nil {
+		return taskError(err, "error removing dependency")
+	}
+	return nil
+}
+
+// PutTasksIdTagsTagId навешивает метку на задачу
+func (h *TaskHandler) PutTasksIdTagsTagId(ctx context.Context, id int64, tagId int64) (openapi.Task, error) {
+	if h.taskService == nil {
+		return openapi.Task{}, fmt.Errorf("task service is not initialized")
+	}
+
+	task, err := h.taskService.AttachTag(ctx, uint(id), uint(tagId))
+	if err != nil {
+		return openapi.Task{}, taskError(err, "error attaching tag")
+	}
+	return toTaskResponse(task), nil
+}
+
+// DeleteTasksIdTagsTagId снимает метку с задачи
+func (h *TaskHandler) DeleteTasksIdTagsTagId(ctx context.Context, id int64, tagId int64) error {
+	if h.taskService == nil {
+		return fmt.Errorf("task service is not initialized")
+	}
+
+	if err := h.taskService.DetachTag(ctx, uint(id), uint(tagId)); err != nil {
+		return taskError(err, "error detaching tag")
+	}
+	return nil
+}
+
+// GetTags возвращает метки вызывающего
+func (h *TaskHandler) GetTags(ctx context.Context) (openapi.TagList, error) {
+	if h.taskService == nil {
+		return openapi.TagList{}, fmt.Errorf("task service is not initialized")
+	}
+
+	tags, err := h.taskService.GetTags(ctx)
+	if err != nil {
+		return openapi.TagList{}, taskError(err, "error fetching tags")
+	}
+	return openapi.TagList{Items: toTagList(tags)}, nil
+}
+
+// PostTags создает метку
+func (h *TaskHandler) PostTags(ctx context.Context, req openapi.NewTagRequest) (openapi.Tag, error) {
+	if h.taskService == nil {
+		return openapi.Tag{}, fmt.Errorf("task service is not initialized")
+	}
+
+	tag := taskService.Tag{Name: req.Name}
+	if req.Color != nil {
+		tag.Color = *req.Color
+	}
+
+	created, err := h.taskService.CreateTag(ctx, tag)
+	if err != nil {
+		log.Printf("Error creating tag: %v", err)
+		return openapi.Tag{}, taskError(err, "error creating tag")
+	}
+	return toTagResponse(created), nil
+}
+
+// DeleteTagsId удаляет метку
+func (h *TaskHandler) DeleteTagsId(ctx context.Context, id int64) error {
+	if h.taskService == nil {
+		return fmt.Errorf("task service is not initialized")
+	}
+
+	if err := h.taskService.DeleteTag(ctx, uint(id)); err != nil {
+		return taskError(err, "error deleting tag")
+	}
+	return nil
+}
+
+// PutTasksIdAssigneesUserId назначает пользователя исполнителем задачи
+func (h *TaskHandler) PutTasksIdAssigneesUserId(ctx context.Context, id int64, userId int64) (openapi.Task, error) {
+	return h.assign(ctx, id, userId, taskService.RoleAssignee)
+}
+
+// DeleteTasksIdAssigneesUserId снимает исполнителя с задачи
+func (h *TaskHandler) DeleteTasksIdAssigneesUserId(ctx context.Context, id int64, userId int64) error {
+	return h.unassign(ctx, id, userId, taskService.RoleAssignee)
+}
+
+// PutTasksIdWatchersUserId делает пользователя наблюдателем задачи
+func (h *TaskHandler) PutTasksIdWatchersUserId(ctx context.Context, id int64, userId int64) (openapi.Task, error) {
+	return h.assign(ctx, id, userId, taskService.RoleWatcher)
+}
+
+// DeleteTasksIdWatchersUserId убирает наблюдателя задачи
+func (h *TaskHandler) DeleteTasksIdWatchersUserId(ctx context.Context, id int64, userId int64) error {
+	return h.unassign(ctx, id, userId, taskService.RoleWatcher)
+}
+
+func (h *TaskHandler) assign(ctx context.Context, id, userID int64, role string) (openapi.Task, error) {
+	if h.taskService == nil {
+		return openapi.Task{}, fmt.Errorf("task service is not initialized")
+	}
+
+	task, err := h.taskService.Assign(ctx, uint(id), uint(userID), role)
+	if err != nil {
+		log.Printf("Error assigning user %d to task %d as %s: %v", userID, id, role, err)
+		return openapi.Task{}, taskError(err, "error assigning user")
+	}
+	return toTaskResponse(task), nil
+}
+
+func (h *TaskHandler) unassign(ctx context.Context, id, userID int64, role string) error {
+	if h.taskService == nil {
+		return fmt.Errorf("task service is not initialized")
+	}
+
+	if err := h.taskService.Unassign(ctx, uint(id), uint(userID), role); err != nil {
+		return taskError(err, "error unassigning user")
+	}
+	return nil
+}
+
+// PatchTasksIdStatus переводит задачу в другой статус доски
+func (h *TaskHandler) PatchTasksIdStatus(ctx context.Context, id int64, req openapi.TaskStatusChangeRequest) (openapi.Task, error) {
+	if h.taskService == nil {
+		return openapi.Task{}, fmt.Errorf("task service is not initialized")
+	}
+
+	task, err := h.taskService.SetStatus(ctx, uint(id), uint(req.StatusId))
+	if err != nil {
+		log.Printf("Error changing status of task %d: %v", id, err)
+		return openapi.Task{}, taskError(err, "error changing task status")
+	}
+	return toTaskResponse(task), nil
+}
+
+// GetStatuses возвращает статусы доски вызывающего
+func (h *TaskHandler) GetStatuses(ctx context.Context) (openapi.TaskStatusList, error) {
+	if h.taskService == nil {
+		return openapi.TaskStatusList{}, fmt.Errorf("task service is not initialized")
+	}
+
+	statuses, err := h.taskService.GetStatuses(ctx)
+	if err != nil {
+		return openapi.TaskStatusList{}, taskError(err, "error fetching statuses")
+	}
+	items := make([]openapi.TaskStatus, 0, len(statuses))
+	for _, st := range statuses {
+		items = append(items, toStatusResponse(st))
+	}
+	return openapi.TaskStatusList{Items: items}, nil
+}
+
+// PostStatuses добавляет статус на доску вызывающего
+func (h *TaskHandler) PostStatuses(ctx context.Context, req openapi.NewTaskStatusRequest) (openapi.TaskStatus, error) {
+	if h.taskService == nil {
+		return openapi.TaskStatus{}, fmt.Errorf("task service is not initialized")
+	}
+
+	status := taskService.TaskStatus{Name: req.Name, Category: string(req.Category)}
+	var transitions []uint
+	if req.Transitions != nil {
+		transitions = toUintIDs(*req.Transitions)
+	}
+
+	created, err := h.taskService.CreateStatus(ctx, status, transitions)
+	if err != nil {
+		log.Printf("Error creating status: %v", err)
+		return openapi.TaskStatus{}, taskError(err, "error creating status")
+	}
+	return toStatusResponse(created), nil
+}
+
+// PutStatusesOrder задает порядок колонок доски
+func (h *TaskHandler) PutStatusesOrder(ctx context.Context, req openapi.StatusOrderRequest) error {
+	if h.taskService == nil {
+		return fmt.Errorf("task service is not initialized")
+	}
+
+	if err := h.taskService.ReorderStatuses(ctx, toUintIDs(req.StatusIds)); err != nil {
+		return taskError(err, "error reordering statuses")
+	}
+	return nil
+}
+
+// PatchStatusesId меняет статус доски
+func (h *TaskHandler) PatchStatusesId(ctx context.Context, id int64, req openapi.UpdateTaskStatusRequest) (openapi.TaskStatus, error) {
+	if h.taskService == nil {
+		return openapi.TaskStatus{}, fmt.Errorf("task service is not initialized")
+	}
+
+	update := taskService.StatusUpdate{Name: req.Name}
+	if req.Category != nil {
+		category := string(*req.Category)
+		update.Category = &category
+	}
+	if req.Transitions != nil {
+		transitions := toUintIDs(*req.Transitions)
+		update.Transitions = &transitions
+	}
+
+	status, err := h.taskService.UpdateStatus(ctx, uint(id), update)
+	if err != nil {
+		log.Printf("Error updating status with ID %d: %v", id, err)
+		return openapi.TaskStatus{}, taskError(err, "error updating status")
+	}
+	return toStatusResponse(status), nil
+}
+
+// DeleteStatusesId удаляет пустой статус
+func (h *TaskHandler) DeleteStatusesId(ctx context.Context, id int64) error {
+	if h.taskService == nil {
+		return fmt.Errorf("task service is not initialized")
 	}
 
-	createdTask, err := h.taskService.CreateTask(task)
+	if err := h.taskService.DeleteStatus(ctx, uint(id)); err != nil {
+		return taskError(err, "error deleting status")
+	}
+	return nil
+}
+
+// PostTasks создает новую задачу
+func (h *TaskHandler) PostTasks(ctx context.Context, req openapi.NewTaskRequest) (openapi.Task, error) {
+	if h.taskService == nil {
+		return openapi.Task{}, fmt.Errorf("task service is not initialized")
+	}
+
+	// Владелец по умолчанию — вызывающий, user_id из запроса только проверяется
+	task, err := newTaskFromRequest(req)
+	if err != nil {
+		return openapi.Task{}, err
+	}
+
+	createdTask, err := h.taskService.CreateTask(ctx, task)
 	if err != nil {
 		log.Printf("Error creating task: %v", err)
-		return openapi.Task{}, fmt.Errorf("error creating task: %w", err)
+		return openapi.Task{}, taskError(err, "error creating task")
 	}
 
-	return openapi.Task{
-		Id:     int64Ptr(int64(createdTask.ID)),
-		Task:   stringPtr(createdTask.Task),
-		IsDone: boolPtr(createdTask.IsDone),
-		UserId: int64Ptr(int64(createdTask.UserID)),
-	}, nil
+	return toTaskResponse(createdTask), nil
 }
 
 // DeleteTasksId удаляет задачу по ID
@@ -168,18 +526,16 @@ func (h *TaskHandler) DeleteTasksId(ctx context.Context, id int64) error {
 		return fmt.Errorf("task service is not initialized")
 	}
 
-	err := h.taskService.DeleteTaskByID(uint(id))
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return fmt.Errorf("task not found")
-	} else if err != nil {
-		return fmt.Errorf("error deleting task: %w", err)
+	err := h.taskService.DeleteTaskByID(ctx, uint(id))
+	if err != nil {
+		return taskError(err, "error deleting task")
 	}
 
 	return nil
 }
 
 // PatchTasksId обновляет задачу по ID
-func (h *TaskHandler) PatchTasksId(ctx context.Context, id int64, req openapi.PatchTasksIdJSONRequestBody) (openapi.Task, error) {
+func (h *TaskHandler) PatchTasksId(ctx context.Context, id int64, params openapi.PatchTasksIdParams, req openapi.PatchTasksIdJSONRequestBody) (openapi.Task, error) {
 	// Проверяем, что taskService инициализирован
 	if h.taskService == nil {
 		log.Println("taskService is nil")
@@ -188,30 +544,297 @@ func (h *TaskHandler) PatchTasksId(ctx context.Context, id int64, req openapi.Pa
 
 	log.Printf("Updating task with ID %d: task=%v, isDone=%v, userId=%v", id, req.Task, req.IsDone, req.UserId)
 
-	// Обновляем задачу
-	updatedTask, err := h.taskService.UpdateTaskByID(uint(id), taskService.Task{
-		Task:   req.Task,
-		IsDone: req.IsDone,
+	if req.Task != nil && strings.TrimSpace(*req.Task) == "" {
+		return openapi.Task{}, echo.NewHTTPError(http.StatusBadRequest, "task must not be empty")
+	}
+	task := taskService.Task{
 		UserID: uint(req.UserId),
-	})
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		log.Printf("Task with ID %d not found", id)
-		return openapi.Task{}, fmt.Errorf("task not found")
-	} else if err != nil {
-		log.Printf("Error updating task: %v", err)
-		return openapi.Task{}, fmt.Errorf("error updating task: %w", err)
+		DueAt:  req.DueAt,
+	}
+	// Название и is_done без значения в запросе остаются прежними
+	if req.Task == nil || req.IsDone == nil {
+		existing, err := h.taskService.GetTaskByID(ctx, uint(id))
+		if err != nil {
+			return openapi.Task{}, taskError(err, "error fetching task")
+		}
+		task.Task, task.IsDone = existing.Task, existing.IsDone
+	}
+	if req.Task != nil {
+		task.Task = *req.Task
+	}
+	if req.IsDone != nil {
+		task.IsDone = *req.IsDone
+	}
+	if req.Priority != nil {
+		task.Priority = string(*req.Priority)
+	}
+	if req.ParentId != nil {
+		parentID := uint(*req.ParentId)
+		task.ParentID = &parentID
+	}
+	if req.ProjectId != nil {
+		projectID := uint(*req.ProjectId)
+		task.ProjectID = &projectID
+	}
+	edit := taskService.SeriesEdit{Recurrence: req.Recurrence}
+	if params.Scope != nil {
+		edit.Scope = taskService.EditScope(*params.Scope)
+	}
+
+	// Обновляем задачу
+	updatedTask, err := h.taskService.UpdateTask(ctx, uint(id), task, edit)
+	if err != nil {
+		log.Printf("Error updating task with ID %d: %v", id, err)
+		return openapi.Task{}, taskError(err, "error updating task")
 	}
 
 	log.Printf("Task with ID %d updated successfully: %+v", id, updatedTask)
 
-	return openapi.Task{
-		Id:     int64Ptr(int64(updatedTask.ID)),
-		Task:   stringPtr(updatedTask.Task),
-		IsDone: boolPtr(updatedTask.IsDone),
-		UserId: int64Ptr(int64(updatedTask.UserID)),
-	}, nil
+	return toTaskResponse(updatedTask), nil
+}
+
+// newTaskFromRequest собирает задачу из тела запроса на создание
+func newTaskFromRequest(req openapi.NewTaskRequest) (taskService.Task, error) {
+	if req.Task == nil || req.IsDone == nil {
+		return taskService.Task{}, echo.NewHTTPError(http.StatusBadRequest, "task and isDone are required")
+	}
+
+	task := taskService.Task{
+		Task:   *req.Task,
+		IsDone: *req.IsDone,
+		DueAt:  req.DueAt,
+	}
+	if req.UserId != nil {
+		task.UserID = uint(*req.UserId)
+	}
+	if req.Priority != nil {
+		task.Priority = string(*req.Priority)
+	}
+	if req.ProjectId != nil {
+		projectID := uint(*req.ProjectId)
+		task.ProjectID = &projectID
+	}
+	if req.Recurrence != nil {
+		task.Recurrence = *req.Recurrence
+	}
+	if req.StatusId != nil {
+		statusID := uint(*req.StatusId)
+		task.StatusID = &statusID
+	}
+	return task, nil
+}
+
+// taskQueryFromParams собирает фильтр и страницу из параметров GET /tasks
+func taskQueryFromParams(params openapi.GetTasksParams) (taskService.TaskFilter, pagination.Page, error) {
+	filter := taskService.TaskFilter{
+		IsDone:        params.IsDone,
+		CreatedAfter:  params.CreatedAfter,
+		CreatedBefore: params.CreatedBefore,
+		UpdatedAfter:  params.UpdatedAfter,
+		UpdatedBefore: params.UpdatedBefore,
+		Overdue:       params.Overdue,
+	}
+	if params.UserId != nil {
+		userID := uint(*params.UserId)
+		filter.UserID = &userID
+	}
+	if params.Q != nil {
+		filter.Query = *params.Q
+	}
+	if params.ProjectId != nil {
+		projectID := uint(*params.ProjectId)
+		filter.ProjectID = &projectID
+	}
+	if params.StatusId != nil {
+		statusID := uint(*params.StatusId)
+		filter.StatusID = &statusID
+	}
+	if params.Tag != nil {
+		for _, tagID := range *params.Tag {
+			filter.TagIDs = append(filter.TagIDs, uint(tagID))
+		}
+	}
+	if params.TagMatch != nil {
+		filter.TagMatch = string(*params.TagMatch)
+	}
+
+	var sort string
+	if params.Sort != nil {
+		sort = string(*params.Sort)
+	}
+	order, err := pagination.ParseOrder(sort, taskService.SortColumns)
+	if err != nil {
+		return taskService.TaskFilter{}, pagination.Page{}, err
+	}
+
+	page, err := pagination.NewPage(params.Limit, params.Cursor, order)
+	if err != nil {
+		return taskService.TaskFilter{}, pagination.Page{}, err
+	}
+	return filter, page, nil
+}
+
+// toTaskResponse преобразует taskService.Task в openapi.Task
+func toTaskResponse(t taskService.Task) openapi.Task {
+	priority := openapi.TaskPriority(t.Priority)
+	resp := openapi.Task{
+		Id:          int64Ptr(int64(t.ID)),
+		Task:        stringPtr(t.Task),
+		IsDone:      boolPtr(t.IsDone),
+		UserId:      int64Ptr(int64(t.UserID)),
+		DueAt:       t.DueAt,
+		Priority:    &priority,
+		CompletedAt: t.CompletedAt,
+	}
+	tags := toTagList(t.Tags)
+	resp.Tags = &tags
+	if t.ParentID != nil {
+		resp.ParentId = int64Ptr(int64(*t.ParentID))
+	}
+	if t.ProjectID != nil {
+		resp.ProjectId = int64Ptr(int64(*t.ProjectID))
+		resp.Position = &t.Position
+	}
+	if t.SeriesID != nil {
+		resp.SeriesId = int64Ptr(int64(*t.SeriesID))
+	}
+	if t.Recurrence != "" {
+		resp.Recurrence = stringPtr(t.Recurrence)
+	}
+	if t.StatusID != nil {
+		resp.StatusId = int64Ptr(int64(*t.StatusID))
+	}
+	if t.Status != nil {
+		resp.Status = &openapi.TaskStatusRef{
+			Id:       int64(t.Status.ID),
+			Name:     t.Status.Name,
+			Category: openapi.TaskStatusCategory(t.Status.Category),
+		}
+	}
+	assignees := make([]openapi.TaskAssignee, 0, len(t.Assignees))
+	for _, a := range t.Assignees {
+		assignees = append(assignees, openapi.TaskAssignee{UserId: int64(a.UserID), Role: openapi.TaskAssigneeRole(a.Role)})
+	}
+	resp.Assignees = &assignees
+	return resp
+}
+
+func toStatusResponse(st taskService.TaskStatus) openapi.TaskStatus {
+	transitions := make([]int64, 0, len(st.Transitions))
+	for _, next := range st.Transitions {
+		transitions = append(transitions, int64(next.ID))
+	}
+	return openapi.TaskStatus{
+		Id:          int64(st.ID),
+		Name:        st.Name,
+		Category:    openapi.TaskStatusCategory(st.Category),
+		Position:    st.Position,
+		Transitions: transitions,
+	}
+}
+
+func toUintIDs(ids []int64) []uint {
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, uint(id))
+	}
+	return result
+}
+
+func toTagResponse(t taskService.Tag) openapi.Tag {
+	return openapi.Tag{Id: int64(t.ID), Name: t.Name, Color: t.Color}
+}
+
+func toTagList(tags []taskService.Tag) []openapi.Tag {
+	response := make([]openapi.Tag, 0, len(tags))
+	for _, t := range tags {
+		response = append(response, toTagResponse(t))
+	}
+	return response
+}
+
+func toTaskList(tasks []taskService.Task) []openapi.Task {
+	response := make([]openapi.Task, 0, len(tasks))
+	for _, t := range tasks {
+		response = append(response, toTaskResponse(t))
+	}
+	return response
+}
+
+func toDependenciesResponse(deps taskService.Dependencies) openapi.TaskDependencies {
+	return openapi.TaskDependencies{
+		Blockers:   toTaskList(deps.Blockers),
+		Dependents: toTaskList(deps.Dependents),
+	}
+}
+
+func toTaskEventResponse(e taskService.TaskEvent) openapi.TaskEvent {
+	resp := openapi.TaskEvent{
+		Id:        int64(e.ID),
+		TaskId:    int64(e.TaskID),
+		Type:      openapi.TaskEventType(e.Type),
+		Summary:   e.Summary(),
+		CreatedAt: e.CreatedAt,
+	}
+	if e.ActorID != nil {
+		resp.ActorId = int64Ptr(int64(*e.ActorID))
+	}
+	if e.OldValue != "" || e.NewValue != "" {
+		resp.OldValue, resp.NewValue = stringPtr(e.OldValue), stringPtr(e.NewValue)
+	}
+	return resp
+}
+
+func toTaskPage(tasks []taskService.Task, next string) openapi.TaskPage {
+	return openapi.TaskPage{Items: toTaskList(tasks), NextCursor: cursorPtr(next)}
+}
+
+// taskError переводит ошибки TaskService в HTTP-ошибки
+func taskError(err error, message string) error {
+	switch {
+	case errors.Is(err, identity.ErrUnauthenticated):
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	case errors.Is(err, taskService.ErrDependencyNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "dependency not found")
+	case errors.Is(err, taskService.ErrTagNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "tag not found")
+	case errors.Is(err, taskService.ErrTagNotAttached):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, taskService.ErrStatusNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "status not found")
+	case errors.Is(err, taskService.ErrAssigneeNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "task not found")
+	case errors.Is(err, taskService.ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, taskService.ErrInvalidPriority), errors.Is(err, taskService.ErrInvalidParent),
+		errors.Is(err, taskService.ErrInvalidProject), errors.Is(err, taskService.ErrInvalidTag),
+		errors.Is(err, taskService.ErrInvalidTagName), errors.Is(err, taskService.ErrInvalidRecurrence),
+		errors.Is(err, taskService.ErrInvalidScope), errors.Is(err, taskService.ErrRecurrenceScope),
+		errors.Is(err, taskService.ErrInvalidStatus), errors.Is(err, taskService.ErrInvalidStatusName),
+		errors.Is(err, taskService.ErrInvalidCategory), errors.Is(err, taskService.ErrInvalidStatusOrder),
+		errors.Is(err, taskService.ErrInvalidAssignee), errors.Is(err, taskService.ErrInvalidRole):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, taskService.ErrTaskCycle), errors.Is(err, taskService.ErrOpenSubtasks),
+		errors.Is(err, taskService.ErrDependencyCycle), errors.Is(err, taskService.ErrBlocked),
+		errors.Is(err, taskService.ErrProjectArchived), errors.Is(err, taskService.ErrTagExists),
+		errors.Is(err, taskService.ErrTransitionNotAllowed), errors.Is(err, taskService.ErrStatusInUse),
+		errors.Is(err, taskService.ErrStatusRequired), errors.Is(err, taskService.ErrParentDeleted):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	default:
+		return fmt.Errorf("%s: %w", message, err)
+	}
 }
 
 func int64Ptr(i int64) *int64    { return &i }
 func boolPtr(b bool) *bool       { return &b }
 func stringPtr(s string) *string { return &s }
+
+// cursorPtr возвращает nil для пустого курсора, чтобы next_cursor не попал в ответ
+func cursorPtr(next string) *string {
+	if next == "" {
+		return nil
+	}
+	return &next
+}
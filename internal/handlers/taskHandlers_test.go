@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"newproject/internal/identity"
+	"newproject/internal/pagination"
+	"newproject/internal/taskService"
+	"newproject/internal/tenant"
+	openapi "newproject/internal/web/tasks"
+	"path/filepath"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type noProjects struct{}
+
+func (noProjects) ProjectOwner(context.Context, uint) (uint, bool, error) {
+	return 0, false, gorm.ErrRecordNotFound
+}
+
+type anyUser struct{}
+
+func (anyUser) UserExists(context.Context, uint) (bool, error) {
+	return true, nil
+}
+
+func TestPatchTaskKeepsAbsentFields(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_txlock=immediate&_busy_timeout=5000"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.Use(tenant.Plugin{}); err != nil {
+		t.Fatalf("tenant plugin: %v", err)
+	}
+	err = db.AutoMigrate(&taskService.TaskStatus{}, &taskService.Task{}, &taskService.TaskDependency{},
+		&taskService.TaskAssignee{}, &taskService.TaskEvent{}, &taskService.Tag{}, &taskService.TaskSeries{})
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	tasks := taskService.NewTaskService(taskService.NewTaskRepository(db), noProjects{}, anyUser{}, taskService.CompletionBlock)
+	h := NewTaskHandler(tasks, nil, nil)
+	ctx := identity.WithCaller(tenant.WithWorkspace(context.Background(), 1), identity.Caller{UserID: 1, Role: "user"})
+
+	created, err := tasks.CreateTask(ctx, taskService.Task{Task: "write report", IsDone: true})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	high := openapi.TaskPriority("high")
+	got, err := h.PatchTasksId(ctx, int64(created.ID), openapi.PatchTasksIdParams{}, openapi.PatchTasksIdJSONRequestBody{Priority: &high})
+	if err != nil {
+		t.Fatalf("patch priority: %v", err)
+	}
+	if got.Task == nil || *got.Task != "write report" || got.IsDone == nil || !*got.IsDone {
+		t.Errorf("patch priority: got task %v, done %v, want them unchanged", got.Task, got.IsDone)
+	}
+	events, _, err := tasks.GetActivity(ctx, created.ID, pagination.Page{Limit: 10, Order: pagination.ByCreatedAt})
+	if err != nil {
+		t.Fatalf("activity: %v", err)
+	}
+	for _, event := range events {
+		if event.Type == taskService.EventRenamed || event.Type == taskService.EventReopened {
+			t.Errorf("patch priority: got %s event", event.Type)
+		}
+	}
+
+	blank := "  "
+	_, err = h.PatchTasksId(ctx, int64(created.ID), openapi.PatchTasksIdParams{}, openapi.PatchTasksIdJSONRequestBody{Task: &blank})
+	var httpErr *echo.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.Code != http.StatusBadRequest {
+		t.Errorf("empty name: got %v, want %d", err, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"newproject/internal/pagination"
+	"newproject/internal/policy"
+	"newproject/internal/taskService"
+	"newproject/internal/userService"
+	openapi "newproject/internal/web/trash"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type TrashHandler struct {
+	taskService *taskService.TaskService
+	userService *userService.UserService
+	policy      *policy.Policy
+}
+
+func NewTrashHandler(taskService *taskService.TaskService, userService *userService.UserService, policy *policy.Policy) *TrashHandler {
+	return &TrashHandler{
+		taskService: taskService,
+		userService: userService,
+		policy:      policy,
+	}
+}
+
+// GetTrash возвращает удаленные задачи или пользователей, недавние первыми.
+// Корзина пользователей доступна только администраторам.
+func (h *TrashHandler) GetTrash(ctx context.Context, params openapi.GetTrashParams) (openapi.TrashPage, error) {
+	page, err := pagination.NewPage(params.Limit, params.Cursor, pagination.ByDeletedAt)
+	if err != nil {
+		return openapi.TrashPage{}, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	var items []openapi.TrashItem
+	var next string
+	switch params.Type {
+	case openapi.TrashItemTypeTask:
+		tasks, cursor, err := h.taskService.GetDeletedTasks(ctx, page)
+		if err != nil {
+			return openapi.TrashPage{}, taskError(err, "error fetching deleted tasks")
+		}
+		items = make([]openapi.TrashItem, 0, len(tasks))
+		for _, t := range tasks {
+			items = append(items, openapi.TrashItem{
+				Type:      openapi.TrashItemTypeTask,
+				Id:        int64(t.ID),
+				Name:      t.Task,
+				DeletedAt: t.DeletedAt.Time,
+			})
+		}
+		next = cursor
+	case openapi.TrashItemTypeUser:
+		if err := h.policy.CanListUsers(ctx); err != nil {
+			return openapi.TrashPage{}, policyError(err)
+		}
+		users, cursor, err := h.userService.GetDeletedUsers(ctx, page)
+		if err != nil {
+			return openapi.TrashPage{}, fmt.Errorf("error fetching deleted users: %w", err)
+		}
+		items = make([]openapi.TrashItem, 0, len(users))
+		for _, u := range users {
+			items = append(items, openapi.TrashItem{
+				Type:      openapi.TrashItemTypeUser,
+				Id:        int64(u.ID),
+				Name:      u.Name,
+				DeletedAt: u.DeletedAt.Time,
+			})
+		}
+		next = cursor
+	default:
+		return openapi.TrashPage{}, echo.NewHTTPError(http.StatusBadRequest, "type must be task or user")
+	}
+	return openapi.TrashPage{Items: items, NextCursor: cursorPtr(next)}, nil
+}
+
+// PostTasksIdRestore возвращает задачу из корзины вместе с ее подзадачами
+func (h *TrashHandler) PostTasksIdRestore(ctx context.Context, id int64) error {
+	if _, err := h.taskService.RestoreTask(ctx, uint(id)); err != nil {
+		return taskError(err, "error restoring task")
+	}
+	return nil
+}
+
+// PostUsersIdRestore возвращает пользователя из корзины
+func (h *TrashHandler) PostUsersIdRestore(ctx context.Context, id int64) error {
+	if err := h.policy.CanRestoreUser(ctx); err != nil {
+		return policyError(err)
+	}
+
+	_, err := h.userService.RestoreUser(ctx, uint(id))
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "User not found")
+	case errors.Is(err, userService.ErrEmailTaken):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case err != nil:
+		return fmt.Errorf("error restoring user: %w", err)
+	}
+	return nil
+}
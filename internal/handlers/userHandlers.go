@@ -4,8 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"newproject/internal/identity"
 	"newproject/internal/models"
+	"newproject/internal/pagination"
+	"newproject/internal/policy"
+	"newproject/internal/taskService"
+	"newproject/internal/tenant"
 	"newproject/internal/userService"
+	"newproject/internal/workspaceService"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
@@ -13,36 +19,48 @@ import (
 )
 
 type UserHandler struct {
-	userService *userService.UserService
+	userService      *userService.UserService
+	workspaceService *workspaceService.WorkspaceService
+	policy           *policy.Policy
 }
 
-type UpdateUserRequest struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
-}
-
-func NewUserHandler(userService *userService.UserService) *UserHandler {
+func NewUserHandler(userService *userService.UserService, workspaceService *workspaceService.WorkspaceService, policy *policy.Policy) *UserHandler {
 	return &UserHandler{
-		userService: userService,
+		userService:      userService,
+		workspaceService: workspaceService,
+		policy:           policy,
 	}
 }
 
 func (h *UserHandler) GetUsers(ctx echo.Context) error {
-	users, err := h.userService.GetAllUsers()
+	if err := h.policy.CanListUsers(ctx.Request().Context()); err != nil {
+		return policyError(err)
+	}
+
+	page, err := pageFromQuery(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	users, next, err := h.userService.GetAllUsers(ctx.Request().Context(), page)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Error fetching users: %s", err))
 	}
 
-	return ctx.JSON(http.StatusOK, users)
+	return ctx.JSON(http.StatusOK, pagination.NewEnvelope(users, next))
 }
 
 func (h *UserHandler) PostUsers(ctx echo.Context) error {
-	var request models.User
+	var request models.NewUserRequest
 	if err := ctx.Bind(&request); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid input: %s", err))
 	}
 
-	user, err := h.userService.CreateUser(request)
+	user, err := h.userService.CreateUser(ctx.Request().Context(), models.User{
+		Name:     request.Name,
+		Email:    request.Email,
+		Password: request.Password,
+	})
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Error creating user: %s", err))
 	}
@@ -50,16 +68,47 @@ func (h *UserHandler) PostUsers(ctx echo.Context) error {
 	return ctx.JSON(http.StatusCreated, user)
 }
 
+// DeleteUsersId исключает пользователя из рабочего пространства запроса.
+// Учетная запись и участие в других пространствах остаются.
 func (h *UserHandler) DeleteUsersId(ctx echo.Context) error {
-	idStr := ctx.Param("id")
-	id, err := strconv.ParseInt(idStr, 10, 64)
+	id, reassignTo, err := deleteUserParams(ctx)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid user ID")
+		return err
+	}
+	workspaceID, err := tenant.Require(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	err = h.workspaceService.RemoveMember(ctx.Request().Context(), workspaceID, id, reassignTo)
+	switch {
+	case errors.Is(err, workspaceService.ErrMemberNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "User not found")
+	case errors.Is(err, taskService.ErrInvalidAssignee):
+		return echo.NewHTTPError(http.StatusBadRequest, "reassign_to must be another member of the workspace")
+	case err != nil:
+		return workspaceError(err, "error removing user from the workspace")
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// DeleteUsersIdAccount удаляет учетную запись пользователя, и он пропадает
+// из всех рабочих пространств
+func (h *UserHandler) DeleteUsersIdAccount(ctx echo.Context) error {
+	id, reassignTo, err := deleteUserParams(ctx)
+	if err != nil {
+		return err
 	}
 
-	err = h.userService.DeleteUserByID(uint(id))
+	if err := h.policy.CanDeleteAccount(ctx.Request().Context(), id); err != nil {
+		return policyError(err)
+	}
+
+	err = h.userService.DeleteUserByID(ctx.Request().Context(), id, reassignTo)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return echo.NewHTTPError(http.StatusNotFound, "User not found")
+	} else if errors.Is(err, taskService.ErrInvalidAssignee) {
+		return echo.NewHTTPError(http.StatusBadRequest, "reassign_to must be another member of the workspace")
 	} else if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Error deleting user: %s", err))
 	}
@@ -67,6 +116,26 @@ func (h *UserHandler) DeleteUsersId(ctx echo.Context) error {
 	return ctx.NoContent(http.StatusNoContent)
 }
 
+// deleteUserParams читает ID пользователя и необязательный reassign_to —
+// того, кто получит задачи, где удаляемый пользователь был исполнителем
+func deleteUserParams(ctx echo.Context) (uint, *uint, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return 0, nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid user ID")
+	}
+
+	var reassignTo *uint
+	if raw := ctx.QueryParam("reassign_to"); raw != "" {
+		to, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return 0, nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid reassign_to")
+		}
+		userID := uint(to)
+		reassignTo = &userID
+	}
+	return uint(id), reassignTo, nil
+}
+
 func (h *UserHandler) PatchUsersId(ctx echo.Context) error {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
@@ -74,18 +143,41 @@ func (h *UserHandler) PatchUsersId(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid user ID")
 	}
 
-	var request UpdateUserRequest
+	var request models.UpdateUserRequest
 	if err := ctx.Bind(&request); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid input: %s", err))
 	}
 
-	updatedUser, err := h.userService.UpdateUserByID(uint(id), models.User{
-		ID:    uint(id),
-		Name:  request.Name,
-		Email: request.Email,
-	})
+	if err := h.policy.CanUpdateUser(ctx.Request().Context(), uint(id)); err != nil {
+		return policyError(err)
+	}
+	if request.Role != nil {
+		if err := h.policy.CanChangeRole(ctx.Request().Context(), uint(id), *request.Role); err != nil {
+			return policyError(err)
+		}
+	}
+
+	user := models.User{ID: uint(id)}
+	if request.Name != nil {
+		user.Name = *request.Name
+	}
+	if request.Email != nil {
+		user.Email = *request.Email
+	}
+	if request.Password != nil {
+		user.Password = *request.Password
+	}
+	if request.Role != nil {
+		user.Role = *request.Role
+	}
+
+	updatedUser, err := h.userService.UpdateUserByID(ctx.Request().Context(), uint(id), user)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return echo.NewHTTPError(http.StatusNotFound, "User not found")
+	} else if errors.Is(err, userService.ErrInvalidRole) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	} else if errors.Is(err, userService.ErrEmailTaken) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
 	} else if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Error updating user: %s", err))
 	}
@@ -100,12 +192,90 @@ func (h *UserHandler) GetUsersIdTasks(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid user ID")
 	}
 
-	tasks, err := h.userService.GetUserTasks(uint(id))
+	page, err := pageFromQuery(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	// relation=assigned и relation=watching отдают чужие задачи, где пользователь участник
+	relation := ctx.QueryParam("relation")
+	switch relation {
+	case "", "owned":
+	case "assigned", "watching":
+		if view := ctx.QueryParam("view"); view != "" && view != "flat" {
+			return echo.NewHTTPError(http.StatusBadRequest, "view=tree is only available for owned tasks")
+		}
+		role := taskService.RoleAssignee
+		if relation == "watching" {
+			role = taskService.RoleWatcher
+		}
+		tasks, next, err := h.userService.GetUserAssignedTasks(ctx.Request().Context(), uint(id), role, page)
+		if err != nil {
+			return userTasksError(err)
+		}
+		return ctx.JSON(http.StatusOK, pagination.NewEnvelope(tasks, next))
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "relation must be owned, assigned or watching")
+	}
+
+	// view=tree отдает корневые задачи с вложенными подзадачами
+	switch ctx.QueryParam("view") {
+	case "", "flat":
+		tasks, next, err := h.userService.GetUserTasks(ctx.Request().Context(), uint(id), page)
+		if err != nil {
+			return userTasksError(err)
+		}
+		return ctx.JSON(http.StatusOK, pagination.NewEnvelope(tasks, next))
+	case "tree":
+		nodes, next, err := h.userService.GetUserTaskTree(ctx.Request().Context(), uint(id), page)
+		if err != nil {
+			return userTasksError(err)
+		}
+		return ctx.JSON(http.StatusOK, pagination.NewEnvelope(nodes, next))
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "view must be flat or tree")
+	}
+}
+
+// userTasksError переводит ошибки выборки задач пользователя в HTTP-ошибки
+func userTasksError(err error) error {
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return echo.NewHTTPError(http.StatusNotFound, "User not found")
-	} else if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Error fetching tasks for user: %s", err))
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Error fetching tasks for user: %s", err))
+}
+
+// pageFromQuery читает параметры limit и cursor из строки запроса
+func pageFromQuery(ctx echo.Context) (pagination.Page, error) {
+	var limit *int
+	if raw := ctx.QueryParam("limit"); raw != "" {
+		l, err := strconv.Atoi(raw)
+		if err != nil {
+			return pagination.Page{}, pagination.ErrInvalidLimit
+		}
+		limit = &l
+	}
+
+	var cursor *string
+	if raw := ctx.QueryParam("cursor"); raw != "" {
+		cursor = &raw
 	}
 
-	return ctx.JSON(http.StatusOK, tasks)
+	return pagination.NewPage(limit, cursor, pagination.ByCreatedAt)
+}
+
+// policyError переводит отказ Policy в HTTP-ошибку
+func policyError(err error) error {
+	switch {
+	case errors.Is(err, identity.ErrUnauthenticated):
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	case errors.Is(err, policy.ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, policy.ErrLastAdmin):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "User not found")
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
 }
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"newproject/internal/pagination"
+	"newproject/internal/policy"
+	openapi "newproject/internal/web/webhooks"
+	"newproject/internal/webhookService"
+
+	"github.com/labstack/echo/v4"
+)
+
+type WebhookHandler struct {
+	webhookService *webhookService.WebhookService
+	policy         *policy.Policy
+}
+
+func NewWebhookHandler(webhookService *webhookService.WebhookService, policy *policy.Policy) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		policy:         policy,
+	}
+}
+
+// GetWebhooks возвращает подписки рабочего пространства
+func (h *WebhookHandler) GetWebhooks(ctx context.Context) (openapi.WebhookList, error) {
+	if err := h.policy.CanManageWebhooks(ctx); err != nil {
+		return openapi.WebhookList{}, policyError(err)
+	}
+
+	webhooks, err := h.webhookService.GetWebhooks(ctx)
+	if err != nil {
+		return openapi.WebhookList{}, fmt.Errorf("error fetching webhooks: %w", err)
+	}
+
+	items := make([]openapi.Webhook, 0, len(webhooks))
+	for _, s := range webhooks {
+		items = append(items, toWebhookResponse(s))
+	}
+	return openapi.WebhookList{Items: items}, nil
+}
+
+// PostWebhooks подписывает адрес на события. Секрет отдается только в этом ответе.
+func (h *WebhookHandler) PostWebhooks(ctx context.Context, req openapi.NewWebhookRequest) (openapi.Webhook, error) {
+	if err := h.policy.CanManageWebhooks(ctx); err != nil {
+		return openapi.Webhook{}, policyError(err)
+	}
+
+	events := make([]string, len(req.Events))
+	for i, e := range req.Events {
+		events[i] = string(e)
+	}
+	var secret string
+	if req.Secret != nil {
+		secret = *req.Secret
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(ctx, req.Url, events, secret)
+	if err != nil {
+		return openapi.Webhook{}, webhookError(err, "error creating webhook")
+	}
+	resp := toWebhookResponse(webhook)
+	resp.Secret = stringPtr(webhook.Secret)
+	return resp, nil
+}
+
+// DeleteWebhooksId удаляет подписку
+func (h *WebhookHandler) DeleteWebhooksId(ctx context.Context, id int64) error {
+	if err := h.policy.CanManageWebhooks(ctx); err != nil {
+		return policyError(err)
+	}
+
+	if err := h.webhookService.DeleteWebhook(ctx, uint(id)); err != nil {
+		return webhookError(err, "error deleting webhook")
+	}
+	return nil
+}
+
+// GetWebhooksIdDeliveries возвращает журнал доставок подписки, новые первыми
+func (h *WebhookHandler) GetWebhooksIdDeliveries(ctx context.Context, id int64, params openapi.GetWebhooksIdDeliveriesParams) (openapi.WebhookDeliveryPage, error) {
+	if err := h.policy.CanManageWebhooks(ctx); err != nil {
+		return openapi.WebhookDeliveryPage{}, policyError(err)
+	}
+
+	page, err := pagination.NewPage(params.Limit, params.Cursor, pagination.ByNewest)
+	if err != nil {
+		return openapi.WebhookDeliveryPage{}, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	deliveries, next, err := h.webhookService.GetDeliveries(ctx, uint(id), page)
+	if err != nil {
+		return openapi.WebhookDeliveryPage{}, webhookError(err, "error fetching deliveries")
+	}
+
+	items := make([]openapi.WebhookDelivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		items = append(items, toDeliveryResponse(d))
+	}
+	return openapi.WebhookDeliveryPage{Items: items, NextCursor: cursorPtr(next)}, nil
+}
+
+// PostWebhooksIdDeliveriesDeliveryIdRedeliver ставит событие доставки в очередь еще раз
+func (h *WebhookHandler) PostWebhooksIdDeliveriesDeliveryIdRedeliver(ctx context.Context, id int64, deliveryId int64) (openapi.WebhookDelivery, error) {
+	if err := h.policy.CanManageWebhooks(ctx); err != nil {
+		return openapi.WebhookDelivery{}, policyError(err)
+	}
+
+	delivery, err := h.webhookService.Redeliver(ctx, uint(id), uint(deliveryId))
+	if err != nil {
+		return openapi.WebhookDelivery{}, webhookError(err, "error redelivering webhook")
+	}
+	return toDeliveryResponse(delivery), nil
+}
+
+func toWebhookResponse(s webhookService.Webhook) openapi.Webhook {
+	events := make([]openapi.WebhookEvent, 0)
+	for _, e := range s.EventList() {
+		events = append(events, openapi.WebhookEvent(e))
+	}
+	return openapi.Webhook{
+		Id:        int64(s.ID),
+		Url:       s.URL,
+		Events:    events,
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+func toDeliveryResponse(d webhookService.WebhookDelivery) openapi.WebhookDelivery {
+	resp := openapi.WebhookDelivery{
+		Id:            int64(d.ID),
+		WebhookId:     int64(d.WebhookID),
+		EventId:       int64(d.EventID),
+		Event:         openapi.WebhookEvent(d.Event),
+		Status:        openapi.WebhookDeliveryStatus(d.Status),
+		Attempts:      d.Attempts,
+		ResponseCode:  d.ResponseCode,
+		NextAttemptAt: d.NextAttemptAt,
+		DeliveredAt:   d.DeliveredAt,
+		CreatedAt:     d.CreatedAt,
+	}
+	if payload := jsonObject(&d.Payload); payload != nil {
+		resp.Payload = *payload
+	}
+	if d.Error != "" {
+		resp.Error = stringPtr(d.Error)
+	}
+	return resp
+}
+
+// webhookError переводит ошибки WebhookService в HTTP-ошибки
+func webhookError(err error, message string) error {
+	switch {
+	case errors.Is(err, webhookService.ErrWebhookNotFound), errors.Is(err, webhookService.ErrDeliveryNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, webhookService.ErrInvalidURL), errors.Is(err, webhookService.ErrInvalidEvents),
+		errors.Is(err, webhookService.ErrInvalidSecret):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return fmt.Errorf("%s: %w", message, err)
+	}
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"newproject/internal/identity"
+	openapi "newproject/internal/web/workspaces"
+	"newproject/internal/workspaceService"
+
+	"github.com/labstack/echo/v4"
+)
+
+type WorkspaceHandler struct {
+	workspaceService *workspaceService.WorkspaceService
+}
+
+func NewWorkspaceHandler(workspaceService *workspaceService.WorkspaceService) *WorkspaceHandler {
+	return &WorkspaceHandler{workspaceService: workspaceService}
+}
+
+// GetWorkspaces возвращает пространства вызывающего
+func (h *WorkspaceHandler) GetWorkspaces(ctx context.Context) (openapi.WorkspaceList, error) {
+	workspaces, err := h.workspaceService.GetWorkspaces(ctx)
+	if err != nil {
+		return openapi.WorkspaceList{}, workspaceError(err, "error fetching workspaces")
+	}
+
+	items := make([]openapi.Workspace, 0, len(workspaces))
+	for _, w := range workspaces {
+		items = append(items, toWorkspaceResponse(w))
+	}
+	return openapi.WorkspaceList{Items: items}, nil
+}
+
+// PostWorkspaces создает пространство, владельцем которого становится вызывающий
+func (h *WorkspaceHandler) PostWorkspaces(ctx context.Context, req openapi.WorkspaceRequest) (openapi.Workspace, error) {
+	workspace, err := h.workspaceService.CreateWorkspace(ctx, req.Name)
+	if err != nil {
+		log.Printf("Error creating workspace: %v", err)
+		return openapi.Workspace{}, workspaceError(err, "error creating workspace")
+	}
+	return toWorkspaceResponse(workspace), nil
+}
+
+// PatchWorkspacesId переименовывает пространство
+func (h *WorkspaceHandler) PatchWorkspacesId(ctx context.Context, id int64, req openapi.WorkspaceRequest) (openapi.Workspace, error) {
+	workspace, err := h.workspaceService.UpdateWorkspace(ctx, uint(id), req.Name)
+	if err != nil {
+		log.Printf("Error updating workspace with ID %d: %v", id, err)
+		return openapi.Workspace{}, workspaceError(err, "error updating workspace")
+	}
+	return toWorkspaceResponse(workspace), nil
+}
+
+// GetWorkspacesIdMembers возвращает участников пространства
+func (h *WorkspaceHandler) GetWorkspacesIdMembers(ctx context.Context, id int64) (openapi.WorkspaceMemberList, error) {
+	members, err := h.workspaceService.GetMembers(ctx, uint(id))
+	if err != nil {
+		return openapi.WorkspaceMemberList{}, workspaceError(err, "error fetching members")
+	}
+
+	items := make([]openapi.WorkspaceMember, 0, len(members))
+	for _, m := range members {
+		items = append(items, toWorkspaceMemberResponse(m))
+	}
+	return openapi.WorkspaceMemberList{Items: items}, nil
+}
+
+// PutWorkspacesIdMembersUserId добавляет участника или меняет его роль
+func (h *WorkspaceHandler) PutWorkspacesIdMembersUserId(ctx context.Context, id int64, userId int64, req openapi.WorkspaceMemberRequest) (openapi.WorkspaceMember, error) {
+	member, err := h.workspaceService.SetMember(ctx, uint(id), uint(userId), string(req.Role))
+	if err != nil {
+		log.Printf("Error setting member %d of workspace %d: %v", userId, id, err)
+		return openapi.WorkspaceMember{}, workspaceError(err, "error setting member")
+	}
+	return toWorkspaceMemberResponse(member), nil
+}
+
+// DeleteWorkspacesIdMembersUserId исключает участника из пространства
+func (h *WorkspaceHandler) DeleteWorkspacesIdMembersUserId(ctx context.Context, id int64, userId int64) error {
+	if err := h.workspaceService.RemoveMember(ctx, uint(id), uint(userId), nil); err != nil {
+		return workspaceError(err, "error removing member")
+	}
+	return nil
+}
+
+// toWorkspaceResponse преобразует workspaceService.WorkspaceSummary в openapi.Workspace
+func toWorkspaceResponse(w workspaceService.WorkspaceSummary) openapi.Workspace {
+	resp := openapi.Workspace{
+		Id:        int64(w.ID),
+		Name:      w.Name,
+		IsDefault: w.IsDefault,
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	}
+	if w.Role != "" {
+		role := openapi.WorkspaceRole(w.Role)
+		resp.Role = &role
+	}
+	return resp
+}
+
+// toWorkspaceMemberResponse преобразует workspaceService.WorkspaceMember в openapi.WorkspaceMember
+func toWorkspaceMemberResponse(m workspaceService.WorkspaceMember) openapi.WorkspaceMember {
+	return openapi.WorkspaceMember{
+		WorkspaceId: int64(m.WorkspaceID),
+		UserId:      int64(m.UserID),
+		Role:        openapi.WorkspaceRole(m.Role),
+		CreatedAt:   m.CreatedAt,
+	}
+}
+
+// workspaceError переводит ошибки WorkspaceService в HTTP-ошибки
+func workspaceError(err error, message string) error {
+	switch {
+	case errors.Is(err, identity.ErrUnauthenticated):
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	case errors.Is(err, workspaceService.ErrWorkspaceNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "workspace not found")
+	case errors.Is(err, workspaceService.ErrMemberNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, workspaceService.ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, workspaceService.ErrLastOwner):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, workspaceService.ErrInvalidName), errors.Is(err, workspaceService.ErrInvalidRole),
+		errors.Is(err, workspaceService.ErrInvalidMember):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return fmt.Errorf("%s: %w", message, err)
+	}
+}
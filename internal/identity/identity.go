@@ -0,0 +1,65 @@
+package identity
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"newproject/internal/models"
+)
+
+var (
+	// ErrNoCredentials — запрос не содержит учетных данных
+	ErrNoCredentials = errors.New("missing credentials")
+	// ErrUnauthenticated — в контексте нет вызывающего
+	ErrUnauthenticated = errors.New("unauthenticated")
+)
+
+// Caller — тот, от чьего имени выполняется запрос
+type Caller struct {
+	UserID uint
+	Email  string
+	// Role — роль в системе
+	Role string
+	// WorkspaceRole — роль в рабочем пространстве запроса, ее заполняет
+	// tenant.Middleware
+	WorkspaceRole string
+}
+
+// IsAdmin сообщает, управляет ли вызывающий рабочим пространством запроса:
+// он его владелец или администратор. Роль в системе здесь не учитывается.
+func (c Caller) IsAdmin() bool {
+	return c.WorkspaceRole == models.WorkspaceRoleOwner || c.WorkspaceRole == models.WorkspaceRoleAdmin
+}
+
+// IsPlatformAdmin сообщает, является ли вызывающий администратором системы
+func (c Caller) IsPlatformAdmin() bool {
+	return c.Role == models.RoleAdmin
+}
+
+// Authenticator определяет вызывающего по HTTP-запросу.
+// Если учетных данных нет, возвращает ErrNoCredentials.
+type Authenticator interface {
+	Authenticate(r *http.Request) (Caller, error)
+}
+
+type callerContextKey struct{}
+
+// WithCaller кладет вызывающего в контекст
+func WithCaller(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, callerContextKey{}, caller)
+}
+
+// FromContext достает вызывающего из контекста
+func FromContext(ctx context.Context) (Caller, bool) {
+	caller, ok := ctx.Value(callerContextKey{}).(Caller)
+	return caller, ok
+}
+
+// Require возвращает вызывающего или ErrUnauthenticated, если его нет
+func Require(ctx context.Context) (Caller, error) {
+	caller, ok := FromContext(ctx)
+	if !ok || caller.UserID == 0 {
+		return Caller{}, ErrUnauthenticated
+	}
+	return caller, nil
+}
@@ -0,0 +1,38 @@
+package identity
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// Middleware определяет вызывающего с помощью authenticator и кладет его
+// в контекст запроса, откуда его читают strict-хендлеры и сервисы.
+// Маршруты, для которых skipper вернул true, пропускаются без проверки.
+func Middleware(authenticator Authenticator, skipper middleware.Skipper) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if skipper != nil && skipper(c) {
+				return next(c)
+			}
+
+			caller, err := authenticator.Authenticate(c.Request())
+			if errors.Is(err, ErrNoCredentials) {
+				return unauthorized(c, "missing credentials")
+			} else if err != nil {
+				return unauthorized(c, "invalid or expired credentials")
+			}
+
+			ctx := WithCaller(c.Request().Context(), caller)
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
+func unauthorized(c echo.Context, message string) error {
+	c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+	return echo.NewHTTPError(http.StatusUnauthorized, message)
+}
@@ -0,0 +1,98 @@
+package identity
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// tokens — Authenticator по таблице: токен из заголовка → вызывающий
+type tokens map[string]Caller
+
+func (t tokens) Authenticate(r *http.Request) (Caller, error) {
+	token := r.Header.Get("Authorization")
+	if token == "" {
+		return Caller{}, ErrNoCredentials
+	}
+	caller, ok := t[token]
+	if !ok {
+		return Caller{}, errors.New("invalid token")
+	}
+	return caller, nil
+}
+
+func TestMiddleware(t *testing.T) {
+	authenticator := tokens{"good": {UserID: 1, Email: "a@x", Role: "user"}}
+	skipper := func(c echo.Context) bool { return c.Path() == "/public" }
+
+	tests := []struct {
+		name  string
+		path  string
+		token string
+		code  int
+		want  uint
+	}{
+		{name: "valid token", path: "/data", token: "good", code: http.StatusOK, want: 1},
+		{name: "no token", path: "/data", code: http.StatusUnauthorized},
+		{name: "invalid token", path: "/data", token: "bad", code: http.StatusUnauthorized},
+		{name: "skipped", path: "/public", code: http.StatusOK},
+		{name: "skipped with invalid token", path: "/public", token: "bad", code: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			var got Caller
+			var found bool
+			handler := func(c echo.Context) error {
+				got, found = FromContext(c.Request().Context())
+				return c.NoContent(http.StatusOK)
+			}
+			e.Use(Middleware(authenticator, skipper))
+			e.GET(tt.path, handler)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", tt.token)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.code {
+				t.Fatalf("code: got %d, want %d", rec.Code, tt.code)
+			}
+			if tt.code == http.StatusUnauthorized && rec.Header().Get(echo.HeaderWWWAuthenticate) != "Bearer" {
+				t.Errorf("WWW-Authenticate: got %q, want Bearer", rec.Header().Get(echo.HeaderWWWAuthenticate))
+			}
+			if tt.want != 0 && (!found || got.UserID != tt.want) {
+				t.Errorf("caller: got %+v, %v, want user %d", got, found, tt.want)
+			}
+			if tt.want == 0 && found {
+				t.Errorf("caller: got %+v, want none", got)
+			}
+		})
+	}
+}
+
+func TestRequire(t *testing.T) {
+	tests := []struct {
+		name    string
+		caller  *Caller
+		wantErr error
+	}{
+		{name: "caller", caller: &Caller{UserID: 1}},
+		{name: "no caller", wantErr: ErrUnauthenticated},
+		{name: "zero user", caller: &Caller{}, wantErr: ErrUnauthenticated},
+	}
+	for _, tt := range tests {
+		ctx := httptest.NewRequest(http.MethodGet, "/", nil).Context()
+		if tt.caller != nil {
+			ctx = WithCaller(ctx, *tt.caller)
+		}
+		if _, err := Require(ctx); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
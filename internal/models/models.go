@@ -1,10 +1,24 @@
 package models
 
+// Роли пользователей в системе
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// Роли участников рабочего пространства
+const (
+	WorkspaceRoleOwner  = "owner"
+	WorkspaceRoleAdmin  = "admin"
+	WorkspaceRoleMember = "member"
+)
+
 type User struct {
 	ID       uint   `json:"id"`
 	Name     string `json:"name"`
 	Email    string `json:"email"`
-	Password string `json:"password"`
+	Password string `json:"-"`
+	Role     string `json:"role"`
 	Tasks    []Task `json:"tasks"`
 }
 
@@ -22,8 +36,15 @@ type NewTaskRequest struct {
 	UserId uint   `json:"user_id"`
 }
 
+type NewUserRequest struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
 type UpdateUserRequest struct {
 	Name     *string `json:"name,omitempty"`
 	Email    *string `json:"email,omitempty"`
 	Password *string `json:"password,omitempty"`
+	Role     *string `json:"role,omitempty"`
 }
@@ -0,0 +1,91 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// defaultBusDedupSize — сколько последних ключей помнит шина
+const defaultBusDedupSize = 4096
+
+// Handler обрабатывает сообщение шины. Он вызывается в горутине
+// ретранслятора и не должен надолго ее задерживать.
+type Handler func(ctx context.Context, msg OutboxMessage) error
+
+// Bus раздает сообщения подписчикам внутри процесса. Повторы отбрасываются
+// по ключу среди последних опубликованных, так что подписчики видят
+// сообщение один раз, если повтор пришел не слишком поздно.
+type Bus struct {
+	mu       sync.Mutex
+	handlers map[int]Handler
+	nextID   int
+	// seen и ring — множество последних ключей и порядок их вытеснения
+	seen map[string]bool
+	ring []string
+	pos  int
+}
+
+// NewBus создает шину, помнящую dedupSize последних ключей
+// (defaultBusDedupSize, если dedupSize не больше нуля)
+func NewBus(dedupSize int) *Bus {
+	if dedupSize <= 0 {
+		dedupSize = defaultBusDedupSize
+	}
+	return &Bus{
+		handlers: make(map[int]Handler),
+		seen:     make(map[string]bool, dedupSize),
+		ring:     make([]string, dedupSize),
+	}
+}
+
+// Subscribe подписывает обработчик на все сообщения шины.
+// Возвращенная функция отменяет подписку.
+func (b *Bus) Subscribe(handler Handler) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = handler
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.handlers, id)
+	}
+}
+
+// Publish передает сообщение всем подписчикам. Ключ запоминается, только
+// если все они справились: иначе повтор дойдет до всех еще раз.
+func (b *Bus) Publish(ctx context.Context, msg OutboxMessage) error {
+	b.mu.Lock()
+	if b.seen[msg.Key] {
+		b.mu.Unlock()
+		return nil
+	}
+	handlers := make([]Handler, 0, len(b.handlers))
+	for _, handler := range b.handlers {
+		handlers = append(handlers, handler)
+	}
+	b.mu.Unlock()
+
+	var errs []error
+	for _, handler := range handlers {
+		if err := handler(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.seen[msg.Key] {
+		delete(b.seen, b.ring[b.pos])
+		b.ring[b.pos] = msg.Key
+		b.seen[msg.Key] = true
+		b.pos = (b.pos + 1) % len(b.ring)
+	}
+	return nil
+}
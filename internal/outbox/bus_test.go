@@ -0,0 +1,62 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestBusRetriesAllSubscribersAfterPartialFailure(t *testing.T) {
+	bus := NewBus(0)
+	var healthy, flaky int
+	bus.Subscribe(func(context.Context, OutboxMessage) error {
+		healthy++
+		return nil
+	})
+	bus.Subscribe(func(context.Context, OutboxMessage) error {
+		flaky++
+		if flaky == 1 {
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+
+	msg := OutboxMessage{ID: 1, Key: "audit-1", Event: EventTaskCreated}
+	tests := []struct {
+		name           string
+		wantErr        bool
+		healthy, flaky int
+	}{
+		{name: "first delivery fails on one subscriber", wantErr: true, healthy: 1, flaky: 1},
+		{name: "retry reaches every subscriber", healthy: 2, flaky: 2},
+		{name: "delivered message is dropped", healthy: 2, flaky: 2},
+	}
+	for _, tt := range tests {
+		err := bus.Publish(context.Background(), msg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: got error %v, want error %v", tt.name, err, tt.wantErr)
+		}
+		if healthy != tt.healthy || flaky != tt.flaky {
+			t.Errorf("%s: got calls %d/%d, want %d/%d", tt.name, healthy, flaky, tt.healthy, tt.flaky)
+		}
+	}
+}
+
+func TestBusForgetsOldestKeys(t *testing.T) {
+	bus := NewBus(2)
+	calls := 0
+	bus.Subscribe(func(context.Context, OutboxMessage) error {
+		calls++
+		return nil
+	})
+
+	for _, key := range []string{"a", "b", "a", "c", "a"} {
+		if err := bus.Publish(context.Background(), OutboxMessage{Key: key}); err != nil {
+			t.Fatalf("publish %s: %v", key, err)
+		}
+	}
+	// Повтор "a" отброшен, пока ключ помнится; после "c" он вытеснен
+	if calls != 4 {
+		t.Errorf("calls: got %d, want 4", calls)
+	}
+}
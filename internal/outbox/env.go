@@ -0,0 +1,17 @@
+package outbox
+
+import "os"
+
+// NATSFromEnv настраивает публикацию в брокер по NATS_URL и NATS_SUBJECT_PREFIX
+// (по умолчанию newproject). Без NATS_URL возвращает nil: брокер не нужен.
+func NATSFromEnv() (*NATSPublisher, error) {
+	natsURL := os.Getenv("NATS_URL")
+	if natsURL == "" {
+		return nil, nil
+	}
+	prefix, ok := os.LookupEnv("NATS_SUBJECT_PREFIX")
+	if !ok {
+		prefix = "newproject"
+	}
+	return NewNATSPublisher(NATSConfig{URL: natsURL, SubjectPrefix: prefix})
+}
@@ -0,0 +1,141 @@
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"newproject/internal/auditService"
+
+	"gorm.io/gorm"
+)
+
+// data — поле Data сообщения
+type data struct {
+	ID     uint            `json:"id"`
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// Plugin записывает в outbox события по записям журнала аудита, сделанным
+// тем же запросом. Сообщения пишутся в транзакции изменения, поэтому событие
+// не теряется при падении между изменением и публикацией.
+// Подключается после auditService.Plugin. Окончательное удаление из корзины
+// событий не порождает.
+type Plugin struct{}
+
+func (Plugin) Name() string {
+	return "outbox"
+}
+
+func (Plugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().After("audit:create").Before("gorm:commit_or_rollback_transaction").
+		Register("outbox:create", write); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("audit:update").Before("gorm:commit_or_rollback_transaction").
+		Register("outbox:update", write); err != nil {
+		return err
+	}
+	return callbacks.Delete().After("audit:delete").Before("gorm:commit_or_rollback_transaction").
+		Register("outbox:delete", write)
+}
+
+// write записывает события запроса в outbox
+func write(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	entries := auditService.Entries(db)
+	if len(entries) == 0 {
+		return
+	}
+
+	var messages []OutboxMessage
+	for _, entry := range entries {
+		event := eventOf(entry)
+		if event == "" {
+			continue
+		}
+		payload, err := json.Marshal(data{ID: entry.EntityID, Before: raw(entry.Before), After: raw(entry.After)})
+		if err != nil {
+			db.AddError(fmt.Errorf("outbox: %w", err))
+			return
+		}
+		messages = append(messages, OutboxMessage{
+			// Одна запись журнала дает не больше одного события
+			Key:           fmt.Sprintf("audit-%d", entry.ID),
+			Event:         event,
+			WorkspaceID:   entry.WorkspaceID,
+			ActorID:       entry.ActorID,
+			Data:          string(payload),
+			NextAttemptAt: entry.CreatedAt,
+			CreatedAt:     entry.CreatedAt,
+		})
+	}
+	if len(messages) == 0 {
+		return
+	}
+
+	if err := db.Session(&gorm.Session{NewDB: true}).Create(&messages).Error; err != nil {
+		db.AddError(fmt.Errorf("outbox: %w", err))
+	}
+}
+
+// eventOf возвращает событие для записи журнала или пустую строку
+func eventOf(entry auditService.AuditEntry) string {
+	switch entry.Entity + ":" + entry.Action {
+	case auditService.EntityTask + ":" + auditService.ActionCreate:
+		return EventTaskCreated
+	case auditService.EntityTask + ":" + auditService.ActionUpdate:
+		if becameDone(entry) {
+			return EventTaskCompleted
+		}
+		return EventTaskUpdated
+	case auditService.EntityTask + ":" + auditService.ActionRestore:
+		return EventTaskRestored
+	case auditService.EntityUser + ":" + auditService.ActionCreate:
+		return EventUserCreated
+	case auditService.EntityUser + ":" + auditService.ActionUpdate:
+		return EventUserUpdated
+	case auditService.EntityUser + ":" + auditService.ActionRestore:
+		return EventUserRestored
+	}
+
+	// Удалением считается только перенос в корзину: у очистки корзины нет «после»
+	if entry.Action == auditService.ActionDelete && entry.After != nil {
+		switch entry.Entity {
+		case auditService.EntityTask:
+			return EventTaskDeleted
+		case auditService.EntityUser:
+			return EventUserDeleted
+		}
+	}
+	return ""
+}
+
+// becameDone сообщает, стала ли задача выполненной. В записи журнала есть
+// только изменившиеся колонки, так что is_done в «после» означает переход.
+func becameDone(entry auditService.AuditEntry) bool {
+	if entry.After == nil {
+		return false
+	}
+	var after map[string]any
+	if err := json.Unmarshal([]byte(*entry.After), &after); err != nil {
+		return false
+	}
+	// Драйверы отдают булевы колонки по-разному: true или 1
+	switch done := after["is_done"].(type) {
+	case bool:
+		return done
+	case float64:
+		return done != 0
+	}
+	return false
+}
+
+func raw(s *string) json.RawMessage {
+	if s == nil {
+		return nil
+	}
+	return json.RawMessage(*s)
+}
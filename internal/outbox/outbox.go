@@ -0,0 +1,91 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// События о задачах и пользователях
+const (
+	EventTaskCreated   = "task.created"
+	EventTaskUpdated   = "task.updated"
+	EventTaskCompleted = "task.completed"
+	EventTaskDeleted   = "task.deleted"
+	EventTaskRestored  = "task.restored"
+	EventUserCreated   = "user.created"
+	EventUserUpdated   = "user.updated"
+	EventUserDeleted   = "user.deleted"
+	EventUserRestored  = "user.restored"
+)
+
+// OutboxMessage — событие, записанное в той же транзакции, что и изменение,
+// из которого оно родилось. Откат изменения отменяет и событие, а записанное
+// событие ретранслятор рано или поздно опубликует.
+type OutboxMessage struct {
+	ID uint `gorm:"primaryKey" json:"id"`
+	// Key — ключ дедупликации, одинаковый у всех публикаций сообщения.
+	// Получатели отбрасывают по нему повторы.
+	Key   string `gorm:"size:64;not null;uniqueIndex" json:"key"`
+	Event string `gorm:"size:32;not null" json:"event"`
+	// WorkspaceID — пространство изменения, nil у изменений пользователей вне пространства
+	WorkspaceID *uint `gorm:"index" json:"workspace_id"`
+	// ActorID — кто внес изменение, nil у регистрации и служебных задач
+	ActorID *uint `json:"actor_id"`
+	// Data — JSON-объект с ID сущности и ее изменившимися полями до и после
+	Data      string `gorm:"type:text;not null" json:"data"`
+	Attempts  int    `gorm:"not null;default:0" json:"attempts"`
+	LastError string `gorm:"type:text;not null;default:''" json:"last_error"`
+	// NextAttemptAt — когда сообщение можно публиковать. Ретранслятор сдвигает
+	// его вперед, пока публикует, и после неудачи.
+	NextAttemptAt time.Time  `gorm:"not null;index" json:"next_attempt_at"`
+	PublishedAt   *time.Time `gorm:"index" json:"published_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// Envelope — тело события, одинаковое для всех издателей
+type Envelope struct {
+	// EventID и Key одинаковы у всех публикаций события
+	EventID     uint            `json:"event_id"`
+	Key         string          `json:"key"`
+	Event       string          `json:"event"`
+	WorkspaceID *uint           `json:"workspace_id"`
+	ActorID     *uint           `json:"actor_id"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// Body возвращает сообщение в виде JSON-тела события
+func (m OutboxMessage) Body() ([]byte, error) {
+	return json.Marshal(Envelope{
+		EventID:     m.ID,
+		Key:         m.Key,
+		Event:       m.Event,
+		WorkspaceID: m.WorkspaceID,
+		ActorID:     m.ActorID,
+		OccurredAt:  m.CreatedAt,
+		Data:        json.RawMessage(m.Data),
+	})
+}
+
+// Publisher доставляет сообщение дальше: подписчикам в процессе, вебхукам,
+// брокеру. Ошибка означает, что сообщение будет опубликовано еще раз,
+// поэтому повтор уже опубликованного сообщения не должен ничего ломать.
+type Publisher interface {
+	Publish(ctx context.Context, msg OutboxMessage) error
+}
+
+// Fanout публикует сообщение всем издателям. Неудача одного не мешает
+// остальным, но повтор достанется всем: они отбрасывают его по ключу.
+type Fanout []Publisher
+
+func (f Fanout) Publish(ctx context.Context, msg OutboxMessage) error {
+	var errs []error
+	for _, publisher := range f {
+		if err := publisher.Publish(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,111 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// RelayConfig — настройки ретранслятора. Нулевые поля заменяются значениями по умолчанию.
+type RelayConfig struct {
+	// Interval — как часто искать неопубликованные сообщения
+	Interval time.Duration
+	// BaseDelay — пауза перед второй попыткой, дальше она удваивается
+	BaseDelay time.Duration
+	// MaxDelay — предел паузы между попытками. Сообщения не выбрасываются,
+	// сколько бы попыток ни понадобилось.
+	MaxDelay time.Duration
+	// Lease — на сколько сообщение закрепляется за ретранслятором, пока он его публикует
+	Lease time.Duration
+	// BatchSize — сколько сообщений публикуется за один обход
+	BatchSize int
+}
+
+var defaultRelayConfig = RelayConfig{
+	Interval:  time.Second,
+	BaseDelay: 5 * time.Second,
+	MaxDelay:  10 * time.Minute,
+	Lease:     time.Minute,
+	BatchSize: 100,
+}
+
+// Relay публикует сообщения outbox. Сообщение считается опубликованным,
+// только когда Publish вернул nil, а отметка об этом делается после публикации,
+// поэтому доставка гарантируется хотя бы один раз и повторы возможны.
+// Порядок публикации соблюдается, пока не случилось неудачных попыток.
+type Relay struct {
+	repo      OutboxRepository
+	publisher Publisher
+	config    RelayConfig
+}
+
+func NewRelay(repo OutboxRepository, publisher Publisher, config RelayConfig) *Relay {
+	if config.Interval <= 0 {
+		config.Interval = defaultRelayConfig.Interval
+	}
+	if config.BaseDelay <= 0 {
+		config.BaseDelay = defaultRelayConfig.BaseDelay
+	}
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = defaultRelayConfig.MaxDelay
+	}
+	if config.Lease <= 0 {
+		config.Lease = defaultRelayConfig.Lease
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultRelayConfig.BatchSize
+	}
+	return &Relay{repo: repo, publisher: publisher, config: config}
+}
+
+// Run публикует сообщения, пока не отменен ctx
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.RelayDue(ctx); err != nil {
+			log.Printf("Error relaying outbox: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayDue публикует сообщения, которым пора уйти
+func (r *Relay) RelayDue(ctx context.Context) error {
+	messages, err := r.repo.ClaimDue(ctx, time.Now(), r.config.Lease, r.config.BatchSize)
+	if err != nil {
+		return fmt.Errorf("error claiming outbox messages: %w", err)
+	}
+
+	for _, msg := range messages {
+		if err := r.publisher.Publish(ctx, msg); err != nil {
+			msg.Attempts++
+			msg.LastError = err.Error()
+			msg.NextAttemptAt = time.Now().Add(r.backoff(msg.Attempts))
+			log.Printf("Error publishing outbox message %d (attempt %d): %v", msg.ID, msg.Attempts, err)
+			if err := r.repo.SaveAttempt(ctx, msg); err != nil {
+				return fmt.Errorf("error saving outbox message %d: %w", msg.ID, err)
+			}
+			continue
+		}
+		if err := r.repo.MarkPublished(ctx, msg.ID, time.Now()); err != nil {
+			return fmt.Errorf("error marking outbox message %d published: %w", msg.ID, err)
+		}
+	}
+	return nil
+}
+
+// backoff возвращает паузу после attempts неудачных попыток
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.config.BaseDelay
+	for i := 1; i < attempts && delay < r.config.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, r.config.MaxDelay)
+}
@@ -0,0 +1,66 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository interface {
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]OutboxMessage, error)
+	MarkPublished(ctx context.Context, id uint, at time.Time) error
+	SaveAttempt(ctx context.Context, msg OutboxMessage) error
+	PurgePublished(ctx context.Context, before time.Time) (int64, error)
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) *outboxRepository {
+	return &outboxRepository{db: db}
+}
+
+// ClaimDue забирает неопубликованные сообщения, которым пора уйти, и сдвигает
+// их следующую попытку на lease вперед. Пока срок не вышел, другие экземпляры
+// ретранслятора их не берут; если процесс упадет, сообщения вернутся в очередь сами.
+func (r *outboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]OutboxMessage, error) {
+	var messages []OutboxMessage
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL AND next_attempt_at <= ?", now).
+			Order("id").
+			Limit(limit).
+			Find(&messages).Error; err != nil {
+			return err
+		}
+		if len(messages) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(messages))
+		for i, msg := range messages {
+			ids[i] = msg.ID
+		}
+		return tx.Model(&OutboxMessage{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(lease)).Error
+	})
+	return messages, err
+}
+
+func (r *outboxRepository) MarkPublished(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&OutboxMessage{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"published_at": at, "last_error": ""}).Error
+}
+
+// SaveAttempt сохраняет итог неудачной попытки
+func (r *outboxRepository) SaveAttempt(ctx context.Context, msg OutboxMessage) error {
+	return r.db.WithContext(ctx).Model(&msg).Select("attempts", "last_error", "next_attempt_at").Updates(&msg).Error
+}
+
+// PurgePublished удаляет сообщения, опубликованные раньше before
+func (r *outboxRepository) PurgePublished(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("published_at < ?", before).Delete(&OutboxMessage{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,233 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidLimit  = errors.New("limit must be between 1 and 200")
+	ErrInvalidSort   = errors.New("invalid sort field")
+)
+
+// Kind — тип значения колонки, нужен для разбора курсора
+type Kind int
+
+const (
+	KindTime Kind = iota
+	KindString
+	KindBool
+	KindInt
+)
+
+// Column — колонка, по которой разрешено сортировать. Имя попадает в SQL,
+// поэтому колонки берутся только из белых списков в коде.
+type Column struct {
+	Name string
+	Kind Kind
+}
+
+// Order — сортировка выборки. Вторым ключом всегда идет id,
+// чтобы порядок был однозначным.
+type Order struct {
+	Column Column
+	Desc   bool
+}
+
+// ByCreatedAt — сортировка по умолчанию
+var ByCreatedAt = Order{Column: Column{Name: "created_at", Kind: KindTime}}
+
+// ByNewest — сначала созданные последними, например записи журнала
+var ByNewest = Order{Column: ByCreatedAt.Column, Desc: true}
+
+// ByDeletedAt — сортировка корзины: сначала удаленные последними
+var ByDeletedAt = Order{Column: Column{Name: "deleted_at", Kind: KindTime}, Desc: true}
+
+// String возвращает сортировку в виде параметра запроса: "created_at" или "-created_at"
+func (o Order) String() string {
+	if o.Desc {
+		return "-" + o.Column.Name
+	}
+	return o.Column.Name
+}
+
+// ParseOrder разбирает параметр sort по белому списку колонок.
+// Пустая строка означает сортировку по умолчанию.
+func ParseOrder(sort string, allowed map[string]Column) (Order, error) {
+	if sort == "" {
+		return ByCreatedAt, nil
+	}
+
+	name, desc := strings.CutPrefix(sort, "-")
+	column, ok := allowed[name]
+	if !ok {
+		return Order{}, ErrInvalidSort
+	}
+	return Order{Column: column, Desc: desc}, nil
+}
+
+// Cursor — позиция в выборке: значение колонки сортировки и id последней записи
+type Cursor struct {
+	Order string `json:"o"`
+	Value string `json:"v"`
+	ID    uint   `json:"id"`
+}
+
+// Page — параметры запроса страницы. After == nil означает первую страницу.
+type Page struct {
+	Limit int
+	After *Cursor
+	Order Order
+}
+
+// NewPage собирает Page из необязательных параметров запроса.
+// Курсор должен быть выдан для той же сортировки order.
+func NewPage(limit *int, cursor *string, order Order) (Page, error) {
+	page := Page{Limit: DefaultLimit, Order: order}
+
+	if limit != nil {
+		if *limit < 1 || *limit > MaxLimit {
+			return Page{}, ErrInvalidLimit
+		}
+		page.Limit = *limit
+	}
+
+	if cursor != nil && *cursor != "" {
+		after, err := Decode(*cursor)
+		if err != nil {
+			return Page{}, err
+		}
+		page.After = &after
+
+		if _, err := page.cursorValue(); err != nil {
+			return Page{}, err
+		}
+	}
+
+	return page, nil
+}
+
+// Encode превращает курсор в непрозрачную строку для клиента
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// Decode разбирает строку, полученную из Encode
+func Decode(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// CursorFor строит курсор на запись со значением value колонки сортировки
+func (p Page) CursorFor(value any, id uint) Cursor {
+	c := Cursor{Order: p.Order.String(), ID: id}
+	switch v := value.(type) {
+	case time.Time:
+		c.Value = v.UTC().Format(time.RFC3339Nano)
+	case bool:
+		c.Value = strconv.FormatBool(v)
+	default:
+		c.Value = fmt.Sprint(v)
+	}
+	return c
+}
+
+// cursorValue разбирает значение курсора согласно типу колонки
+func (p Page) cursorValue() (any, error) {
+	if p.After.Order != p.Order.String() {
+		// Курсор выдан для другой сортировки
+		return nil, ErrInvalidCursor
+	}
+
+	switch p.Order.Column.Kind {
+	case KindTime:
+		t, err := time.Parse(time.RFC3339Nano, p.After.Value)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		return t, nil
+	case KindBool:
+		b, err := strconv.ParseBool(p.After.Value)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		return b, nil
+	case KindInt:
+		n, err := strconv.ParseInt(p.After.Value, 10, 64)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		return n, nil
+	default:
+		return p.After.Value, nil
+	}
+}
+
+// Apply добавляет к запросу keyset-условие, сортировку и лимит.
+// Выбирается на одну запись больше, чтобы понять, есть ли следующая страница.
+func Apply(db *gorm.DB, page Page) *gorm.DB {
+	column := page.Order.Column.Name
+	direction, op := "ASC", ">"
+	if page.Order.Desc {
+		direction, op = "DESC", "<"
+	}
+
+	if page.After != nil {
+		value, err := page.cursorValue()
+		if err != nil {
+			// Ошибку добавляем в отдельную сессию, чтобы не испортить общий *gorm.DB
+			tx := db.Session(&gorm.Session{})
+			_ = tx.AddError(err)
+			return tx
+		}
+		db = db.Where(fmt.Sprintf("(%s, id) %s (?, ?)", column, op), value, page.After.ID)
+	}
+
+	return db.Order(fmt.Sprintf("%s %s, id %s", column, direction, direction)).Limit(page.Limit + 1)
+}
+
+// Trim отрезает лишнюю запись, выбранную Apply, и возвращает курсор
+// следующей страницы или пустую строку, если страница последняя
+func Trim[T any](items []T, page Page, cursor func(T) Cursor) ([]T, string) {
+	if len(items) <= page.Limit {
+		return items, ""
+	}
+	items = items[:page.Limit]
+	return items, cursor(items[len(items)-1]).Encode()
+}
+
+// Envelope — тело ответа со страницей записей
+type Envelope[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewEnvelope оборачивает страницу; пустой список сериализуется как []
+func NewEnvelope[T any](items []T, next string) Envelope[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return Envelope[T]{Items: items, NextCursor: next}
+}
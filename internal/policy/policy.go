@@ -0,0 +1,157 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"newproject/internal/identity"
+	"newproject/internal/models"
+	"newproject/internal/userService"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrForbidden — у вызывающего нет прав на действие
+	ErrForbidden = errors.New("forbidden")
+	// ErrLastAdmin — действие оставило бы систему без администраторов
+	ErrLastAdmin = errors.New("cannot remove the last admin")
+)
+
+// Policy решает, какие действия доступны вызывающему. Действия внутри рабочего
+// пространства разрешаются по роли в нем, которую tenant.Middleware читает из
+// базы на каждый запрос. Роль в системе нужна только для действий над
+// учетными записями и тоже читается из репозитория, а не из токена. Поэтому
+// понижение прав действует сразу.
+type Policy struct {
+	users userService.UserRepository
+}
+
+func NewPolicy(users userService.UserRepository) *Policy {
+	return &Policy{users: users}
+}
+
+// CanListUsers — список участников пространства доступен его владельцам и администраторам
+func (p *Policy) CanListUsers(ctx context.Context) error {
+	return p.requireWorkspaceAdmin(ctx)
+}
+
+// CanListAllTasks — задачи всех участников пространства доступны его
+// владельцам и администраторам
+func (p *Policy) CanListAllTasks(ctx context.Context) error {
+	return p.requireWorkspaceAdmin(ctx)
+}
+
+// CanUpdateUser — учетную запись может менять ее владелец или администратор системы
+func (p *Policy) CanUpdateUser(ctx context.Context, targetID uint) error {
+	caller, err := p.caller(ctx)
+	if err != nil {
+		return err
+	}
+
+	if caller.ID == targetID || caller.Role == models.RoleAdmin {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// CanChangeRole — роли в системе меняют только администраторы системы, причем
+// последний администратор не может лишиться своей роли
+func (p *Policy) CanChangeRole(ctx context.Context, targetID uint, role string) error {
+	if err := p.requirePlatformAdmin(ctx); err != nil {
+		return err
+	}
+
+	if role == models.RoleAdmin {
+		return nil
+	}
+	return p.ensureNotLastAdmin(ctx, targetID)
+}
+
+// CanDeleteAccount — учетную запись может удалить ее владелец или администратор
+// системы. Последнего администратора системы удалить нельзя. Исключение из
+// рабочего пространства решает workspaceService.
+func (p *Policy) CanDeleteAccount(ctx context.Context, targetID uint) error {
+	if err := p.CanUpdateUser(ctx, targetID); err != nil {
+		return err
+	}
+	return p.ensureNotLastAdmin(ctx, targetID)
+}
+
+// CanRestoreUser — учетные записи из корзины возвращают только администраторы системы
+func (p *Policy) CanRestoreUser(ctx context.Context) error {
+	return p.requirePlatformAdmin(ctx)
+}
+
+// CanViewAudit — журнал изменений пространства доступен его владельцам и администраторам
+func (p *Policy) CanViewAudit(ctx context.Context) error {
+	return p.requireWorkspaceAdmin(ctx)
+}
+
+// CanManageWebhooks — вебхуки видят все изменения пространства, поэтому
+// управляют ими только его владельцы и администраторы
+func (p *Policy) CanManageWebhooks(ctx context.Context) error {
+	return p.requireWorkspaceAdmin(ctx)
+}
+
+// requireWorkspaceAdmin пропускает владельцев и администраторов пространства запроса
+func (p *Policy) requireWorkspaceAdmin(ctx context.Context) error {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !caller.IsAdmin() {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// requirePlatformAdmin пропускает администраторов системы
+func (p *Policy) requirePlatformAdmin(ctx context.Context) error {
+	caller, err := p.caller(ctx)
+	if err != nil {
+		return err
+	}
+
+	if caller.Role != models.RoleAdmin {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// caller загружает учетную запись вызывающего из репозитория
+func (p *Policy) caller(ctx context.Context) (userService.User, error) {
+	c, err := identity.Require(ctx)
+	if err != nil {
+		return userService.User{}, err
+	}
+
+	var user userService.User
+	err = p.users.GetUserByIDAnyWorkspace(ctx, c.UserID, &user)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return userService.User{}, identity.ErrUnauthenticated
+	} else if err != nil {
+		return userService.User{}, fmt.Errorf("error fetching caller: %w", err)
+	}
+	return user, nil
+}
+
+func (p *Policy) ensureNotLastAdmin(ctx context.Context, targetID uint) error {
+	var target userService.User
+	if err := p.users.GetUserByIDAnyWorkspace(ctx, targetID, &target); err != nil {
+		return err
+	}
+	if target.Role != models.RoleAdmin {
+		return nil
+	}
+
+	admins, err := p.users.CountUsersByRole(ctx, models.RoleAdmin)
+	if err != nil {
+		return fmt.Errorf("error counting admins: %w", err)
+	}
+	if admins <= 1 {
+		return ErrLastAdmin
+	}
+	return nil
+}
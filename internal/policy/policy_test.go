@@ -0,0 +1,288 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"newproject/internal/identity"
+	"newproject/internal/models"
+	"newproject/internal/pagination"
+	"newproject/internal/taskService"
+	"newproject/internal/userService"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// memUserRepository — userService.UserRepository в памяти
+type memUserRepository struct {
+	users map[uint]userService.User
+}
+
+func newMemUserRepository(users ...userService.User) *memUserRepository {
+	r := &memUserRepository{users: make(map[uint]userService.User)}
+	for _, u := range users {
+		r.users[u.ID] = u
+	}
+	return r
+}
+
+func (r *memUserRepository) CreateUser(ctx context.Context, user userService.User) (userService.User, error) {
+	user.ID = uint(len(r.users) + 1)
+	r.users[user.ID] = user
+	return user, nil
+}
+
+func (r *memUserRepository) GetAllUsers(ctx context.Context, page pagination.Page) ([]userService.User, string, error) {
+	var users []userService.User
+	for _, u := range r.users {
+		users = append(users, u)
+	}
+	return users, "", nil
+}
+
+func (r *memUserRepository) UpdateUserByID(ctx context.Context, id uint, user userService.User) (userService.User, error) {
+	existing, ok := r.users[id]
+	if !ok {
+		return userService.User{}, gorm.ErrRecordNotFound
+	}
+	if user.Role != "" {
+		existing.Role = user.Role
+	}
+	r.users[id] = existing
+	return existing, nil
+}
+
+func (r *memUserRepository) DeleteUserByID(ctx context.Context, id uint) error {
+	delete(r.users, id)
+	return nil
+}
+
+func (r *memUserRepository) GetUserByID(ctx context.Context, id uint, user *userService.User) error {
+	u, ok := r.users[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	*user = u
+	return nil
+}
+
+func (r *memUserRepository) GetUserByIDAnyWorkspace(ctx context.Context, id uint, user *userService.User) error {
+	return r.GetUserByID(ctx, id, user)
+}
+
+func (r *memUserRepository) UserExists(ctx context.Context, id uint) (bool, error) {
+	_, ok := r.users[id]
+	return ok, nil
+}
+
+func (r *memUserRepository) UserExistsAnyWorkspace(ctx context.Context, id uint) (bool, error) {
+	return r.UserExists(ctx, id)
+}
+
+func (r *memUserRepository) GetTasksForUser(ctx context.Context, userID uint) ([]taskService.Task, error) {
+	return nil, nil
+}
+
+func (r *memUserRepository) GetUserByEmailAnyWorkspace(ctx context.Context, email string) (*userService.User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return &u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memUserRepository) UpdatePassword(ctx context.Context, id uint, passwordHash string) error {
+	return nil
+}
+
+func (r *memUserRepository) CountUsersByRole(ctx context.Context, role string) (int64, error) {
+	var count int64
+	for _, u := range r.users {
+		if u.Role == role {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memUserRepository) GetDeletedUsers(ctx context.Context, page pagination.Page) ([]userService.User, string, error) {
+	return nil, "", nil
+}
+
+func (r *memUserRepository) GetDeletedUserByID(ctx context.Context, id uint, user *userService.User) error {
+	return gorm.ErrRecordNotFound
+}
+
+func (r *memUserRepository) RestoreUser(ctx context.Context, id uint) error {
+	return nil
+}
+
+func (r *memUserRepository) PurgeUsers(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func callerCtx(userID uint) context.Context {
+	return identity.WithCaller(context.Background(), identity.Caller{UserID: userID})
+}
+
+// memberCtx — вызывающий с ролью role в рабочем пространстве запроса
+func memberCtx(userID uint, role string) context.Context {
+	return identity.WithCaller(context.Background(), identity.Caller{UserID: userID, WorkspaceRole: role})
+}
+
+func TestWorkspaceActionsFollowWorkspaceRole(t *testing.T) {
+	p := NewPolicy(newMemUserRepository(
+		userService.User{ID: 1, Role: models.RoleAdmin},
+		userService.User{ID: 2, Role: models.RoleUser},
+	))
+	checks := map[string]func(context.Context) error{
+		"CanListUsers":      p.CanListUsers,
+		"CanListAllTasks":   p.CanListAllTasks,
+		"CanViewAudit":      p.CanViewAudit,
+		"CanManageWebhooks": p.CanManageWebhooks,
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want error
+	}{
+		{"workspace owner", memberCtx(2, models.WorkspaceRoleOwner), nil},
+		{"workspace admin", memberCtx(2, models.WorkspaceRoleAdmin), nil},
+		{"workspace member", memberCtx(2, models.WorkspaceRoleMember), ErrForbidden},
+		{"platform admin as a member", memberCtx(1, models.WorkspaceRoleMember), ErrForbidden},
+		{"no workspace role", callerCtx(1), ErrForbidden},
+		{"no caller", context.Background(), identity.ErrUnauthenticated},
+	}
+	for _, tt := range tests {
+		for name, check := range checks {
+			if err := check(tt.ctx); !errors.Is(err, tt.want) {
+				t.Errorf("%s %s: got %v, want %v", tt.name, name, err, tt.want)
+			}
+		}
+	}
+}
+
+func TestPlatformActionsFollowPlatformRole(t *testing.T) {
+	p := NewPolicy(newMemUserRepository(
+		userService.User{ID: 1, Role: models.RoleAdmin},
+		userService.User{ID: 2, Role: models.RoleUser},
+	))
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want error
+	}{
+		{"platform admin", memberCtx(1, models.WorkspaceRoleMember), nil},
+		{"workspace owner", memberCtx(2, models.WorkspaceRoleOwner), ErrForbidden},
+		{"user", memberCtx(2, models.WorkspaceRoleMember), ErrForbidden},
+	}
+	for _, tt := range tests {
+		if err := p.CanRestoreUser(tt.ctx); !errors.Is(err, tt.want) {
+			t.Errorf("%s CanRestoreUser: got %v, want %v", tt.name, err, tt.want)
+		}
+		if err := p.CanChangeRole(tt.ctx, 2, models.RoleAdmin); !errors.Is(err, tt.want) {
+			t.Errorf("%s CanChangeRole: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestRoleIsReadFromRepository(t *testing.T) {
+	repo := newMemUserRepository(userService.User{ID: 1, Role: models.RoleUser})
+	p := NewPolicy(repo)
+
+	// Роль в контексте не должна давать прав, если в базе ее уже нет
+	ctx := identity.WithCaller(context.Background(), identity.Caller{UserID: 1, Role: models.RoleAdmin})
+	if err := p.CanRestoreUser(ctx); !errors.Is(err, ErrForbidden) {
+		t.Errorf("stale admin role: got %v, want ErrForbidden", err)
+	}
+}
+
+func TestUnauthenticated(t *testing.T) {
+	p := NewPolicy(newMemUserRepository(userService.User{ID: 1, Role: models.RoleAdmin}))
+
+	if err := p.CanRestoreUser(context.Background()); !errors.Is(err, identity.ErrUnauthenticated) {
+		t.Errorf("no caller: got %v, want ErrUnauthenticated", err)
+	}
+	if err := p.CanRestoreUser(callerCtx(42)); !errors.Is(err, identity.ErrUnauthenticated) {
+		t.Errorf("deleted caller: got %v, want ErrUnauthenticated", err)
+	}
+}
+
+func TestCanDeleteAccount(t *testing.T) {
+	p := NewPolicy(newMemUserRepository(
+		userService.User{ID: 1, Role: models.RoleAdmin},
+		userService.User{ID: 2, Role: models.RoleUser},
+		userService.User{ID: 3, Role: models.RoleUser},
+	))
+
+	tests := []struct {
+		name     string
+		callerID uint
+		targetID uint
+		want     error
+	}{
+		{"user deletes self", 2, 2, nil},
+		{"user deletes other user", 2, 3, ErrForbidden},
+		{"user deletes admin", 2, 1, ErrForbidden},
+		{"admin deletes user", 1, 3, nil},
+		{"admin deletes missing user", 1, 99, gorm.ErrRecordNotFound},
+		{"last admin deletes self", 1, 1, ErrLastAdmin},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := p.CanDeleteAccount(callerCtx(tt.callerID), tt.targetID)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanDeleteAdminWhenAnotherAdminExists(t *testing.T) {
+	p := NewPolicy(newMemUserRepository(
+		userService.User{ID: 1, Role: models.RoleAdmin},
+		userService.User{ID: 2, Role: models.RoleAdmin},
+	))
+
+	if err := p.CanDeleteAccount(callerCtx(1), 2); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+}
+
+func TestCanChangeRole(t *testing.T) {
+	p := NewPolicy(newMemUserRepository(
+		userService.User{ID: 1, Role: models.RoleAdmin},
+		userService.User{ID: 2, Role: models.RoleUser},
+	))
+
+	if err := p.CanChangeRole(callerCtx(2), 2, models.RoleAdmin); !errors.Is(err, ErrForbidden) {
+		t.Errorf("user promotes self: got %v, want ErrForbidden", err)
+	}
+	if err := p.CanChangeRole(callerCtx(1), 2, models.RoleAdmin); err != nil {
+		t.Errorf("admin promotes user: got %v, want nil", err)
+	}
+	if err := p.CanChangeRole(callerCtx(1), 1, models.RoleUser); !errors.Is(err, ErrLastAdmin) {
+		t.Errorf("last admin demotes self: got %v, want ErrLastAdmin", err)
+	}
+}
+
+func TestCanUpdateUser(t *testing.T) {
+	p := NewPolicy(newMemUserRepository(
+		userService.User{ID: 1, Role: models.RoleAdmin},
+		userService.User{ID: 2, Role: models.RoleUser},
+	))
+
+	if err := p.CanUpdateUser(callerCtx(2), 2); err != nil {
+		t.Errorf("user updates self: got %v, want nil", err)
+	}
+	if err := p.CanUpdateUser(callerCtx(2), 1); !errors.Is(err, ErrForbidden) {
+		t.Errorf("user updates admin: got %v, want ErrForbidden", err)
+	}
+	if err := p.CanUpdateUser(callerCtx(1), 2); err != nil {
+		t.Errorf("admin updates user: got %v, want nil", err)
+	}
+}
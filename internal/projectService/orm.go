@@ -0,0 +1,38 @@
+package projectService
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Project struct {
+	gorm.Model
+	// WorkspaceID — рабочее пространство проекта, заполняется из контекста запроса
+	WorkspaceID uint   `gorm:"not null;index;tenant" json:"workspace_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	UserID      uint   `gorm:"index" json:"user_id"` // владелец проекта
+	// ArchivedAt — когда проект отправлен в архив, nil у активных проектов
+	ArchivedAt *time.Time `json:"archived_at"`
+}
+
+// TaskCounts — число открытых и выполненных задач проекта
+type TaskCounts struct {
+	ProjectID uint  `json:"-"`
+	Open      int64 `gorm:"column:open_tasks" json:"open_tasks"`
+	Done      int64 `gorm:"column:done_tasks" json:"done_tasks"`
+}
+
+// ProjectSummary — проект вместе со счетчиками задач
+type ProjectSummary struct {
+	Project
+	TaskCounts
+}
+
+// ProjectUpdate — изменяемые поля проекта, nil оставляет значение как есть
+type ProjectUpdate struct {
+	Name        *string
+	Description *string
+	Archived    *bool
+}
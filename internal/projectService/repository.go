@@ -0,0 +1,161 @@
+package projectService
+
+import (
+	"context"
+	"newproject/internal/pagination"
+	"newproject/internal/taskService"
+
+	"gorm.io/gorm"
+)
+
+type ProjectRepository interface {
+	CreateProject(ctx context.Context, project Project) (Project, error)
+	GetProjects(ctx context.Context, filter ProjectFilter, page pagination.Page) ([]Project, string, error)
+	GetProjectByID(ctx context.Context, id uint) (Project, error)
+	UpdateProject(ctx context.Context, project Project) (Project, error)
+	DeleteProjectByID(ctx context.Context, id uint) error
+	ProjectOwner(ctx context.Context, id uint) (uint, bool, error)
+	CountTasks(ctx context.Context, projectIDs []uint) (map[uint]TaskCounts, error)
+	SetTaskOrder(ctx context.Context, projectID uint, taskIDs []uint) error
+}
+
+// ProjectFilter — условия выборки проектов
+type ProjectFilter struct {
+	UserID *uint
+	// IncludeArchived — вместе с активными вернуть и архивные проекты
+	IncludeArchived bool
+}
+
+type projectRepository struct {
+	db *gorm.DB
+}
+
+func NewProjectRepository(db *gorm.DB) *projectRepository {
+	return &projectRepository{db: db}
+}
+
+func (r *projectRepository) CreateProject(ctx context.Context, project Project) (Project, error) {
+	err := r.db.WithContext(ctx).Create(&project).Error
+	return project, err
+}
+
+func (r *projectRepository) GetProjects(ctx context.Context, filter ProjectFilter, page pagination.Page) ([]Project, string, error) {
+	db := r.db.WithContext(ctx)
+	if filter.UserID != nil {
+		db = db.Where("user_id = ?", *filter.UserID)
+	}
+	if !filter.IncludeArchived {
+		db = db.Where("archived_at IS NULL")
+	}
+
+	var projects []Project
+	if err := pagination.Apply(db, page).Find(&projects).Error; err != nil {
+		return nil, "", err
+	}
+	projects, next := pagination.Trim(projects, page, func(p Project) pagination.Cursor {
+		return page.CursorFor(p.CreatedAt, p.ID)
+	})
+	return projects, next, nil
+}
+
+func (r *projectRepository) GetProjectByID(ctx context.Context, id uint) (Project, error) {
+	var project Project
+	err := r.db.WithContext(ctx).First(&project, id).Error
+	return project, err
+}
+
+func (r *projectRepository) UpdateProject(ctx context.Context, project Project) (Project, error) {
+	err := r.db.WithContext(ctx).Save(&project).Error
+	return project, err
+}
+
+// DeleteProjectByID удаляет проект, а его задачи оставляет без проекта
+func (r *projectRepository) DeleteProjectByID(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&taskService.Task{}).Where("project_id = ?", id).
+			Updates(map[string]any{"project_id": nil, "position": 0}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&Project{}, id).Error
+	})
+}
+
+// ProjectOwner возвращает владельца проекта и признак архивации,
+// реализует taskService.ProjectLookup
+func (r *projectRepository) ProjectOwner(ctx context.Context, id uint) (uint, bool, error) {
+	project, err := r.GetProjectByID(ctx, id)
+	if err != nil {
+		return 0, false, err
+	}
+	return project.UserID, project.ArchivedAt != nil, nil
+}
+
+// CountTasks считает открытые и выполненные задачи проектов одним запросом
+func (r *projectRepository) CountTasks(ctx context.Context, projectIDs []uint) (map[uint]TaskCounts, error) {
+	counts := make(map[uint]TaskCounts, len(projectIDs))
+	if len(projectIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []TaskCounts
+	err := r.db.WithContext(ctx).Model(&taskService.Task{}).
+		Select("project_id, "+
+			"SUM(CASE WHEN is_done THEN 0 ELSE 1 END) AS open_tasks, "+
+			"SUM(CASE WHEN is_done THEN 1 ELSE 0 END) AS done_tasks").
+		Where("project_id IN ?", projectIDs).
+		Group("project_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.ProjectID] = row
+	}
+	return counts, nil
+}
+
+// SetTaskOrder ставит перечисленные задачи в начало проекта в заданном порядке,
+// остальные задачи проекта идут следом, сохраняя прежний порядок
+func (r *projectRepository) SetTaskOrder(ctx context.Context, projectID uint, taskIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := taskService.LockProject(tx, projectID); err != nil {
+			return err
+		}
+		var current []uint
+		err := tx.Model(&taskService.Task{}).Where("project_id = ?", projectID).
+			Order("position, id").Pluck("id", &current).Error
+		if err != nil {
+			return err
+		}
+
+		inProject := make(map[uint]bool, len(current))
+		for _, id := range current {
+			inProject[id] = true
+		}
+		placed := make(map[uint]bool, len(taskIDs))
+		for _, id := range taskIDs {
+			if !inProject[id] || placed[id] {
+				return ErrInvalidOrder
+			}
+			placed[id] = true
+		}
+
+		order := append([]uint{}, taskIDs...)
+		for _, id := range current {
+			if !placed[id] {
+				order = append(order, id)
+			}
+		}
+
+		// Порядок не меняет содержимое задач, поэтому updated_at не трогаем
+		for i, id := range order {
+			err := tx.Model(&taskService.Task{}).Where("id = ?", id).UpdateColumn("position", i+1).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
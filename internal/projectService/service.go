@@ -0,0 +1,195 @@
+package projectService
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"newproject/internal/identity"
+	"newproject/internal/pagination"
+	"newproject/internal/taskService"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrProjectNotFound возвращается и для чужих проектов, чтобы не раскрывать их существование
+	ErrProjectNotFound = fmt.Errorf("project not found: %w", gorm.ErrRecordNotFound)
+	// ErrInvalidName — пустое название проекта
+	ErrInvalidName = errors.New("project name is required")
+	// ErrInvalidOrder — в порядке есть чужие для проекта или повторяющиеся задачи
+	ErrInvalidOrder = errors.New("order must list distinct tasks of the project")
+)
+
+// ByPosition — ручной порядок задач внутри проекта
+var ByPosition = pagination.Order{Column: pagination.Column{Name: "position", Kind: pagination.KindInt}}
+
+type ProjectService struct {
+	repo        ProjectRepository
+	taskService *taskService.TaskService
+}
+
+func NewProjectService(repo ProjectRepository, taskService *taskService.TaskService) *ProjectService {
+	return &ProjectService{repo: repo, taskService: taskService}
+}
+
+// CreateProject создает проект вызывающего
+func (s *ProjectService) CreateProject(ctx context.Context, project Project) (ProjectSummary, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return ProjectSummary{}, err
+	}
+
+	project.Name = strings.TrimSpace(project.Name)
+	if project.Name == "" {
+		return ProjectSummary{}, ErrInvalidName
+	}
+	project.UserID = caller.UserID
+	project.ArchivedAt = nil
+
+	created, err := s.repo.CreateProject(ctx, project)
+	if err != nil {
+		return ProjectSummary{}, err
+	}
+	return ProjectSummary{Project: created}, nil
+}
+
+// GetProjects возвращает страницу проектов вызывающего, а администратору — всех проектов
+func (s *ProjectService) GetProjects(ctx context.Context, includeArchived bool, page pagination.Page) ([]ProjectSummary, string, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	filter := ProjectFilter{IncludeArchived: includeArchived}
+	if !caller.IsAdmin() {
+		filter.UserID = &caller.UserID
+	}
+
+	projects, next, err := s.repo.GetProjects(ctx, filter, page)
+	if err != nil {
+		return nil, "", err
+	}
+	summaries, err := s.withCounts(ctx, projects)
+	if err != nil {
+		return nil, "", err
+	}
+	return summaries, next, nil
+}
+
+// GetProjectByID возвращает проект, если он виден вызывающему
+func (s *ProjectService) GetProjectByID(ctx context.Context, id uint) (ProjectSummary, error) {
+	project, err := s.getProject(ctx, id)
+	if err != nil {
+		return ProjectSummary{}, err
+	}
+
+	summaries, err := s.withCounts(ctx, []Project{project})
+	if err != nil {
+		return ProjectSummary{}, err
+	}
+	return summaries[0], nil
+}
+
+// UpdateProject меняет название, описание или архивный статус проекта
+func (s *ProjectService) UpdateProject(ctx context.Context, id uint, update ProjectUpdate) (ProjectSummary, error) {
+	project, err := s.getProject(ctx, id)
+	if err != nil {
+		return ProjectSummary{}, err
+	}
+
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			return ProjectSummary{}, ErrInvalidName
+		}
+		project.Name = name
+	}
+	if update.Description != nil {
+		project.Description = *update.Description
+	}
+	if update.Archived != nil {
+		switch {
+		case *update.Archived && project.ArchivedAt == nil:
+			now := time.Now()
+			project.ArchivedAt = &now
+		case !*update.Archived:
+			project.ArchivedAt = nil
+		}
+	}
+
+	if _, err := s.repo.UpdateProject(ctx, project); err != nil {
+		return ProjectSummary{}, err
+	}
+	return s.GetProjectByID(ctx, id)
+}
+
+// DeleteProjectByID удаляет проект. Задачи проекта остаются, но уже вне проекта.
+func (s *ProjectService) DeleteProjectByID(ctx context.Context, id uint) error {
+	if _, err := s.getProject(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.DeleteProjectByID(ctx, id)
+}
+
+// GetProjectTasks возвращает страницу задач проекта в ручном порядке
+func (s *ProjectService) GetProjectTasks(ctx context.Context, id uint, page pagination.Page) ([]taskService.Task, string, error) {
+	if _, err := s.getProject(ctx, id); err != nil {
+		return nil, "", err
+	}
+
+	filter := taskService.TaskFilter{ProjectID: &id}
+	caller, _ := identity.FromContext(ctx)
+	if caller.IsAdmin() {
+		return s.taskService.GetAllTasks(ctx, filter, page)
+	}
+	return s.taskService.GetTasks(ctx, filter, page)
+}
+
+// ReorderTasks задает ручной порядок задач проекта
+func (s *ProjectService) ReorderTasks(ctx context.Context, id uint, taskIDs []uint) error {
+	if _, err := s.getProject(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.SetTaskOrder(ctx, id, taskIDs)
+}
+
+// getProject загружает проект и проверяет доступ вызывающего
+func (s *ProjectService) getProject(ctx context.Context, id uint) (Project, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return Project{}, err
+	}
+
+	project, err := s.repo.GetProjectByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Project{}, ErrProjectNotFound
+	} else if err != nil {
+		return Project{}, err
+	}
+
+	if project.UserID != caller.UserID && !caller.IsAdmin() {
+		return Project{}, ErrProjectNotFound
+	}
+	return project, nil
+}
+
+// withCounts добавляет к проектам счетчики открытых и выполненных задач
+func (s *ProjectService) withCounts(ctx context.Context, projects []Project) ([]ProjectSummary, error) {
+	ids := make([]uint, 0, len(projects))
+	for _, p := range projects {
+		ids = append(ids, p.ID)
+	}
+
+	counts, err := s.repo.CountTasks(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]ProjectSummary, 0, len(projects))
+	for _, p := range projects {
+		summaries = append(summaries, ProjectSummary{Project: p, TaskCounts: counts[p.ID]})
+	}
+	return summaries, nil
+}
@@ -0,0 +1,308 @@
+package streamService
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"newproject/internal/identity"
+	"newproject/internal/outbox"
+	"newproject/internal/tenant"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultBufferSize — сколько последних событий хранится для возобновления ленты
+	defaultBufferSize = 1024
+	// subscriberBuffer — сколько событий ждут подписчика, прежде чем его отключат
+	subscriberBuffer = 64
+)
+
+var (
+	// ErrUserNotFound — чужая лента доступна только администраторам, для остальных ее нет
+	ErrUserNotFound = fmt.Errorf("user not found: %w", gorm.ErrRecordNotFound)
+	// ErrInvalidLastEventID — Last-Event-ID не похож на ID события
+	ErrInvalidLastEventID = errors.New("Last-Event-ID must be an event id")
+)
+
+// TaskLookup — то, что Hub нужно знать о задачах, чтобы решить, кому отправить событие
+type TaskLookup interface {
+	// TaskAudience возвращает владельца и участников задачи из пространства
+	// в контексте, включая задачи в корзине, или gorm.ErrRecordNotFound
+	TaskAudience(ctx context.Context, id uint) (ownerID uint, members []uint, err error)
+}
+
+// Event — изменение задачи в ленте
+type Event struct {
+	// ID — ID сообщения outbox, из которого родилось событие
+	ID          uint
+	Type        string
+	WorkspaceID uint
+	TaskID      uint
+	// OwnerID — владелец задачи на момент события, 0, если задачу уже очистили
+	// из корзины. PreviousOwnerID — прежний владелец, если задачу передали.
+	OwnerID         uint
+	PreviousOwnerID uint
+	ActorID         *uint
+	OccurredAt      time.Time
+	// Before и After — изменившиеся поля задачи, как в журнале аудита
+	Before json.RawMessage
+	After  json.RawMessage
+	// audience — кому видна задача на момент события
+	audience []uint
+}
+
+// Concerns сообщает, видна ли задача события пользователю: он ее владелец,
+// участник или прежний владелец
+func (e Event) Concerns(userID uint) bool {
+	for _, id := range e.audience {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// Hub раздает изменения задач открытым лентам и хранит последние события,
+// чтобы переподключившийся клиент получил пропущенное. Буфер живет в памяти
+// процесса: после перезапуска клиент получает reset и перечитывает задачи.
+type Hub struct {
+	tasks TaskLookup
+
+	mu sync.Mutex
+	// buffer — кольцо последних событий, start — индекс самого старого
+	buffer      []Event
+	start, size int
+	subscribers map[*Subscription]struct{}
+	listeners   map[int]func(Event)
+	nextID      int
+}
+
+// NewHub создает ленту с буфером на bufferSize событий
+// (defaultBufferSize, если bufferSize не больше нуля)
+func NewHub(tasks TaskLookup, bufferSize int) *Hub {
+	if bufferSize <= 0 {
+		bufferSize = defaultBufferSize
+	}
+	return &Hub{
+		tasks:       tasks,
+		buffer:      make([]Event, bufferSize),
+		subscribers: make(map[*Subscription]struct{}),
+		listeners:   make(map[int]func(Event)),
+	}
+}
+
+// Listen передает listener каждое новое событие всех пространств. Он
+// вызывается под блокировкой ленты и не должен ждать или обращаться к ней.
+// Возвращенная функция отменяет подписку.
+func (h *Hub) Listen(listener func(Event)) func() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	id := h.nextID
+	h.nextID++
+	h.listeners[id] = listener
+	return func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.listeners, id)
+	}
+}
+
+// Subscription — открытая лента одного пользователя
+type Subscription struct {
+	// Reset — события после Last-Event-ID уже вытеснены из буфера, клиент
+	// должен перечитать задачи. LatestID — ID последнего события в буфере.
+	Reset    bool
+	LatestID uint
+	// Backlog — события после Last-Event-ID, которые надо отправить первыми
+	Backlog []Event
+	// C — новые события. Канал закрывается, когда подписку отменили или
+	// подписчик не успевает забирать события: тогда клиент переподключается
+	// с Last-Event-ID и добирает пропущенное из буфера.
+	C <-chan Event
+
+	hub         *Hub
+	ch          chan Event
+	workspaceID uint
+	userID      uint
+}
+
+// Subscribe открывает ленту изменений задач пользователя userID (по умолчанию
+// вызывающего) в пространстве из контекста. Чужую ленту может открыть только
+// администратор. lastEventID — ID последнего полученного события или пустая строка.
+func (h *Hub) Subscribe(ctx context.Context, userID *uint, lastEventID string) (*Subscription, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	workspaceID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	target := caller.UserID
+	if userID != nil {
+		target = *userID
+	}
+	if target != caller.UserID && !caller.IsAdmin() {
+		return nil, ErrUserNotFound
+	}
+
+	var lastID uint64
+	if lastEventID != "" {
+		if lastID, err = strconv.ParseUint(lastEventID, 10, 64); err != nil || lastID == 0 {
+			return nil, ErrInvalidLastEventID
+		}
+	}
+
+	ch := make(chan Event, subscriberBuffer)
+	sub := &Subscription{C: ch, hub: h, ch: ch, workspaceID: workspaceID, userID: target}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.size > 0 {
+		sub.LatestID = h.at(h.size - 1).ID
+	}
+	if lastID != 0 {
+		pos := -1
+		for i := 0; i < h.size; i++ {
+			if h.at(i).ID == uint(lastID) {
+				pos = i
+				break
+			}
+		}
+		if pos < 0 {
+			sub.Reset = true
+		} else {
+			for i := pos + 1; i < h.size; i++ {
+				if event := h.at(i); sub.wants(event) {
+					sub.Backlog = append(sub.Backlog, event)
+				}
+			}
+		}
+	}
+	h.subscribers[sub] = struct{}{}
+	return sub, nil
+}
+
+// Close отменяет подписку
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.drop(s)
+}
+
+// wants сообщает, касается ли событие подписчика
+func (s *Subscription) wants(event Event) bool {
+	return event.WorkspaceID == s.workspaceID && event.Concerns(s.userID)
+}
+
+// Publish принимает сообщение шины outbox, реализует outbox.Handler.
+// Сообщения не о задачах и изменения вне пространств пропускаются.
+func (h *Hub) Publish(ctx context.Context, msg outbox.OutboxMessage) error {
+	if !strings.HasPrefix(msg.Event, "task.") || msg.WorkspaceID == nil {
+		return nil
+	}
+	var data struct {
+		ID     uint            `json:"id"`
+		Before json.RawMessage `json:"before"`
+		After  json.RawMessage `json:"after"`
+	}
+	if err := json.Unmarshal([]byte(msg.Data), &data); err != nil {
+		return fmt.Errorf("stream: %w", err)
+	}
+
+	owner, members, err := h.tasks.TaskAudience(tenant.WithWorkspace(ctx, *msg.WorkspaceID), data.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("stream: %w", err)
+	}
+	audience := members
+	if owner != 0 {
+		audience = append(audience, owner)
+	}
+	// Прежний владелец тоже узнает, что задачу передали другому
+	previous := previousOwner(data.Before)
+	if previous != 0 {
+		audience = append(audience, previous)
+	}
+
+	h.add(Event{
+		ID:              msg.ID,
+		Type:            msg.Event,
+		WorkspaceID:     *msg.WorkspaceID,
+		TaskID:          data.ID,
+		OwnerID:         owner,
+		PreviousOwnerID: previous,
+		ActorID:         msg.ActorID,
+		OccurredAt:      msg.CreatedAt,
+		Before:          data.Before,
+		After:           data.After,
+		audience:        audience,
+	})
+	return nil
+}
+
+// add кладет событие в буфер и отправляет подписчикам. Подписчик с
+// переполненным каналом отключается, чтобы медленный клиент не держал остальных.
+func (h *Hub) add(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.size < len(h.buffer) {
+		h.buffer[(h.start+h.size)%len(h.buffer)] = event
+		h.size++
+	} else {
+		h.buffer[h.start] = event
+		h.start = (h.start + 1) % len(h.buffer)
+	}
+
+	for _, listener := range h.listeners {
+		listener(event)
+	}
+	for sub := range h.subscribers {
+		if !sub.wants(event) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			h.drop(sub)
+		}
+	}
+}
+
+// at возвращает i-е по старшинству событие буфера
+func (h *Hub) at(i int) Event {
+	return h.buffer[(h.start+i)%len(h.buffer)]
+}
+
+// drop отключает подписчика. Вызывается под h.mu.
+func (h *Hub) drop(sub *Subscription) {
+	if _, ok := h.subscribers[sub]; ok {
+		delete(h.subscribers, sub)
+		close(sub.ch)
+	}
+}
+
+// previousOwner возвращает прежнего владельца из полей «до» или 0,
+// если владелец не менялся
+func previousOwner(before json.RawMessage) uint {
+	if len(before) == 0 {
+		return 0
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(before, &fields); err != nil {
+		return 0
+	}
+	if owner, ok := fields["user_id"].(float64); ok {
+		return uint(owner)
+	}
+	return 0
+}
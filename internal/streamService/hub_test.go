@@ -0,0 +1,191 @@
+package streamService
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"newproject/internal/identity"
+	"newproject/internal/outbox"
+	"newproject/internal/tenant"
+	"strconv"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// owners — задачи и их владельцы, участников нет
+type owners map[uint]uint
+
+func (o owners) TaskAudience(_ context.Context, id uint) (uint, []uint, error) {
+	owner, ok := o[id]
+	if !ok {
+		return 0, nil, gorm.ErrRecordNotFound
+	}
+	return owner, nil, nil
+}
+
+func subscriberContext(userID uint) context.Context {
+	ctx := tenant.WithWorkspace(context.Background(), 1)
+	return identity.WithCaller(ctx, identity.Caller{UserID: userID, Role: "user"})
+}
+
+// publish отправляет в ленту изменение задачи taskID в пространстве 1
+func publish(t *testing.T, hub *Hub, id, taskID uint) {
+	t.Helper()
+	workspaceID := uint(1)
+	err := hub.Publish(context.Background(), outbox.OutboxMessage{
+		ID:          id,
+		Event:       outbox.EventTaskUpdated,
+		WorkspaceID: &workspaceID,
+		Data:        fmt.Sprintf(`{"id":%d,"after":{"task":"x"}}`, taskID),
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("publish %d: %v", id, err)
+	}
+}
+
+func eventIDs(events []Event) []uint {
+	ids := make([]uint, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	return ids
+}
+
+func TestSubscribeBacklog(t *testing.T) {
+	// Задача 10 принадлежит пользователю 1, задача 20 — пользователю 2
+	hub := NewHub(owners{10: 1, 20: 2}, 4)
+	for id := uint(1); id <= 6; id++ {
+		taskID := uint(10)
+		if id%2 == 0 {
+			taskID = 20
+		}
+		publish(t, hub, id, taskID)
+	}
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		wantReset   bool
+		wantBacklog []uint
+		wantErr     error
+	}{
+		{name: "new stream", wantBacklog: []uint{}},
+		{name: "resume skips other users' events", lastEventID: "3", wantBacklog: []uint{5}},
+		{name: "resume from the latest event", lastEventID: "6", wantBacklog: []uint{}},
+		{name: "missed events were evicted", lastEventID: "1", wantReset: true, wantBacklog: []uint{}},
+		{name: "unknown event", lastEventID: "100", wantReset: true, wantBacklog: []uint{}},
+		{name: "not an event id", lastEventID: "abc", wantErr: ErrInvalidLastEventID},
+	}
+	for _, tt := range tests {
+		sub, err := hub.Subscribe(subscriberContext(1), nil, tt.lastEventID)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: got error %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		sub.Close()
+
+		if sub.Reset != tt.wantReset {
+			t.Errorf("%s: got reset %v, want %v", tt.name, sub.Reset, tt.wantReset)
+		}
+		if sub.LatestID != 6 {
+			t.Errorf("%s: got latest id %d, want 6", tt.name, sub.LatestID)
+		}
+		if got := eventIDs(sub.Backlog); fmt.Sprint(got) != fmt.Sprint(tt.wantBacklog) {
+			t.Errorf("%s: got backlog %v, want %v", tt.name, got, tt.wantBacklog)
+		}
+	}
+}
+
+func TestSubscribeOtherUser(t *testing.T) {
+	hub := NewHub(owners{}, 0)
+	other := uint(2)
+
+	if _, err := hub.Subscribe(subscriberContext(1), &other, ""); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("user: got %v, want %v", err, ErrUserNotFound)
+	}
+	// Роль в системе не дает прав внутри пространства, роль в пространстве — дает
+	platformAdmin := identity.WithCaller(tenant.WithWorkspace(context.Background(), 1), identity.Caller{UserID: 1, Role: "admin", WorkspaceRole: "member"})
+	if _, err := hub.Subscribe(platformAdmin, &other, ""); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("platform admin: got %v, want %v", err, ErrUserNotFound)
+	}
+	admin := identity.WithCaller(tenant.WithWorkspace(context.Background(), 1), identity.Caller{UserID: 1, WorkspaceRole: "admin"})
+	sub, err := hub.Subscribe(admin, &other, "")
+	if err != nil {
+		t.Fatalf("admin: %v", err)
+	}
+	sub.Close()
+}
+
+func TestLiveEventsReachOnlyTheirAudience(t *testing.T) {
+	hub := NewHub(owners{10: 1, 20: 2}, 0)
+	sub, err := hub.Subscribe(subscriberContext(1), nil, "")
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	defer sub.Close()
+
+	publish(t, hub, 1, 20)
+	publish(t, hub, 2, 10)
+
+	select {
+	case event := <-sub.C:
+		if event.ID != 2 || event.TaskID != 10 || event.OwnerID != 1 {
+			t.Errorf("event: got id %d task %d owner %d, want 2, 10, 1", event.ID, event.TaskID, event.OwnerID)
+		}
+	default:
+		t.Fatal("event was not delivered")
+	}
+	select {
+	case event := <-sub.C:
+		t.Errorf("unexpected event %d", event.ID)
+	default:
+	}
+}
+
+func TestSlowSubscriberIsDisconnected(t *testing.T) {
+	hub := NewHub(owners{10: 1}, 2*subscriberBuffer)
+	slow, err := hub.Subscribe(subscriberContext(1), nil, "")
+	if err != nil {
+		t.Fatalf("subscribe slow: %v", err)
+	}
+	fast, err := hub.Subscribe(subscriberContext(1), nil, "")
+	if err != nil {
+		t.Fatalf("subscribe fast: %v", err)
+	}
+	defer fast.Close()
+
+	// Быстрый подписчик забирает каждое событие, медленный — ни одного
+	last := uint(subscriberBuffer + 1)
+	for id := uint(1); id <= last; id++ {
+		publish(t, hub, id, 10)
+		if event := <-fast.C; event.ID != id {
+			t.Fatalf("fast subscriber: got event %d, want %d", event.ID, id)
+		}
+	}
+
+	// Медленный получает то, что успело лечь в канал, и канал закрывается
+	received := 0
+	for range slow.C {
+		received++
+	}
+	if received != subscriberBuffer {
+		t.Errorf("slow subscriber: got %d events, want %d", received, subscriberBuffer)
+	}
+	slow.Close()
+
+	// Переподключившись с последним полученным ID, он добирает пропущенное
+	resumed, err := hub.Subscribe(subscriberContext(1), nil, strconv.Itoa(subscriberBuffer))
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	defer resumed.Close()
+	if resumed.Reset || len(resumed.Backlog) != 1 || resumed.Backlog[0].ID != last {
+		t.Errorf("resume: got reset %v, backlog %v, want event %d", resumed.Reset, eventIDs(resumed.Backlog), last)
+	}
+}
@@ -0,0 +1,73 @@
+package taskService
+
+import (
+	"context"
+	"fmt"
+	"newproject/internal/identity"
+	"newproject/internal/pagination"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// GetActivity возвращает страницу ленты активности задачи в порядке событий.
+// Ленту видит тот, кому видна сама задача.
+func (s *TaskService) GetActivity(ctx context.Context, id uint, page pagination.Page) ([]TaskEvent, string, error) {
+	if _, err := s.GetTaskByID(ctx, id); err != nil {
+		return nil, "", err
+	}
+	return s.repo.GetTaskEvents(ctx, id, page)
+}
+
+// Summary описывает событие одной фразой для ленты
+func (e TaskEvent) Summary() string {
+	switch e.Type {
+	case EventCreated:
+		return "created the task"
+	case EventRenamed:
+		return fmt.Sprintf("renamed the task from %q to %q", e.OldValue, e.NewValue)
+	case EventCompleted:
+		return "completed the task"
+	case EventReopened:
+		return "reopened the task"
+	case EventReassigned:
+		return fmt.Sprintf("reassigned the task from user %s to user %s", e.OldValue, e.NewValue)
+	default:
+		return e.Type
+	}
+}
+
+// changeEvents сравнивает задачу до и после обновления и возвращает события ленты
+func changeEvents(ctx context.Context, before, after Task) []TaskEvent {
+	var events []TaskEvent
+	if before.Task != after.Task {
+		events = append(events, newEvent(ctx, after.ID, EventRenamed, before.Task, after.Task))
+	}
+	if !before.IsDone && after.IsDone {
+		events = append(events, newEvent(ctx, after.ID, EventCompleted, "", ""))
+	} else if before.IsDone && !after.IsDone {
+		events = append(events, newEvent(ctx, after.ID, EventReopened, "", ""))
+	}
+	if before.UserID != after.UserID {
+		events = append(events, newEvent(ctx, after.ID, EventReassigned,
+			strconv.FormatUint(uint64(before.UserID), 10), strconv.FormatUint(uint64(after.UserID), 10)))
+	}
+	return events
+}
+
+// newEvent создает событие от имени вызывающего. Пространство заполнит tenant.
+func newEvent(ctx context.Context, taskID uint, eventType, oldValue, newValue string) TaskEvent {
+	event := TaskEvent{TaskID: taskID, Type: eventType, OldValue: oldValue, NewValue: newValue}
+	if caller, ok := identity.FromContext(ctx); ok {
+		event.ActorID = &caller.UserID
+	}
+	return event
+}
+
+// addEvents сохраняет события в транзакции изменения задачи
+func addEvents(tx *gorm.DB, events ...TaskEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return tx.Create(&events).Error
+}
@@ -0,0 +1,139 @@
+package taskService
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"newproject/internal/identity"
+	"newproject/internal/pagination"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrAssigneeNotFound — пользователь не участвует в задаче в этой роли
+	ErrAssigneeNotFound = fmt.Errorf("user is not assigned to the task: %w", gorm.ErrRecordNotFound)
+	// ErrInvalidAssignee — назначаемого пользователя нет в рабочем пространстве
+	ErrInvalidAssignee = errors.New("assignee must be a member of the workspace")
+	// ErrInvalidRole — роль участника не из списка assignee/watcher
+	ErrInvalidRole = errors.New("role must be one of assignee, watcher")
+)
+
+// UserLookup — то, что TaskService нужно знать о пользователях, чтобы назначать их на задачи
+type UserLookup interface {
+	// UserExists сообщает, есть ли пользователь с таким ID в рабочем пространстве из контекста
+	UserExists(ctx context.Context, id uint) (bool, error)
+}
+
+// Assign делает пользователя участником задачи в роли role. Повторный вызов
+// с другой ролью меняет роль. Назначать может тот, кто может менять задачу.
+func (s *TaskService) Assign(ctx context.Context, taskID, userID uint, role string) (Task, error) {
+	if _, err := s.editableTask(ctx, taskID); err != nil {
+		return Task{}, err
+	}
+	if !validRole(role) {
+		return Task{}, ErrInvalidRole
+	}
+	if err := s.checkUser(ctx, userID); err != nil {
+		return Task{}, err
+	}
+
+	if err := s.repo.SetAssignee(ctx, taskID, userID, role); err != nil {
+		return Task{}, err
+	}
+	return s.repo.GetTaskByID(ctx, taskID)
+}
+
+// Unassign убирает пользователя с роли role в задаче. Снять себя может любой
+// участник, других — только тот, кто может менять задачу.
+func (s *TaskService) Unassign(ctx context.Context, taskID, userID uint, role string) error {
+	task, err := s.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	caller, _ := identity.FromContext(ctx)
+	if userID != caller.UserID && !canEdit(caller, task) {
+		return ErrForbidden
+	}
+	if !validRole(role) {
+		return ErrInvalidRole
+	}
+
+	err = s.repo.RemoveAssignee(ctx, taskID, userID, role)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrAssigneeNotFound
+	}
+	return err
+}
+
+// GetAssignedTasks возвращает задачи, где пользователь участвует в роли role.
+// Права доступа — как у GetTasksByUserID.
+func (s *TaskService) GetAssignedTasks(ctx context.Context, userID uint, role string, page pagination.Page) ([]Task, string, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if caller.UserID != userID && !caller.IsAdmin() {
+		return nil, "", gorm.ErrRecordNotFound
+	}
+	if !validRole(role) {
+		return nil, "", ErrInvalidRole
+	}
+	return s.repo.GetTasks(ctx, TaskFilter{AssigneeID: &userID, AssigneeRole: role}, page)
+}
+
+// ReleaseUser снимает пользователя со всех задач рабочего пространства из
+// контекста, где он участник. Если задан reassignTo, его задачи как
+// исполнителя переходят к reassignTo.
+func (s *TaskService) ReleaseUser(ctx context.Context, userID uint, reassignTo *uint) error {
+	if reassignTo != nil {
+		if *reassignTo == userID {
+			return ErrInvalidAssignee
+		}
+		if err := s.checkUser(ctx, *reassignTo); err != nil {
+			return err
+		}
+	}
+	return s.repo.ReleaseUser(ctx, userID, reassignTo)
+}
+
+// editableTask загружает задачу, если вызывающий может ее менять
+func (s *TaskService) editableTask(ctx context.Context, id uint) (Task, error) {
+	task, err := s.GetTaskByID(ctx, id)
+	if err != nil {
+		return Task{}, err
+	}
+	caller, _ := identity.FromContext(ctx)
+	if !canEdit(caller, task) {
+		return Task{}, ErrForbidden
+	}
+	return task, nil
+}
+
+// checkUser проверяет, что пользователя можно назначить на задачу или сделать ее владельцем
+func (s *TaskService) checkUser(ctx context.Context, userID uint) error {
+	exists, err := s.users.UserExists(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrInvalidAssignee
+	}
+	return nil
+}
+
+// roleOf возвращает роль пользователя в задаче или пустую строку
+func roleOf(task Task, userID uint) string {
+	for _, a := range task.Assignees {
+		if a.UserID == userID {
+			return a.Role
+		}
+	}
+	return ""
+}
+
+// validRole сообщает, является ли role одной из ролей участника
+func validRole(role string) bool {
+	return role == RoleAssignee || role == RoleWatcher
+}
@@ -0,0 +1,9 @@
+package taskService
+
+// Dependencies — связи задачи в графе блокировок
+type Dependencies struct {
+	// Blockers — задачи, которые нужно выполнить до этой
+	Blockers []Task `json:"blockers"`
+	// Dependents — задачи, которые ждут выполнения этой
+	Dependents []Task `json:"dependents"`
+}
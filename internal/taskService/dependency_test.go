@@ -0,0 +1,111 @@
+package taskService
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAddDependencyRejectsCycles(t *testing.T) {
+	repo := NewTaskRepository(openTestDB(t))
+	ctx := callerContext()
+	service := NewTaskService(repo, noProjects{}, anyUser{}, CompletionBlock)
+
+	// a блокирует b, b блокирует c
+	var a, b, c, d Task
+	for _, task := range []*Task{&a, &b, &c, &d} {
+		created, err := service.CreateTask(ctx, Task{Task: "task"})
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		*task = created
+	}
+	for _, link := range [][2]uint{{a.ID, b.ID}, {b.ID, c.ID}} {
+		if err := service.AddDependency(ctx, link[0], link[1]); err != nil {
+			t.Fatalf("add %d → %d: %v", link[0], link[1], err)
+		}
+	}
+
+	tests := []struct {
+		name             string
+		blocker, blocked uint
+		wantErr          error
+	}{
+		{name: "self", blocker: a.ID, blocked: a.ID, wantErr: ErrDependencyCycle},
+		{name: "direct cycle", blocker: b.ID, blocked: a.ID, wantErr: ErrDependencyCycle},
+		{name: "transitive cycle", blocker: c.ID, blocked: a.ID, wantErr: ErrDependencyCycle},
+		{name: "unknown blocker", blocker: 999, blocked: a.ID, wantErr: ErrTaskNotFound},
+		{name: "shortcut", blocker: a.ID, blocked: c.ID},
+		{name: "new task", blocker: c.ID, blocked: d.ID},
+	}
+	for _, tt := range tests {
+		if err := service.AddDependency(ctx, tt.blocker, tt.blocked); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCompletingBlockedTask(t *testing.T) {
+	tests := []struct {
+		mode CompletionMode
+	}{
+		{mode: CompletionBlock},
+		{mode: CompletionCascade},
+		{mode: CompletionOff},
+	}
+	for _, tt := range tests {
+		repo := NewTaskRepository(openTestDB(t))
+		ctx := callerContext()
+		service := NewTaskService(repo, noProjects{}, anyUser{}, tt.mode)
+
+		blocker, err := service.CreateTask(ctx, Task{Task: "blocker"})
+		if err != nil {
+			t.Fatalf("%s: create blocker: %v", tt.mode, err)
+		}
+		blocked, err := service.CreateTask(ctx, Task{Task: "blocked"})
+		if err != nil {
+			t.Fatalf("%s: create blocked: %v", tt.mode, err)
+		}
+		if err := service.AddDependency(ctx, blocker.ID, blocked.ID); err != nil {
+			t.Fatalf("%s: add dependency: %v", tt.mode, err)
+		}
+
+		if _, err := service.UpdateTaskByID(ctx, blocked.ID, Task{Task: "blocked", IsDone: true}); !errors.Is(err, ErrBlocked) {
+			t.Errorf("%s: complete while blocked: got %v, want %v", tt.mode, err, ErrBlocked)
+		}
+		if _, err := service.UpdateTaskByID(ctx, blocker.ID, Task{Task: "blocker", IsDone: true}); err != nil {
+			t.Fatalf("%s: complete blocker: %v", tt.mode, err)
+		}
+		if _, err := service.UpdateTaskByID(ctx, blocked.ID, Task{Task: "blocked", IsDone: true}); err != nil {
+			t.Errorf("%s: complete after blocker: %v", tt.mode, err)
+		}
+	}
+}
+
+func TestCascadeIsBlockedBySubtaskBlockers(t *testing.T) {
+	repo := NewTaskRepository(openTestDB(t))
+	ctx := callerContext()
+	service := NewTaskService(repo, noProjects{}, anyUser{}, CompletionCascade)
+
+	parent, child, grandchild := createChain(t, ctx, service)
+	outside, err := service.CreateTask(ctx, Task{Task: "outside"})
+	if err != nil {
+		t.Fatalf("create outside: %v", err)
+	}
+	// Связь внутри поддерева каскаду не мешает, связь снаружи — мешает
+	if err := service.AddDependency(ctx, child.ID, grandchild.ID); err != nil {
+		t.Fatalf("add inner dependency: %v", err)
+	}
+	if err := service.AddDependency(ctx, outside.ID, grandchild.ID); err != nil {
+		t.Fatalf("add outer dependency: %v", err)
+	}
+
+	if _, err := service.UpdateTaskByID(ctx, parent.ID, Task{Task: "parent", IsDone: true}); !errors.Is(err, ErrBlocked) {
+		t.Fatalf("complete: got %v, want %v", err, ErrBlocked)
+	}
+	if err := service.RemoveDependency(ctx, outside.ID, grandchild.ID); err != nil {
+		t.Fatalf("remove outer dependency: %v", err)
+	}
+	if _, err := service.UpdateTaskByID(ctx, parent.ID, Task{Task: "parent", IsDone: true}); err != nil {
+		t.Errorf("complete without outer blocker: %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package taskService
+
+import (
+	"newproject/internal/pagination"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Режимы фильтра по меткам
+const (
+	// TagMatchAny — задача с любой из меток
+	TagMatchAny = "any"
+	// TagMatchAll — задача со всеми метками сразу
+	TagMatchAll = "all"
+)
+
+// TaskFilter — условия выборки задач. Нулевые поля выборку не ограничивают.
+type TaskFilter struct {
+	IsDone        *bool
+	UserID        *uint
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+	// Overdue — только просроченные (true) или только непросроченные (false) задачи.
+	// Просроченная задача не выполнена, и ее due_at уже прошел.
+	Overdue *bool
+	// ParentID — только прямые подзадачи этой задачи
+	ParentID *uint
+	// ProjectID — только задачи этого проекта
+	ProjectID *uint
+	// RootsOnly — только задачи верхнего уровня, без подзадач
+	RootsOnly bool
+	// AssigneeID — только задачи, где пользователь участвует в роли AssigneeRole
+	AssigneeID   *uint
+	AssigneeRole string
+	// StatusID — только задачи в этом статусе
+	StatusID *uint
+	// TagIDs — только задачи с этими метками, как именно — задает TagMatch
+	TagIDs []uint
+	// TagMatch — TagMatchAny (по умолчанию) или TagMatchAll
+	TagMatch string
+	// Query — подстрока текста задачи, без учета регистра
+	Query string
+}
+
+// SortColumns — поля, по которым можно сортировать задачи
+var SortColumns = map[string]pagination.Column{
+	"created_at": {Name: "created_at", Kind: pagination.KindTime},
+	"updated_at": {Name: "updated_at", Kind: pagination.KindTime},
+	"task":       {Name: "task", Kind: pagination.KindString},
+	"is_done":    {Name: "is_done", Kind: pagination.KindBool},
+}
+
+// apply переводит фильтр в условия GORM. Значения передаются только
+// параметрами запроса.
+func (f TaskFilter) apply(db *gorm.DB) *gorm.DB {
+	if f.IsDone != nil {
+		db = db.Where("is_done = ?", *f.IsDone)
+	}
+	if f.UserID != nil {
+		db = db.Where("user_id = ?", *f.UserID)
+	}
+	if f.ParentID != nil {
+		db = db.Where("parent_id = ?", *f.ParentID)
+	}
+	if f.ProjectID != nil {
+		db = db.Where("project_id = ?", *f.ProjectID)
+	}
+	if f.AssigneeID != nil {
+		db = db.Where("id IN (SELECT task_id FROM task_assignees WHERE user_id = ? AND role = ?)", *f.AssigneeID, f.AssigneeRole)
+	}
+	if f.StatusID != nil {
+		db = db.Where("status_id = ?", *f.StatusID)
+	}
+	if f.RootsOnly {
+		db = db.Where("parent_id IS NULL")
+	}
+	if len(f.TagIDs) > 0 {
+		if f.TagMatch == TagMatchAll {
+			db = db.Where("id IN (SELECT task_id FROM task_tags WHERE tag_id IN ? GROUP BY task_id HAVING COUNT(DISTINCT tag_id) = ?)",
+				f.TagIDs, len(uniqueIDs(f.TagIDs)))
+		} else {
+			db = db.Where("id IN (SELECT task_id FROM task_tags WHERE tag_id IN ?)", f.TagIDs)
+		}
+	}
+	if f.CreatedAfter != nil {
+		db = db.Where("created_at >= ?", *f.CreatedAfter)
+	}
+	if f.CreatedBefore != nil {
+		db = db.Where("created_at < ?", *f.CreatedBefore)
+	}
+	if f.UpdatedAfter != nil {
+		db = db.Where("updated_at >= ?", *f.UpdatedAfter)
+	}
+	if f.UpdatedBefore != nil {
+		db = db.Where("updated_at < ?", *f.UpdatedBefore)
+	}
+	if f.Overdue != nil {
+		if *f.Overdue {
+			db = db.Where("is_done = ? AND due_at < ?", false, time.Now())
+		} else {
+			db = db.Where("(is_done = ? OR due_at IS NULL OR due_at >= ?)", true, time.Now())
+		}
+	}
+	if f.Query != "" {
+		db = db.Where(`LOWER(task) LIKE LOWER(?) ESCAPE '\'`, "%"+escapeLike(f.Query)+"%")
+	}
+	return db
+}
+
+// uniqueIDs убирает повторы, чтобы режим all не требовал одну метку дважды
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// escapeLike экранирует спецсимволы LIKE, чтобы они искались буквально
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -1,10 +1,175 @@
 package taskService
 
-import "gorm.io/gorm"
+import (
+	"newproject/internal/auditService"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Приоритеты задачи
+const (
+	PriorityLow    = "low"
+	PriorityNormal = "normal"
+	PriorityHigh   = "high"
+	PriorityUrgent = "urgent"
+)
 
 type Task struct {
 	gorm.Model
-	Task   string `json:"task"`
-	IsDone bool   `json:"is_done"`
-	UserID uint   `json:"user_id"` // ID пользователя, связанный с задачей
+	// WorkspaceID — рабочее пространство задачи, заполняется из контекста запроса
+	WorkspaceID uint   `gorm:"not null;index;tenant" json:"workspace_id"`
+	Task        string `json:"task"`
+	// IsDone выводится из категории статуса и хранится для фильтров и совместимости API
+	IsDone   bool       `json:"is_done"`
+	UserID   uint       `json:"user_id"` // ID пользователя, связанный с задачей
+	DueAt    *time.Time `json:"due_at"`
+	Priority string     `gorm:"type:varchar(16);not null;default:normal" json:"priority"`
+	// CompletedAt выставляется автоматически, когда задача становится выполненной
+	CompletedAt *time.Time `json:"completed_at"`
+	// ParentID — родительская задача, nil у задач верхнего уровня
+	ParentID *uint `gorm:"index" json:"parent_id"`
+	// ProjectID — проект, в котором лежит задача, nil у задач вне проектов
+	ProjectID *uint `gorm:"index" json:"project_id"`
+	// Position — место задачи в ручном порядке внутри проекта
+	Position int `gorm:"not null;default:0" json:"position"`
+	// Tags подгружаются репозиторием одним запросом на всю выборку
+	Tags []Tag `gorm:"many2many:task_tags" json:"tags"`
+	// Recurrence — правило RRULE серии, пустое у неповторяющихся задач
+	Recurrence string `gorm:"size:255;not null;default:''" json:"recurrence"`
+	// SeriesID — серия повторяющейся задачи, nil у неповторяющихся
+	SeriesID *uint `gorm:"index" json:"series_id"`
+	// StatusID — колонка канбан-доски владельца задачи
+	StatusID *uint       `gorm:"index" json:"status_id"`
+	Status   *TaskStatus `gorm:"constraint:OnDelete:SET NULL" json:"status,omitempty"`
+	// Assignees — исполнители и наблюдатели задачи помимо владельца
+	Assignees []TaskAssignee `gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE" json:"assignees"`
+}
+
+// AuditEntity — имя задачи в журнале аудита
+func (Task) AuditEntity() string {
+	return auditService.EntityTask
+}
+
+// Роли участника задачи
+const (
+	// RoleAssignee — исполнитель: видит задачу и может ее менять
+	RoleAssignee = "assignee"
+	// RoleWatcher — наблюдатель: видит задачу и может ее комментировать
+	RoleWatcher = "watcher"
+)
+
+// TaskAssignee — участник чужой задачи. У пользователя одна роль на задачу.
+type TaskAssignee struct {
+	TaskID    uint      `gorm:"primaryKey;autoIncrement:false" json:"-"`
+	UserID    uint      `gorm:"primaryKey;autoIncrement:false;index" json:"user_id"`
+	Role      string    `gorm:"type:varchar(16);not null" json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Категории статусов. Задача в статусе категории done считается выполненной.
+const (
+	CategoryTodo       = "todo"
+	CategoryInProgress = "in_progress"
+	CategoryDone       = "done"
+)
+
+// TaskStatus — статус задачи на канбан-доске пользователя. У пользователя
+// своя доска в каждом рабочем пространстве.
+type TaskStatus struct {
+	ID          uint   `gorm:"primarykey" json:"id"`
+	WorkspaceID uint   `gorm:"not null;index;tenant" json:"workspace_id"`
+	UserID      uint   `gorm:"not null;index" json:"user_id"`
+	Name        string `gorm:"size:64;not null" json:"name"`
+	Category    string `gorm:"type:varchar(16);not null" json:"category"`
+	Position    int    `gorm:"not null;default:0" json:"position"`
+	// Transitions — статусы, в которые разрешено переводить задачу из этого.
+	// Пустой список разрешает любой переход.
+	Transitions []TaskStatus `gorm:"many2many:task_status_transitions;joinForeignKey:FromID;joinReferences:ToID" json:"-"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// defaultStatuses — доска, которую получает пользователь при первом обращении к статусам.
+// Переходы заданы по индексам в этом же списке. Из backlog можно сразу закрыть
+// задачу, чтобы на доске по умолчанию работал PATCH с isDone.
+var defaultStatuses = []struct {
+	Name        string
+	Category    string
+	Transitions []int
+}{
+	{"backlog", CategoryTodo, []int{1, 3}},
+	{"in_progress", CategoryInProgress, []int{0, 2, 3}},
+	{"review", CategoryInProgress, []int{1, 3}},
+	{"done", CategoryDone, []int{1}},
+}
+
+// TaskSeries — шаблон повторяющейся задачи. Из него создается следующее
+// вхождение, когда текущее выполнено.
+type TaskSeries struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	WorkspaceID uint      `gorm:"not null;index;tenant" json:"workspace_id"`
+	UserID      uint      `gorm:"index" json:"user_id"`
+	Task        string    `json:"task"`
+	Priority    string    `gorm:"type:varchar(16);not null;default:normal" json:"priority"`
+	Recurrence  string    `gorm:"size:255;not null;default:''" json:"recurrence"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Tag — метка пользователя, которую можно навесить на его задачи
+// в том же рабочем пространстве
+type Tag struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	WorkspaceID uint      `gorm:"not null;uniqueIndex:idx_tags_workspace_user_name;tenant" json:"workspace_id"`
+	UserID      uint      `gorm:"not null;uniqueIndex:idx_tags_workspace_user_name" json:"user_id"`
+	Name        string    `gorm:"size:64;not null;uniqueIndex:idx_tags_workspace_user_name" json:"name"`
+	Color       string    `gorm:"size:16;not null;default:''" json:"color"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TaskDependency — связь «задача BlockerID блокирует задачу BlockedID»
+type TaskDependency struct {
+	BlockerID uint      `gorm:"primaryKey;autoIncrement:false" json:"blocker_id"`
+	BlockedID uint      `gorm:"primaryKey;autoIncrement:false;index" json:"blocked_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Типы событий в ленте задачи
+const (
+	EventCreated    = "created"
+	EventRenamed    = "renamed"
+	EventCompleted  = "completed"
+	EventReopened   = "reopened"
+	EventReassigned = "reassigned"
+)
+
+// TaskEvent — событие в ленте активности задачи. OldValue и NewValue
+// заполнены у переименования (название) и смены владельца (ID пользователя).
+type TaskEvent struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	WorkspaceID uint      `gorm:"not null;tenant" json:"workspace_id"`
+	TaskID      uint      `gorm:"not null;index:idx_task_events_task_id" json:"task_id"`
+	ActorID     *uint     `json:"actor_id"`
+	Type        string    `gorm:"type:varchar(16);not null" json:"type"`
+	OldValue    string    `gorm:"not null;default:''" json:"old_value"`
+	NewValue    string    `gorm:"not null;default:''" json:"new_value"`
+	CreatedAt   time.Time `gorm:"index:idx_task_events_task_id" json:"created_at"`
+}
+
+// validCategory сообщает, является ли c одной из категорий статуса
+func validCategory(c string) bool {
+	switch c {
+	case CategoryTodo, CategoryInProgress, CategoryDone:
+		return true
+	}
+	return false
+}
+
+// validPriority сообщает, является ли p одним из допустимых приоритетов
+func validPriority(p string) bool {
+	switch p {
+	case PriorityLow, PriorityNormal, PriorityHigh, PriorityUrgent:
+		return true
+	}
+	return false
 }
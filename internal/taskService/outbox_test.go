@@ -0,0 +1,107 @@
+package taskService
+
+import (
+	"context"
+	"errors"
+	"newproject/internal/auditService"
+	"newproject/internal/outbox"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// openOutboxDB добавляет к тестовой базе журнал аудита и outbox, как в cmd/app
+func openOutboxDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := openTestDB(t)
+	if err := db.Use(auditService.Plugin{}); err != nil {
+		t.Fatalf("audit plugin: %v", err)
+	}
+	if err := db.Use(outbox.Plugin{}); err != nil {
+		t.Fatalf("outbox plugin: %v", err)
+	}
+	if err := db.AutoMigrate(&auditService.AuditEntry{}, &outbox.OutboxMessage{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// outboxEvents возвращает события outbox по порядку записи
+func outboxEvents(t *testing.T, db *gorm.DB) []string {
+	t.Helper()
+	var events []string
+	if err := db.Model(&outbox.OutboxMessage{}).Order("id").Pluck("event", &events).Error; err != nil {
+		t.Fatalf("outbox: %v", err)
+	}
+	return events
+}
+
+func TestOutboxIsWrittenInTaskTransaction(t *testing.T) {
+	db := openOutboxDB(t)
+	repo := NewTaskRepository(db)
+	ctx := callerContext()
+
+	var inside []string
+	err := repo.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := repo.CreateTask(ctx, Task{Task: "draft", UserID: 1}); err != nil {
+			return err
+		}
+		// Сообщение уже записано, но видно только внутри транзакции
+		inside = outboxEvents(t, repo.conn(ctx))
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("transaction: got %v, want %v", err, errBoom)
+	}
+
+	if len(inside) != 1 || inside[0] != outbox.EventTaskCreated {
+		t.Errorf("inside transaction: got %v, want [%s]", inside, outbox.EventTaskCreated)
+	}
+	if events := outboxEvents(t, db); len(events) != 0 {
+		t.Errorf("after rollback: got %v, want no messages", events)
+	}
+	var tasks int64
+	db.WithContext(ctx).Model(&Task{}).Count(&tasks)
+	if tasks != 0 {
+		t.Errorf("after rollback: got %d tasks, want 0", tasks)
+	}
+}
+
+func TestOutboxRollsBackWithTaskUpdate(t *testing.T) {
+	db := openOutboxDB(t)
+	repo := NewTaskRepository(db)
+	ctx := callerContext()
+	service := NewTaskService(repo, noProjects{}, anyUser{}, CompletionCascade)
+
+	task, err := service.CreateTask(ctx, Task{Task: "daily", Recurrence: "FREQ=DAILY"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	want := []string{outbox.EventTaskCreated}
+	if events := outboxEvents(t, db); len(events) != 1 || events[0] != want[0] {
+		t.Fatalf("after create: got %v, want %v", events, want)
+	}
+
+	// Задача успевает обновиться, но последний шаг падает и откатывает все
+	failing := NewTaskService(failingRepo{repo}, noProjects{}, anyUser{}, CompletionCascade)
+	if _, err := failing.UpdateTaskByID(ctx, task.ID, Task{Task: "renamed", IsDone: true}); !errors.Is(err, errBoom) {
+		t.Fatalf("update: got %v, want %v", err, errBoom)
+	}
+	if events := outboxEvents(t, db); len(events) != 1 {
+		t.Errorf("after failed update: got %v, want %v", events, want)
+	}
+
+	if _, err := service.UpdateTaskByID(ctx, task.ID, Task{Task: "renamed", IsDone: true}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	events := outboxEvents(t, db)
+	completed := 0
+	for _, event := range events {
+		if event == outbox.EventTaskCompleted {
+			completed++
+		}
+	}
+	if completed != 1 {
+		t.Errorf("after update: got %v, want one %s", events, outbox.EventTaskCompleted)
+	}
+}
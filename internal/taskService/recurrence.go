@@ -0,0 +1,147 @@
+package taskService
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// EditScope — на какие вхождения серии распространяется правка
+type EditScope string
+
+const (
+	// ScopeThis меняет только само вхождение, следующие создаются по шаблону серии
+	ScopeThis EditScope = "this"
+	// ScopeFuture меняет вхождение, шаблон серии и ее открытые вхождения не раньше этого
+	ScopeFuture EditScope = "future"
+)
+
+var (
+	// ErrInvalidScope — область правки не this и не future
+	ErrInvalidScope = errors.New("scope must be one of this, future")
+	// ErrRecurrenceScope — правило серии меняется только для всех будущих вхождений
+	ErrRecurrenceScope = errors.New("recurrence can only be changed with scope=future")
+)
+
+// ParseEditScope разбирает параметр scope; пустая строка означает ScopeThis
+func ParseEditScope(s string) (EditScope, error) {
+	switch EditScope(s) {
+	case "", ScopeThis:
+		return ScopeThis, nil
+	case ScopeFuture:
+		return ScopeFuture, nil
+	}
+	return "", ErrInvalidScope
+}
+
+// SeriesEdit — параметры правки повторяющейся задачи. Recurrence == nil
+// оставляет правило как есть, пустая строка прекращает повторение.
+type SeriesEdit struct {
+	Scope      EditScope
+	Recurrence *string
+}
+
+// normalizeRecurrence проверяет правило и приводит его к каноническому виду
+func normalizeRecurrence(recurrence string) (string, error) {
+	if recurrence == "" {
+		return "", nil
+	}
+	rule, err := ParseRule(recurrence)
+	if err != nil {
+		return "", err
+	}
+	return rule.String(), nil
+}
+
+// startSeries создает шаблон серии для задачи с правилом повторения
+func (s *TaskService) startSeries(ctx context.Context, task Task) (*uint, error) {
+	series, err := s.repo.CreateSeries(ctx, TaskSeries{
+		UserID:     task.UserID,
+		Task:       task.Task,
+		Priority:   task.Priority,
+		Recurrence: task.Recurrence,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &series.ID, nil
+}
+
+// updateSeries переносит правку вхождения на шаблон серии и ее будущие вхождения
+func (s *TaskService) updateSeries(ctx context.Context, task Task) error {
+	series, err := s.repo.GetSeriesByID(ctx, *task.SeriesID)
+	if err != nil {
+		return err
+	}
+
+	series.UserID = task.UserID
+	series.Task = task.Task
+	series.Priority = task.Priority
+	series.Recurrence = task.Recurrence
+	return s.repo.UpdateSeries(ctx, series, task)
+}
+
+// nextOccurrence создает следующее вхождение серии после выполнения done.
+// Срок считается от срока выполненного вхождения, а если его нет — от момента выполнения.
+func (s *TaskService) nextOccurrence(ctx context.Context, done Task) error {
+	series, err := s.repo.GetSeriesByID(ctx, *done.SeriesID)
+	if err != nil {
+		return err
+	}
+	if series.Recurrence == "" {
+		return nil
+	}
+	rule, err := ParseRule(series.Recurrence)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	if done.DueAt != nil {
+		start = *done.DueAt
+	} else if done.CompletedAt != nil {
+		start = *done.CompletedAt
+	}
+	next, ok := rule.Next(start, start)
+	if !ok {
+		return nil
+	}
+
+	if rule.Count > 0 {
+		count, err := s.repo.CountSeriesTasks(ctx, series.ID)
+		if err != nil {
+			return err
+		}
+		if count >= int64(rule.Count) {
+			return nil
+		}
+	}
+	// Повторное выполнение того же вхождения не должно плодить дубликаты
+	exists, err := s.repo.HasOccurrence(ctx, series.ID, next)
+	if err != nil || exists {
+		return err
+	}
+
+	occurrence := Task{
+		Task:       series.Task,
+		UserID:     done.UserID,
+		DueAt:      &next,
+		Priority:   series.Priority,
+		ParentID:   done.ParentID,
+		ProjectID:  done.ProjectID,
+		Recurrence: series.Recurrence,
+		SeriesID:   &series.ID,
+	}
+	created, err := s.create(ctx, occurrence)
+	if errors.Is(err, ErrProjectArchived) || errors.Is(err, ErrInvalidProject) {
+		// Проект ушел в архив или удален — серия продолжается вне проекта
+		occurrence.ProjectID = nil
+		created, err = s.create(ctx, occurrence)
+	}
+	if err != nil {
+		return err
+	}
+	log.Printf("Created occurrence %d of series %d due %s", created.ID, series.ID, next.Format(time.RFC3339))
+	return nil
+}
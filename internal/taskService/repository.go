@@ -1,17 +1,62 @@
 package taskService
 
 import (
-	"log"
+	"context"
+	"newproject/internal/pagination"
+	"newproject/internal/tenant"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TaskRepository interface {
-	CreateTask(task Task) (Task, error)
-	GetAllTasks() ([]Task, error)
-	UpdateTaskByID(id uint, task Task) (Task, error)
-	DeleteTaskByID(id uint) error
-	GetTasksByUserID(userID uint) ([]Task, error)
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+	CreateTask(ctx context.Context, task Task) (Task, error)
+	GetTasks(ctx context.Context, filter TaskFilter, page pagination.Page) ([]Task, string, error)
+	SearchTasks(ctx context.Context, query string, filter TaskFilter, limit int) ([]SearchResult, error)
+	GetTaskByID(ctx context.Context, id uint) (Task, error)
+	UpdateTaskByID(ctx context.Context, id uint, task Task) (Task, error)
+	DeleteTaskByID(ctx context.Context, id uint) error
+	CountOpenSubtasks(ctx context.Context, parentID uint) (int64, error)
+	CompleteSubtasks(ctx context.Context, parentID uint) error
+	GetAncestorIDs(ctx context.Context, id uint) ([]uint, error)
+	GetDescendants(ctx context.Context, rootIDs []uint) ([]Task, error)
+	AddDependency(ctx context.Context, blockerID, blockedID uint) error
+	RemoveDependency(ctx context.Context, blockerID, blockedID uint) error
+	GetBlockers(ctx context.Context, id uint) ([]Task, error)
+	GetDependents(ctx context.Context, id uint) ([]Task, error)
+	Blocks(ctx context.Context, blockerID, blockedID uint) (bool, error)
+	CountOpenBlockers(ctx context.Context, id uint) (int64, error)
+	CountExternalBlockers(ctx context.Context, rootID uint) (int64, error)
+	CreateTag(ctx context.Context, tag Tag) (Tag, error)
+	GetTagsByUserID(ctx context.Context, userID uint) ([]Tag, error)
+	GetTagByID(ctx context.Context, id uint) (Tag, error)
+	DeleteTagByID(ctx context.Context, id uint) error
+	AttachTag(ctx context.Context, taskID, tagID uint) error
+	DetachTag(ctx context.Context, taskID, tagID uint) error
+	CreateSeries(ctx context.Context, series TaskSeries) (TaskSeries, error)
+	GetSeriesByID(ctx context.Context, id uint) (TaskSeries, error)
+	UpdateSeries(ctx context.Context, series TaskSeries, from Task) error
+	CountSeriesTasks(ctx context.Context, seriesID uint) (int64, error)
+	HasOccurrence(ctx context.Context, seriesID uint, dueAt time.Time) (bool, error)
+	GetStatusesByUserID(ctx context.Context, userID uint) ([]TaskStatus, error)
+	GetStatusByID(ctx context.Context, id uint) (TaskStatus, error)
+	CreateDefaultStatuses(ctx context.Context, userID uint) ([]TaskStatus, error)
+	CreateStatus(ctx context.Context, status TaskStatus, transitions []uint) (TaskStatus, error)
+	UpdateStatus(ctx context.Context, status TaskStatus, transitions *[]uint) (TaskStatus, error)
+	DeleteStatusByID(ctx context.Context, id uint) error
+	CountTasksWithStatus(ctx context.Context, id uint) (int64, error)
+	SetStatusOrder(ctx context.Context, userID uint, statusIDs []uint) error
+	SetAssignee(ctx context.Context, taskID, userID uint, role string) error
+	RemoveAssignee(ctx context.Context, taskID, userID uint, role string) error
+	ReleaseUser(ctx context.Context, userID uint, reassignTo *uint) error
+	GetDeletedTasks(ctx context.Context, filter TaskFilter, page pagination.Page) ([]Task, string, error)
+	GetDeletedTaskByID(ctx context.Context, id uint) (Task, error)
+	RestoreTask(ctx context.Context, id uint) error
+	PurgeTasks(ctx context.Context, before time.Time) (int64, error)
+	GetTaskEvents(ctx context.Context, taskID uint, page pagination.Page) ([]TaskEvent, string, error)
+	TaskAudience(ctx context.Context, id uint) (uint, []uint, error)
 }
 
 type taskRepository struct {
@@ -22,51 +67,811 @@ func NewTaskRepository(db *gorm.DB) *taskRepository {
 	return &taskRepository{db: db}
 }
 
-func (r *taskRepository) CreateTask(task Task) (Task, error) {
-	result := r.db.Create(&task)
-	if result.Error != nil {
-		return Task{}, result.Error
+// txContextKey — ключ открытой Transaction в контексте
+type txContextKey struct{}
+
+// Transaction выполняет fn в одной транзакции: вызовы репозитория с контекстом,
+// переданным в fn, идут через нее. Ошибка fn откатывает все изменения.
+func (r *taskRepository) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txContextKey{}, tx))
+	})
+}
+
+// conn возвращает транзакцию из контекста, а вне Transaction — подключение репозитория
+func (r *taskRepository) conn(ctx context.Context) *gorm.DB {
+	if tx, ok := ctx.Value(txContextKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return r.db.WithContext(ctx)
+}
+
+func (r *taskRepository) CreateTask(ctx context.Context, task Task) (Task, error) {
+	err := r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		if task.ProjectID != nil {
+			position, err := nextPosition(tx, *task.ProjectID)
+			if err != nil {
+				return err
+			}
+			task.Position = position
+		}
+		if err := tx.Create(&task).Error; err != nil {
+			return err
+		}
+		return addEvents(tx, newEvent(ctx, task.ID, EventCreated, "", ""))
+	})
+	if err != nil {
+		return Task{}, err
 	}
 	return task, nil
 }
 
-func (r *taskRepository) GetAllTasks() ([]Task, error) {
+func (r *taskRepository) GetTasks(ctx context.Context, filter TaskFilter, page pagination.Page) ([]Task, string, error) {
 	var tasks []Task
-	err := r.db.Find(&tasks).Error
-	return tasks, err
+	if err := pagination.Apply(filter.apply(preload(r.conn(ctx))), page).Find(&tasks).Error; err != nil {
+		return nil, "", err
+	}
+	tasks, next := pagination.Trim(tasks, page, func(t Task) pagination.Cursor {
+		return page.CursorFor(sortValue(t, page.Order.Column.Name), t.ID)
+	})
+	return tasks, next, nil
 }
 
-func (r *taskRepository) UpdateTaskByID(id uint, task Task) (Task, error) {
+func (r *taskRepository) GetTaskByID(ctx context.Context, id uint) (Task, error) {
+	var task Task
+	err := preload(r.conn(ctx)).First(&task, id).Error
+	return task, err
+}
+
+// TaskAudience возвращает владельца и участников задачи, в том числе лежащей
+// в корзине, реализует streamService.TaskLookup
+func (r *taskRepository) TaskAudience(ctx context.Context, id uint) (uint, []uint, error) {
+	var task Task
+	if err := r.conn(ctx).Unscoped().Select("id", "user_id").First(&task, id).Error; err != nil {
+		return 0, nil, err
+	}
+	var members []uint
+	if err := r.conn(ctx).Model(&TaskAssignee{}).Where("task_id = ?", id).Pluck("user_id", &members).Error; err != nil {
+		return 0, nil, err
+	}
+	return task.UserID, members, nil
+}
+
+// SearchTasks ищет задачи по tsvector-колонке search_vector и возвращает
+// их по убыванию релевантности вместе с подсвеченным фрагментом текста
+func (r *taskRepository) SearchTasks(ctx context.Context, query string, filter TaskFilter, limit int) ([]SearchResult, error) {
+	tsquery := "plainto_tsquery('" + searchConfig + "', ?)"
+	headlineOptions := `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `", MaxFragments=2, MaxWords=30, MinWords=10`
+
+	var results []SearchResult
+	err := filter.apply(r.conn(ctx).Model(&Task{})).
+		Select("tasks.*, ts_rank(search_vector, "+tsquery+") AS rank, ts_headline('"+searchConfig+"', task, "+tsquery+", ?) AS snippet",
+			query, query, headlineOptions).
+		Where("search_vector @@ "+tsquery, query).
+		Order("rank DESC, id DESC").
+		Limit(limit).
+		Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		results[i].Snippet = highlight(results[i].Snippet)
+	}
+	return results, nil
+}
+
+func (r *taskRepository) UpdateTaskByID(ctx context.Context, id uint, task Task) (Task, error) {
 	var existing Task
-	if err := r.db.First(&existing, id).Error; err != nil {
-		log.Printf("Task with ID %d not found: %v", id, err)
+	if err := r.conn(ctx).First(&existing, id).Error; err != nil {
 		return Task{}, err
 	}
-	log.Printf("Updating task with ID %d: old task=%v, new task=%v", id, existing, task)
+	before := existing
+
+	// completed_at отмечает момент перехода в выполненные и сбрасывается при возврате в работу
+	if task.IsDone && !existing.IsDone {
+		now := time.Now()
+		existing.CompletedAt = &now
+	} else if !task.IsDone {
+		existing.CompletedAt = nil
+	}
 
 	existing.Task = task.Task
 	existing.IsDone = task.IsDone
 	existing.UserID = task.UserID
+	if task.DueAt != nil {
+		existing.DueAt = task.DueAt
+	}
+	if task.Priority != "" {
+		existing.Priority = task.Priority
+	}
+	// Родителя, проект, серию и статус сервис передает всегда, nil снимает привязку
+	existing.ParentID = task.ParentID
+	existing.StatusID = task.StatusID
+	existing.SeriesID = task.SeriesID
+	existing.Recurrence = task.Recurrence
+	moved := !sameID(existing.ProjectID, task.ProjectID)
+	if moved {
+		existing.ProjectID = task.ProjectID
+		existing.Position = 0
+	}
+
+	err := r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		if moved && task.ProjectID != nil {
+			position, err := nextPosition(tx, *task.ProjectID)
+			if err != nil {
+				return err
+			}
+			existing.Position = position
+		}
+		if err := tx.Save(&existing).Error; err != nil {
+			return err
+		}
+		return addEvents(tx, changeEvents(ctx, before, existing)...)
+	})
+	if err != nil {
+		return existing, err
+	}
+
+	return r.GetTaskByID(ctx, id)
+}
+
+// DeleteTaskByID удаляет задачу вместе со всеми ее подзадачами
+func (r *taskRepository) DeleteTaskByID(ctx context.Context, id uint) error {
+	return r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		ids, err := descendantIDs(tx, []uint{id})
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&Task{}, append(ids, id)).Error
+	})
+}
+
+// CountOpenSubtasks возвращает число невыполненных прямых подзадач
+func (r *taskRepository) CountOpenSubtasks(ctx context.Context, parentID uint) (int64, error) {
+	var count int64
+	err := r.conn(ctx).Model(&Task{}).Where("parent_id = ? AND is_done = ?", parentID, false).Count(&count).Error
+	return count, err
+}
+
+// CompleteSubtasks отмечает выполненными все невыполненные подзадачи на любой глубине
+// и переводит их в первый статус категории done на доске их владельца
+func (r *taskRepository) CompleteSubtasks(ctx context.Context, parentID uint) error {
+	return r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		ids, err := descendantIDs(tx, []uint{parentID})
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+		var open []uint
+		if err := tx.Model(&Task{}).Where("id IN ? AND is_done = ?", ids, false).Pluck("id", &open).Error; err != nil || len(open) == 0 {
+			return err
+		}
+		err = tx.Model(&Task{}).
+			Where("id IN ?", open).
+			Updates(map[string]any{
+				"is_done":      true,
+				"completed_at": time.Now(),
+				"status_id": gorm.Expr("(SELECT s.id FROM task_statuses s WHERE s.user_id = tasks.user_id AND s.workspace_id = tasks.workspace_id AND s.category = ? ORDER BY s.position, s.id LIMIT 1)",
+					CategoryDone),
+			}).Error
+		if err != nil {
+			return err
+		}
 
-	err := r.db.Save(&existing).Error
+		events := make([]TaskEvent, len(open))
+		for i, taskID := range open {
+			events[i] = newEvent(ctx, taskID, EventCompleted, "", "")
+		}
+		return addEvents(tx, events...)
+	})
+}
+
+// GetAncestorIDs возвращает ID задачи и всех ее предков до корня
+func (r *taskRepository) GetAncestorIDs(ctx context.Context, id uint) ([]uint, error) {
+	inWorkspace, err := tenant.Condition(ctx, "workspace_id")
 	if err != nil {
-		log.Printf("Error updating task with ID %d: %v", id, err)
-	} else {
-		log.Printf("Task with ID %d updated successfully: %v", id, existing)
+		return nil, err
+	}
+	var ids []uint
+	err = r.conn(ctx).Raw(`
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id FROM tasks WHERE id = ? AND deleted_at IS NULL AND ?
+			UNION
+			SELECT t.id, t.parent_id FROM tasks t JOIN ancestors a ON t.id = a.parent_id
+			WHERE t.deleted_at IS NULL AND ?
+		)
+		SELECT id FROM ancestors`, id, inWorkspace, inWorkspace).Scan(&ids).Error
+	return ids, err
+}
+
+// GetDescendants возвращает все подзадачи переданных задач на любой глубине
+// в порядке создания
+func (r *taskRepository) GetDescendants(ctx context.Context, rootIDs []uint) ([]Task, error) {
+	ids, err := descendantIDs(r.conn(ctx), rootIDs)
+	if err != nil || len(ids) == 0 {
+		return nil, err
 	}
 
-	return existing, err
+	var tasks []Task
+	err = preload(r.conn(ctx)).Where("id IN ?", ids).Order("created_at, id").Find(&tasks).Error
+	return tasks, err
+}
+
+// AddDependency сохраняет связь, повторное объявление ничего не меняет
+func (r *taskRepository) AddDependency(ctx context.Context, blockerID, blockedID uint) error {
+	dep := TaskDependency{BlockerID: blockerID, BlockedID: blockedID}
+	return r.conn(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&dep).Error
 }
 
-func (r *taskRepository) DeleteTaskByID(id uint) error {
-	return r.db.Delete(&Task{}, id).Error
+// RemoveDependency удаляет связь, а если ее не было — возвращает gorm.ErrRecordNotFound
+func (r *taskRepository) RemoveDependency(ctx context.Context, blockerID, blockedID uint) error {
+	result := r.conn(ctx).Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).Delete(&TaskDependency{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
 
-func (r *taskRepository) GetTasksByUserID(userID uint) ([]Task, error) {
+// GetBlockers возвращает задачи, которые блокируют задачу id
+func (r *taskRepository) GetBlockers(ctx context.Context, id uint) ([]Task, error) {
 	var tasks []Task
-	err := r.db.Where("user_id = ?", userID).Find(&tasks).Error
+	err := preload(r.conn(ctx)).Joins("JOIN task_dependencies d ON d.blocker_id = tasks.id").
+		Where("d.blocked_id = ?", id).Order("tasks.id").Find(&tasks).Error
+	return tasks, err
+}
+
+// GetDependents возвращает задачи, которые блокирует задача id
+func (r *taskRepository) GetDependents(ctx context.Context, id uint) ([]Task, error) {
+	var tasks []Task
+	err := preload(r.conn(ctx)).Joins("JOIN task_dependencies d ON d.blocked_id = tasks.id").
+		Where("d.blocker_id = ?", id).Order("tasks.id").Find(&tasks).Error
+	return tasks, err
+}
+
+// Blocks сообщает, блокирует ли blockerID задачу blockedID напрямую или через цепочку
+// Связи проходятся только по задачам пространства из контекста.
+func (r *taskRepository) Blocks(ctx context.Context, blockerID, blockedID uint) (bool, error) {
+	inWorkspace, err := tenant.Condition(ctx, "t.workspace_id")
+	if err != nil {
+		return false, err
+	}
+	var count int64
+	err = r.conn(ctx).Raw(`
+		WITH RECURSIVE downstream AS (
+			SELECT d.blocked_id FROM task_dependencies d JOIN tasks t ON t.id = d.blocked_id
+			WHERE d.blocker_id = ? AND ?
+			UNION
+			SELECT d.blocked_id FROM task_dependencies d JOIN downstream s ON d.blocker_id = s.blocked_id
+			JOIN tasks t ON t.id = d.blocked_id
+			WHERE ?
+		)
+		SELECT COUNT(*) FROM downstream WHERE blocked_id = ?`, blockerID, inWorkspace, inWorkspace, blockedID).Scan(&count).Error
+	return count > 0, err
+}
+
+// CountOpenBlockers возвращает число невыполненных задач, блокирующих задачу id
+func (r *taskRepository) CountOpenBlockers(ctx context.Context, id uint) (int64, error) {
+	var count int64
+	err := r.conn(ctx).Model(&Task{}).
+		Joins("JOIN task_dependencies d ON d.blocker_id = tasks.id").
+		Where("d.blocked_id = ? AND tasks.is_done = ?", id, false).
+		Count(&count).Error
+	return count, err
+}
+
+// CountExternalBlockers возвращает число невыполненных задач вне поддерева rootID,
+// которые блокируют его невыполненные подзадачи
+func (r *taskRepository) CountExternalBlockers(ctx context.Context, rootID uint) (int64, error) {
+	ids, err := descendantIDs(r.conn(ctx), []uint{rootID})
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	subtree := append(ids, rootID)
+
+	var count int64
+	err = r.conn(ctx).Model(&Task{}).
+		Joins("JOIN task_dependencies d ON d.blocker_id = tasks.id").
+		Joins("JOIN tasks blocked ON blocked.id = d.blocked_id").
+		Where("d.blocked_id IN ? AND d.blocker_id NOT IN ?", ids, subtree).
+		Where("tasks.is_done = ? AND blocked.is_done = ? AND blocked.deleted_at IS NULL", false, false).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *taskRepository) CreateTag(ctx context.Context, tag Tag) (Tag, error) {
+	err := r.conn(ctx).Create(&tag).Error
+	return tag, err
+}
+
+func (r *taskRepository) GetTagsByUserID(ctx context.Context, userID uint) ([]Tag, error) {
+	var tags []Tag
+	err := r.conn(ctx).Where("user_id = ?", userID).Order("name").Find(&tags).Error
+	return tags, err
+}
+
+func (r *taskRepository) GetTagByID(ctx context.Context, id uint) (Tag, error) {
+	var tag Tag
+	err := r.conn(ctx).First(&tag, id).Error
+	return tag, err
+}
+
+// DeleteTagByID удаляет метку и снимает ее со всех задач
+func (r *taskRepository) DeleteTagByID(ctx context.Context, id uint) error {
+	inWorkspace, err := tenant.Condition(ctx, "workspace_id")
+	if err != nil {
+		return err
+	}
+	return r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec("DELETE FROM task_tags WHERE tag_id IN (SELECT id FROM tags WHERE id = ? AND ?)", id, inWorkspace).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&Tag{}, id).Error
+	})
+}
+
+// AttachTag навешивает метку на задачу, повторный вызов ничего не меняет
+func (r *taskRepository) AttachTag(ctx context.Context, taskID, tagID uint) error {
+	return r.conn(ctx).Table("task_tags").Clauses(clause.OnConflict{DoNothing: true}).
+		Create(map[string]any{"task_id": taskID, "tag_id": tagID}).Error
+}
+
+// DetachTag снимает метку с задачи, а если ее не было — возвращает gorm.ErrRecordNotFound
+func (r *taskRepository) DetachTag(ctx context.Context, taskID, tagID uint) error {
+	inWorkspace, err := tenant.Condition(ctx, "workspace_id")
+	if err != nil {
+		return err
+	}
+	result := r.conn(ctx).Exec(`
+		DELETE FROM task_tags
+		WHERE task_id IN (SELECT id FROM tasks WHERE id = ? AND ?)
+			AND tag_id IN (SELECT id FROM tags WHERE id = ? AND ?)`, taskID, inWorkspace, tagID, inWorkspace)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *taskRepository) CreateSeries(ctx context.Context, series TaskSeries) (TaskSeries, error) {
+	err := r.conn(ctx).Create(&series).Error
+	return series, err
+}
+
+func (r *taskRepository) GetSeriesByID(ctx context.Context, id uint) (TaskSeries, error) {
+	var series TaskSeries
+	err := r.conn(ctx).First(&series, id).Error
+	return series, err
+}
+
+// UpdateSeries сохраняет шаблон серии и переносит его текст, приоритет и правило
+// на открытые вхождения не раньше from. Само вхождение from сервис обновляет отдельно.
+func (r *taskRepository) UpdateSeries(ctx context.Context, series TaskSeries, from Task) error {
+	return r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&series).Error; err != nil {
+			return err
+		}
+
+		db := tx.Model(&Task{}).Where("series_id = ? AND id <> ? AND is_done = ?", series.ID, from.ID, false)
+		if from.DueAt != nil {
+			db = db.Where("due_at IS NULL OR due_at >= ?", *from.DueAt)
+		}
+		return db.Updates(map[string]any{
+			"task":       series.Task,
+			"priority":   series.Priority,
+			"recurrence": series.Recurrence,
+		}).Error
+	})
+}
+
+// CountSeriesTasks считает все вхождения серии, включая удаленные, — для COUNT в правиле
+func (r *taskRepository) CountSeriesTasks(ctx context.Context, seriesID uint) (int64, error) {
+	var count int64
+	err := r.conn(ctx).Unscoped().Model(&Task{}).Where("series_id = ?", seriesID).Count(&count).Error
+	return count, err
+}
+
+// HasOccurrence сообщает, создано ли уже вхождение серии со сроком dueAt
+func (r *taskRepository) HasOccurrence(ctx context.Context, seriesID uint, dueAt time.Time) (bool, error) {
+	var count int64
+	err := r.conn(ctx).Model(&Task{}).Where("series_id = ? AND due_at = ?", seriesID, dueAt).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *taskRepository) GetStatusesByUserID(ctx context.Context, userID uint) ([]TaskStatus, error) {
+	var statuses []TaskStatus
+	err := r.conn(ctx).Preload("Transitions").Where("user_id = ?", userID).Order("position, id").Find(&statuses).Error
+	return statuses, err
+}
+
+func (r *taskRepository) GetStatusByID(ctx context.Context, id uint) (TaskStatus, error) {
+	var status TaskStatus
+	err := r.conn(ctx).Preload("Transitions").First(&status, id).Error
+	return status, err
+}
+
+// CreateDefaultStatuses заводит пользователю доску по умолчанию, если доски
+// у него еще нет. Проверка и вставка идут под блокировкой доски, так что
+// одновременные первые запросы создают одну доску.
+func (r *taskRepository) CreateDefaultStatuses(ctx context.Context, userID uint) ([]TaskStatus, error) {
+	err := r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockBoard(tx, userID); err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&TaskStatus{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+
+		statuses := make([]TaskStatus, 0, len(defaultStatuses))
+		for i, d := range defaultStatuses {
+			statuses = append(statuses, TaskStatus{UserID: userID, Name: d.Name, Category: d.Category, Position: i + 1})
+		}
+		if err := tx.Create(&statuses).Error; err != nil {
+			return err
+		}
+
+		for i, d := range defaultStatuses {
+			next := make([]TaskStatus, 0, len(d.Transitions))
+			for _, j := range d.Transitions {
+				next = append(next, statuses[j])
+			}
+			if err := tx.Model(&statuses[i]).Association("Transitions").Replace(next); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	return tasks, nil
+	return r.GetStatusesByUserID(ctx, userID)
+}
+
+// CreateStatus добавляет статус в конец доски пользователя
+func (r *taskRepository) CreateStatus(ctx context.Context, status TaskStatus, transitions []uint) (TaskStatus, error) {
+	err := r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		var last int
+		err := tx.Model(&TaskStatus{}).Where("user_id = ?", status.UserID).
+			Select("COALESCE(MAX(position), 0)").Scan(&last).Error
+		if err != nil {
+			return err
+		}
+		status.Position = last + 1
+
+		if err := tx.Omit("Transitions").Create(&status).Error; err != nil {
+			return err
+		}
+		return replaceTransitions(tx, status, transitions)
+	})
+	if err != nil {
+		return TaskStatus{}, err
+	}
+	return r.GetStatusByID(ctx, status.ID)
+}
+
+// UpdateStatus сохраняет название и категорию; transitions == nil оставляет переходы как есть
+func (r *taskRepository) UpdateStatus(ctx context.Context, status TaskStatus, transitions *[]uint) (TaskStatus, error) {
+	err := r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&status).Updates(map[string]any{"name": status.Name, "category": status.Category}).Error
+		if err != nil || transitions == nil {
+			return err
+		}
+		return replaceTransitions(tx, status, *transitions)
+	})
+	if err != nil {
+		return TaskStatus{}, err
+	}
+	return r.GetStatusByID(ctx, status.ID)
+}
+
+// DeleteStatusByID удаляет статус вместе с переходами в него и из него
+func (r *taskRepository) DeleteStatusByID(ctx context.Context, id uint) error {
+	inWorkspace, err := tenant.Condition(ctx, "workspace_id")
+	if err != nil {
+		return err
+	}
+	return r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`
+			DELETE FROM task_status_transitions
+			WHERE (from_id = ? OR to_id = ?) AND from_id IN (SELECT id FROM task_statuses WHERE ?)`, id, id, inWorkspace).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&TaskStatus{}, id).Error
+	})
+}
+
+// CountTasksWithStatus считает неудаленные задачи в статусе
+func (r *taskRepository) CountTasksWithStatus(ctx context.Context, id uint) (int64, error) {
+	var count int64
+	err := r.conn(ctx).Model(&Task{}).Where("status_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// SetStatusOrder ставит перечисленные статусы в начало доски, остальные идут за ними
+func (r *taskRepository) SetStatusOrder(ctx context.Context, userID uint, statusIDs []uint) error {
+	return r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		var current []uint
+		err := tx.Model(&TaskStatus{}).Where("user_id = ?", userID).
+			Order("position, id").Pluck("id", &current).Error
+		if err != nil {
+			return err
+		}
+
+		owned := make(map[uint]bool, len(current))
+		for _, id := range current {
+			owned[id] = true
+		}
+		placed := make(map[uint]bool, len(statusIDs))
+		for _, id := range statusIDs {
+			if !owned[id] || placed[id] {
+				return ErrInvalidStatusOrder
+			}
+			placed[id] = true
+		}
+
+		order := append([]uint{}, statusIDs...)
+		for _, id := range current {
+			if !placed[id] {
+				order = append(order, id)
+			}
+		}
+		for i, id := range order {
+			if err := tx.Model(&TaskStatus{}).Where("id = ?", id).UpdateColumn("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// replaceTransitions заменяет список разрешенных переходов из статуса
+func replaceTransitions(tx *gorm.DB, status TaskStatus, transitions []uint) error {
+	next := make([]TaskStatus, 0, len(transitions))
+	for _, id := range transitions {
+		next = append(next, TaskStatus{ID: id})
+	}
+	return tx.Model(&status).Association("Transitions").Replace(next)
+}
+
+// SetAssignee добавляет пользователя к задаче или меняет его роль
+func (r *taskRepository) SetAssignee(ctx context.Context, taskID, userID uint, role string) error {
+	return r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&TaskAssignee{}).Where("task_id = ? AND user_id = ?", taskID, userID).Update("role", role)
+		if result.Error != nil || result.RowsAffected > 0 {
+			return result.Error
+		}
+		return tx.Create(&TaskAssignee{TaskID: taskID, UserID: userID, Role: role}).Error
+	})
+}
+
+// RemoveAssignee убирает пользователя с роли role в задаче
+func (r *taskRepository) RemoveAssignee(ctx context.Context, taskID, userID uint, role string) error {
+	result := r.conn(ctx).Where("task_id = ? AND user_id = ? AND role = ?", taskID, userID, role).Delete(&TaskAssignee{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ReleaseUser снимает пользователя с задач рабочего пространства из контекста,
+// а при снятой изоляции — со всех задач. Если задан reassignTo, задачи,
+// где пользователь был исполнителем, переходят к reassignTo.
+func (r *taskRepository) ReleaseUser(ctx context.Context, userID uint, reassignTo *uint) error {
+	inWorkspace, err := tenant.Condition(ctx, "workspace_id")
+	if err != nil {
+		return err
+	}
+	return r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		if reassignTo != nil {
+			var taskIDs []uint
+			err := tx.Model(&Task{}).Joins("JOIN task_assignees a ON a.task_id = tasks.id").
+				Where("a.user_id = ? AND a.role = ?", userID, RoleAssignee).
+				Pluck("tasks.id", &taskIDs).Error
+			if err != nil {
+				return err
+			}
+			for _, taskID := range taskIDs {
+				result := tx.Model(&TaskAssignee{}).Where("task_id = ? AND user_id = ?", taskID, *reassignTo).
+					Update("role", RoleAssignee)
+				if result.Error != nil {
+					return result.Error
+				}
+				if result.RowsAffected == 0 {
+					if err := tx.Create(&TaskAssignee{TaskID: taskID, UserID: *reassignTo, Role: RoleAssignee}).Error; err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return tx.Where("user_id = ? AND task_id IN (SELECT id FROM tasks WHERE ?)", userID, inWorkspace).
+			Delete(&TaskAssignee{}).Error
+	})
+}
+
+// GetDeletedTasks возвращает страницу корзины. Подзадачи, удаленные вместе
+// с родителем, в нее не попадают: они восстанавливаются вместе с ним.
+func (r *taskRepository) GetDeletedTasks(ctx context.Context, filter TaskFilter, page pagination.Page) ([]Task, string, error) {
+	var tasks []Task
+	db := filter.apply(r.conn(ctx).Unscoped()).
+		Where("deleted_at IS NOT NULL").
+		Where("NOT EXISTS (SELECT 1 FROM tasks p WHERE p.id = tasks.parent_id AND p.deleted_at = tasks.deleted_at)")
+	if err := pagination.Apply(db, page).Find(&tasks).Error; err != nil {
+		return nil, "", err
+	}
+	tasks, next := pagination.Trim(tasks, page, func(t Task) pagination.Cursor {
+		return page.CursorFor(t.DeletedAt.Time, t.ID)
+	})
+	return tasks, next, nil
+}
+
+// GetDeletedTaskByID возвращает задачу из корзины
+func (r *taskRepository) GetDeletedTaskByID(ctx context.Context, id uint) (Task, error) {
+	var task Task
+	err := r.conn(ctx).Unscoped().Where("deleted_at IS NOT NULL").First(&task, id).Error
+	return task, err
+}
+
+// RestoreTask возвращает из корзины задачу и подзадачи, удаленные вместе с ней.
+// Связь с проектом, удаленным за это время, снимается, а задачи, чей статус
+// успел исчезнуть, получают первый подходящий статус доски владельца.
+func (r *taskRepository) RestoreTask(ctx context.Context, id uint) error {
+	return r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		var task Task
+		if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&task, id).Error; err != nil {
+			return err
+		}
+
+		inWorkspace, err := tenant.Condition(ctx, "workspace_id")
+		if err != nil {
+			return err
+		}
+		var ids []uint
+		err = tx.Raw(`
+			WITH RECURSIVE subtree AS (
+				SELECT id FROM tasks WHERE parent_id = ? AND deleted_at = ? AND ?
+				UNION
+				SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id
+				WHERE t.deleted_at = ? AND ?
+			)
+			SELECT id FROM subtree`, id, task.DeletedAt.Time, inWorkspace, task.DeletedAt.Time, inWorkspace).Scan(&ids).Error
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+
+		if err := tx.Unscoped().Model(&Task{}).Where("id IN ?", ids).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		err = tx.Model(&Task{}).
+			Where("id IN ? AND project_id IN (SELECT id FROM projects WHERE deleted_at IS NOT NULL)", ids).
+			Updates(map[string]any{"project_id": nil, "position": 0}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&Task{}).
+			Where("id IN ? AND status_id IS NULL", ids).
+			Update("status_id", gorm.Expr("(SELECT s.id FROM task_statuses s WHERE s.user_id = tasks.user_id AND s.workspace_id = tasks.workspace_id AND (s.category = ?) = tasks.is_done ORDER BY s.position, s.id LIMIT 1)",
+				CategoryDone)).Error
+	})
+}
+
+// PurgeTasks окончательно удаляет задачи, попавшие в корзину раньше before.
+// Комментарии, вложения и связи задач удаляются каскадом в базе.
+func (r *taskRepository) PurgeTasks(ctx context.Context, before time.Time) (int64, error) {
+	result := r.conn(ctx).Unscoped().Where("deleted_at < ?", before).Delete(&Task{})
+	return result.RowsAffected, result.Error
+}
+
+// GetTaskEvents возвращает страницу ленты активности задачи
+func (r *taskRepository) GetTaskEvents(ctx context.Context, taskID uint, page pagination.Page) ([]TaskEvent, string, error) {
+	var events []TaskEvent
+	if err := pagination.Apply(r.conn(ctx).Where("task_id = ?", taskID), page).Find(&events).Error; err != nil {
+		return nil, "", err
+	}
+	events, next := pagination.Trim(events, page, func(e TaskEvent) pagination.Cursor {
+		return page.CursorFor(e.CreatedAt, e.ID)
+	})
+	return events, next, nil
+}
+
+// preload подгружает связи, которые отдаются вместе с задачей
+func preload(db *gorm.DB) *gorm.DB {
+	return db.Preload("Tags").Preload("Status").Preload("Assignees", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at, user_id")
+	})
+}
+
+// nextPosition возвращает позицию в конце ручного порядка проекта. Строка
+// проекта блокируется до конца транзакции tx, так что одновременные вставки
+// в проект получают разные позиции.
+func nextPosition(tx *gorm.DB, projectID uint) (int, error) {
+	if err := LockProject(tx, projectID); err != nil {
+		return 0, err
+	}
+	var last int
+	err := tx.Model(&Task{}).Where("project_id = ?", projectID).
+		Select("COALESCE(MAX(position), 0)").Scan(&last).Error
+	return last + 1, err
+}
+
+// boardLockKey — пространство ключей advisory-блокировок Postgres для досок статусов
+const boardLockKey = 0x73746174
+
+// lockBoard ставит в очередь создание доски пользователя до конца транзакции tx.
+// В Postgres это advisory-блокировка; SQLite в тестах и так выполняет пишущие
+// транзакции по одной.
+func lockBoard(tx *gorm.DB, userID uint) error {
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+	return tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", boardLockKey, userID).Error
+}
+
+// LockProject блокирует строку проекта из пространства контекста tx до конца
+// транзакции. Под этой блокировкой меняется ручной порядок задач проекта.
+func LockProject(tx *gorm.DB, projectID uint) error {
+	workspaceID, err := tenant.Require(tx.Statement.Context)
+	if err != nil {
+		return err
+	}
+	var id uint
+	return tx.Table("projects").Select("id").Where("id = ? AND workspace_id = ?", projectID, workspaceID).
+		Clauses(clause.Locking{Strength: "UPDATE"}).Scan(&id).Error
+}
+
+// sameID сравнивает необязательные ID
+func sameID(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// descendantIDs обходит дерево подзадач рекурсивным запросом. UNION вместо
+// UNION ALL гарантирует остановку даже на испорченных данных с циклом.
+// Обход не выходит за пространство из контекста db.
+func descendantIDs(db *gorm.DB, rootIDs []uint) ([]uint, error) {
+	inWorkspace, err := tenant.Condition(db.Statement.Context, "workspace_id")
+	if err != nil {
+		return nil, err
+	}
+	var ids []uint
+	err = db.Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM tasks WHERE parent_id IN ? AND deleted_at IS NULL AND ?
+			UNION
+			SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id
+			WHERE t.deleted_at IS NULL AND ?
+		)
+		SELECT id FROM subtree`, rootIDs, inWorkspace, inWorkspace).Scan(&ids).Error
+	return ids, err
+}
+
+// sortValue возвращает значение поля задачи, по которому идет сортировка
+func sortValue(t Task, column string) any {
+	switch column {
+	case "updated_at":
+		return t.UpdatedAt
+	case "task":
+		return t.Task
+	case "is_done":
+		return t.IsDone
+	case "position":
+		return t.Position
+	default:
+		return t.CreatedAt
+	}
 }
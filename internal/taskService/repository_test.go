@@ -0,0 +1,254 @@
+package taskService
+
+import (
+	"context"
+	"errors"
+	"newproject/internal/tenant"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB открывает SQLite в файле с изоляцией пространств. Пишущие
+// транзакции начинаются с BEGIN IMMEDIATE и ждут друг друга. Проекты живут
+// в projectService, здесь от них нужна только таблица.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_txlock=immediate&_busy_timeout=5000"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.Use(tenant.Plugin{}); err != nil {
+		t.Fatalf("tenant plugin: %v", err)
+	}
+	if err := db.AutoMigrate(&TaskStatus{}, &Task{}, &TaskDependency{}, &TaskAssignee{}, &TaskEvent{}, &Tag{}, &TaskSeries{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := db.Exec("CREATE TABLE projects (id integer PRIMARY KEY, workspace_id integer NOT NULL, deleted_at datetime)").Error; err != nil {
+		t.Fatalf("create projects: %v", err)
+	}
+	return db
+}
+
+func TestConcurrentCreatesGetDistinctPositions(t *testing.T) {
+	db := openTestDB(t)
+	db.Exec("INSERT INTO projects (id, workspace_id) VALUES (1, 1)")
+	repo := NewTaskRepository(db)
+	ctx := tenant.WithWorkspace(context.Background(), 1)
+	project := uint(1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := repo.CreateTask(ctx, Task{Task: "t", UserID: 1, ProjectID: &project}); err != nil {
+				t.Errorf("create: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var positions []int
+	db.WithContext(ctx).Model(&Task{}).Order("position").Pluck("position", &positions)
+	if len(positions) != 10 {
+		t.Fatalf("tasks: got %d, want 10", len(positions))
+	}
+	for i, p := range positions {
+		if p != i+1 {
+			t.Fatalf("positions: got %v, want 1..10", positions)
+		}
+	}
+}
+
+func TestConcurrentFirstRequestsCreateOneBoard(t *testing.T) {
+	db := openTestDB(t)
+	service := NewTaskService(NewTaskRepository(db), noProjects{}, anyUser{}, CompletionBlock)
+	ctx := callerContext()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses, err := service.ensureStatuses(ctx, 1)
+			if err != nil {
+				t.Errorf("ensure statuses: %v", err)
+			} else if len(statuses) != len(defaultStatuses) {
+				t.Errorf("statuses: got %d, want %d", len(statuses), len(defaultStatuses))
+			}
+		}()
+	}
+	wg.Wait()
+
+	var count int64
+	db.WithContext(ctx).Model(&TaskStatus{}).Where("user_id = ?", 1).Count(&count)
+	if count != int64(len(defaultStatuses)) {
+		t.Errorf("stored statuses: got %d, want %d", count, len(defaultStatuses))
+	}
+}
+
+// foreignWorkspace создает в пространстве 2 задачу с подзадачей, связью
+// и меткой, к которым будут обращаться из пространства 1
+type foreignWorkspace struct {
+	parent, child Task
+	tag           Tag
+	statuses      []TaskStatus
+}
+
+func seedForeignWorkspace(t *testing.T, repo *taskRepository) foreignWorkspace {
+	t.Helper()
+	ctx := tenant.WithWorkspace(context.Background(), 2)
+	var f foreignWorkspace
+	var err error
+	if f.parent, err = repo.CreateTask(ctx, Task{Task: "parent", UserID: 1}); err != nil {
+		t.Fatalf("create parent: %v", err)
+	}
+	if f.child, err = repo.CreateTask(ctx, Task{Task: "child", UserID: 1, ParentID: &f.parent.ID}); err != nil {
+		t.Fatalf("create child: %v", err)
+	}
+	if err := repo.AddDependency(ctx, f.parent.ID, f.child.ID); err != nil {
+		t.Fatalf("add dependency: %v", err)
+	}
+	if f.tag, err = repo.CreateTag(ctx, Tag{UserID: 1, Name: "foreign"}); err != nil {
+		t.Fatalf("create tag: %v", err)
+	}
+	if err := repo.AttachTag(ctx, f.child.ID, f.tag.ID); err != nil {
+		t.Fatalf("attach tag: %v", err)
+	}
+	if f.statuses, err = repo.CreateDefaultStatuses(ctx, 1); err != nil {
+		t.Fatalf("create statuses: %v", err)
+	}
+	return f
+}
+
+func TestRawQueriesStayInWorkspace(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewTaskRepository(db)
+	foreign := seedForeignWorkspace(t, repo)
+	ctx := tenant.WithWorkspace(context.Background(), 1)
+
+	// Подзадача из пространства 1 под чужим родителем — испорченные данные,
+	// по которым обход дерева не должен уйти в пространство 2
+	own, err := repo.CreateTask(ctx, Task{Task: "own", UserID: 1, ParentID: &foreign.parent.ID})
+	if err != nil {
+		t.Fatalf("create own: %v", err)
+	}
+
+	ancestors, err := repo.GetAncestorIDs(ctx, own.ID)
+	if err != nil {
+		t.Fatalf("ancestors: %v", err)
+	}
+	if len(ancestors) != 1 || ancestors[0] != own.ID {
+		t.Errorf("ancestors: got %v, want [%d]", ancestors, own.ID)
+	}
+	if ids, err := repo.GetAncestorIDs(ctx, foreign.child.ID); err != nil || len(ids) != 0 {
+		t.Errorf("foreign ancestors: got %v, %v, want none", ids, err)
+	}
+
+	if ids, err := descendantIDs(db.WithContext(ctx), []uint{foreign.parent.ID}); err != nil || len(ids) != 1 || ids[0] != own.ID {
+		t.Errorf("descendants: got %v, %v, want [%d]", ids, err, own.ID)
+	}
+
+	if blocks, err := repo.Blocks(ctx, foreign.parent.ID, foreign.child.ID); err != nil || blocks {
+		t.Errorf("foreign blocks: got %v, %v, want false", blocks, err)
+	}
+
+	if err := repo.DetachTag(ctx, foreign.child.ID, foreign.tag.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("detach foreign tag: got %v, want %v", err, gorm.ErrRecordNotFound)
+	}
+	if err := repo.DeleteTagByID(ctx, foreign.tag.ID); err != nil {
+		t.Errorf("delete foreign tag: %v", err)
+	}
+	if err := repo.DeleteStatusByID(ctx, foreign.statuses[0].ID); err != nil {
+		t.Errorf("delete foreign status: %v", err)
+	}
+
+	// В пространстве 2 все осталось на месте
+	ctx2 := tenant.WithWorkspace(context.Background(), 2)
+	if blocks, err := repo.Blocks(ctx2, foreign.parent.ID, foreign.child.ID); err != nil || !blocks {
+		t.Errorf("blocks: got %v, %v, want true", blocks, err)
+	}
+	child, err := repo.GetTaskByID(ctx2, foreign.child.ID)
+	if err != nil {
+		t.Fatalf("get child: %v", err)
+	}
+	if len(child.Tags) != 1 {
+		t.Errorf("child tags: got %d, want 1", len(child.Tags))
+	}
+	statuses, err := repo.GetStatusesByUserID(ctx2, 1)
+	if err != nil {
+		t.Fatalf("get statuses: %v", err)
+	}
+	if len(statuses) != len(foreign.statuses) || len(statuses[0].Transitions) == 0 {
+		t.Errorf("statuses: got %d with %d transitions, want %d with transitions",
+			len(statuses), len(statuses[0].Transitions), len(foreign.statuses))
+	}
+}
+
+func TestRawQueriesRequireWorkspace(t *testing.T) {
+	repo := NewTaskRepository(openTestDB(t))
+	ctx := context.Background()
+
+	checks := map[string]error{
+		"ancestors":         func() error { _, err := repo.GetAncestorIDs(ctx, 1); return err }(),
+		"blocks":            func() error { _, err := repo.Blocks(ctx, 1, 2); return err }(),
+		"delete tag":        repo.DeleteTagByID(ctx, 1),
+		"detach tag":        repo.DetachTag(ctx, 1, 1),
+		"delete status":     repo.DeleteStatusByID(ctx, 1),
+		"complete subtasks": repo.CompleteSubtasks(ctx, 1),
+	}
+	for name, err := range checks {
+		if !errors.Is(err, tenant.ErrNoWorkspace) {
+			t.Errorf("%s: got %v, want %v", name, err, tenant.ErrNoWorkspace)
+		}
+	}
+}
+
+func TestReleaseUserStaysInWorkspace(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewTaskRepository(db)
+	first := tenant.WithWorkspace(context.Background(), 1)
+	second := tenant.WithWorkspace(context.Background(), 2)
+
+	// Пользователь 2 — исполнитель задач в обоих пространствах
+	tasks := map[uint]Task{}
+	for _, ctx := range []context.Context{first, second} {
+		task, err := repo.CreateTask(ctx, Task{Task: "t", UserID: 1})
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		if err := repo.SetAssignee(ctx, task.ID, 2, RoleAssignee); err != nil {
+			t.Fatalf("assign: %v", err)
+		}
+		tasks[task.WorkspaceID] = task
+	}
+
+	reassignTo := uint(3)
+	if err := repo.ReleaseUser(first, 2, &reassignTo); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+
+	tests := []struct {
+		workspace uint
+		user      uint
+		want      int64
+	}{
+		{workspace: 1, user: 2, want: 0},
+		{workspace: 1, user: 3, want: 1},
+		{workspace: 2, user: 2, want: 1},
+		{workspace: 2, user: 3, want: 0},
+	}
+	for _, tt := range tests {
+		var count int64
+		db.Model(&TaskAssignee{}).Where("task_id = ? AND user_id = ?", tasks[tt.workspace].ID, tt.user).Count(&count)
+		if count != tt.want {
+			t.Errorf("workspace %d, user %d: got %d assignments, want %d", tt.workspace, tt.user, count, tt.want)
+		}
+	}
+}
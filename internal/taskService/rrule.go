@@ -0,0 +1,411 @@
+package taskService
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidRecurrence — правило повторения не разобрано или не поддерживается
+var ErrInvalidRecurrence = errors.New("invalid recurrence rule")
+
+// Частота повторения из RFC 5545
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+	FreqYearly  = "YEARLY"
+)
+
+// maxPeriods ограничивает перебор периодов, чтобы правило без вхождений
+// (например, 30 февраля) не зациклило поиск
+const maxPeriods = 10000
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// WeekdayNum — элемент BYDAY: день недели и необязательный номер в месяце
+// (2MO — второй понедельник, -1FR — последняя пятница, 0 — каждый)
+type WeekdayNum struct {
+	N   int
+	Day time.Weekday
+}
+
+// Rule — разобранное правило RRULE. Поддерживается подмножество RFC 5545:
+// FREQ, INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY, BYMONTH и WKST=MO.
+// Время вхождений берется из даты начала, недели начинаются с понедельника.
+type Rule struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      *time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+}
+
+// ParseRule разбирает строку вида "FREQ=WEEKLY;BYDAY=MO,WE". Префикс "RRULE:" допускается.
+func ParseRule(s string) (Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	rule := Rule{Interval: 1}
+	seen := map[string]bool{}
+
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		key = strings.ToUpper(strings.TrimSpace(key))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !ok || value == "" {
+			return Rule{}, fmt.Errorf("%w: malformed part %q", ErrInvalidRecurrence, part)
+		}
+		if seen[key] {
+			return Rule{}, fmt.Errorf("%w: %s is repeated", ErrInvalidRecurrence, key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			switch value {
+			case FreqDaily, FreqWeekly, FreqMonthly, FreqYearly:
+				rule.Freq = value
+			default:
+				err = fmt.Errorf("unsupported FREQ %s", value)
+			}
+		case "INTERVAL":
+			rule.Interval, err = parseRange(value, 1, 1000)
+		case "COUNT":
+			rule.Count, err = parseRange(value, 1, 10000)
+		case "UNTIL":
+			var until time.Time
+			until, err = parseUntil(value)
+			rule.Until = &until
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseList(value, -31, 31)
+		case "BYMONTH":
+			var months []int
+			months, err = parseList(value, 1, 12)
+			for _, m := range months {
+				rule.ByMonth = append(rule.ByMonth, time.Month(m))
+			}
+		case "WKST":
+			if value != "MO" {
+				err = errors.New("only WKST=MO is supported")
+			}
+		default:
+			err = fmt.Errorf("unsupported part %s", key)
+		}
+		if err != nil {
+			return Rule{}, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+		}
+	}
+
+	if err := rule.validate(); err != nil {
+		return Rule{}, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+	return rule, nil
+}
+
+// validate проверяет сочетания частей, которые не поддерживаются
+func (r Rule) validate() error {
+	if r.Freq == "" {
+		return errors.New("FREQ is required")
+	}
+	if r.Count > 0 && r.Until != nil {
+		return errors.New("COUNT and UNTIL cannot be used together")
+	}
+	if r.Freq == FreqWeekly && len(r.ByMonthDay) > 0 {
+		return errors.New("BYMONTHDAY cannot be used with FREQ=WEEKLY")
+	}
+	for _, d := range r.ByDay {
+		if d.N == 0 {
+			continue
+		}
+		if r.Freq != FreqMonthly && r.Freq != FreqYearly {
+			return errors.New("numbered BYDAY requires FREQ=MONTHLY or FREQ=YEARLY")
+		}
+		if r.Freq == FreqYearly && len(r.ByMonth) == 0 {
+			return errors.New("numbered BYDAY with FREQ=YEARLY requires BYMONTH")
+		}
+	}
+	return nil
+}
+
+// String возвращает правило в каноническом виде
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, d := range r.ByDay {
+			code := weekdayCode(d.Day)
+			if d.N != 0 {
+				code = strconv.Itoa(d.N) + code
+			}
+			days = append(days, code)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinInts(r.ByMonthDay))
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]int, 0, len(r.ByMonth))
+		for _, m := range r.ByMonth {
+			months = append(months, int(m))
+		}
+		parts = append(parts, "BYMONTH="+joinInts(months))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next возвращает первое вхождение правила с началом start, которое позже after.
+// false означает, что вхождений больше нет.
+func (r Rule) Next(start, after time.Time) (time.Time, bool) {
+	for k := 0; k < maxPeriods; k++ {
+		for _, t := range r.period(start, k) {
+			if t.Before(start) || !t.After(after) {
+				continue
+			}
+			if r.Until != nil && t.After(*r.Until) {
+				return time.Time{}, false
+			}
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// period возвращает вхождения k-го периода правила по возрастанию
+func (r Rule) period(start time.Time, k int) []time.Time {
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+	}
+	var out []time.Time
+
+	switch r.Freq {
+	case FreqDaily:
+		t := at(start.Year(), start.Month(), start.Day()+k*r.Interval)
+		if r.matchesDay(t) {
+			out = append(out, t)
+		}
+	case FreqWeekly:
+		monday := start.Day() - (int(start.Weekday())+6)%7 + k*r.Interval*7
+		days := []time.Weekday{start.Weekday()}
+		if len(r.ByDay) > 0 {
+			days = days[:0]
+			for _, d := range r.ByDay {
+				days = append(days, d.Day)
+			}
+		}
+		for _, wd := range days {
+			t := at(start.Year(), start.Month(), monday+(int(wd)+6)%7)
+			if r.matchesMonth(t.Month()) {
+				out = append(out, t)
+			}
+		}
+	case FreqMonthly:
+		first := at(start.Year(), start.Month()+time.Month(k*r.Interval), 1)
+		if r.matchesMonth(first.Month()) {
+			for _, d := range r.monthDays(first.Year(), first.Month(), start.Day()) {
+				out = append(out, at(first.Year(), first.Month(), d))
+			}
+		}
+	case FreqYearly:
+		year := start.Year() + k*r.Interval
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{start.Month()}
+			if len(r.ByDay) > 0 || len(r.ByMonthDay) > 0 {
+				months = []time.Month{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+			}
+		}
+		for _, m := range months {
+			for _, d := range r.monthDays(year, m, start.Day()) {
+				out = append(out, at(year, m, d))
+			}
+		}
+	}
+
+	slices.SortFunc(out, func(a, b time.Time) int { return a.Compare(b) })
+	return slices.CompactFunc(out, func(a, b time.Time) bool { return a.Equal(b) })
+}
+
+// monthDays возвращает дни месяца, подходящие под BYMONTHDAY и BYDAY.
+// Без них вхождение приходится на день даты начала, если он есть в месяце.
+func (r Rule) monthDays(year int, month time.Month, startDay int) []int {
+	last := daysIn(year, month)
+
+	var byMonthDay []int
+	for _, d := range r.ByMonthDay {
+		if d < 0 {
+			d = last + d + 1
+		}
+		if d >= 1 && d <= last {
+			byMonthDay = append(byMonthDay, d)
+		}
+	}
+
+	var byDay []int
+	for _, wd := range r.ByDay {
+		var matches []int
+		for d := 1; d <= last; d++ {
+			if time.Date(year, month, d, 0, 0, 0, 0, time.UTC).Weekday() == wd.Day {
+				matches = append(matches, d)
+			}
+		}
+		switch {
+		case wd.N == 0:
+			byDay = append(byDay, matches...)
+		case wd.N > 0 && wd.N <= len(matches):
+			byDay = append(byDay, matches[wd.N-1])
+		case wd.N < 0 && -wd.N <= len(matches):
+			byDay = append(byDay, matches[len(matches)+wd.N])
+		}
+	}
+
+	var days []int
+	switch {
+	case len(r.ByMonthDay) > 0 && len(r.ByDay) > 0:
+		for _, d := range byMonthDay {
+			if slices.Contains(byDay, d) {
+				days = append(days, d)
+			}
+		}
+	case len(r.ByMonthDay) > 0:
+		days = byMonthDay
+	case len(r.ByDay) > 0:
+		days = byDay
+	case startDay <= last:
+		days = []int{startDay}
+	}
+
+	slices.Sort(days)
+	return slices.Compact(days)
+}
+
+// matchesDay применяет BYMONTH, BYMONTHDAY и BYDAY как фильтры ежедневного правила
+func (r Rule) matchesDay(t time.Time) bool {
+	if !r.matchesMonth(t.Month()) {
+		return false
+	}
+	if len(r.ByMonthDay) > 0 {
+		last := daysIn(t.Year(), t.Month())
+		ok := false
+		for _, d := range r.ByMonthDay {
+			if d == t.Day() || d < 0 && last+d+1 == t.Day() {
+				ok = true
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	if len(r.ByDay) > 0 {
+		for _, d := range r.ByDay {
+			if d.Day == t.Weekday() {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (r Rule) matchesMonth(m time.Month) bool {
+	return len(r.ByMonth) == 0 || slices.Contains(r.ByMonth, m)
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func parseRange(s string, lo, hi int) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < lo || n > hi {
+		return 0, fmt.Errorf("value %q must be between %d and %d", s, lo, hi)
+	}
+	return n, nil
+}
+
+// parseList разбирает список чисел через запятую; ноль не допускается
+func parseList(s string, lo, hi int) ([]int, error) {
+	var out []int
+	for _, item := range strings.Split(s, ",") {
+		n, err := parseRange(item, lo, hi)
+		if err != nil || n == 0 {
+			return nil, fmt.Errorf("invalid list value %q", item)
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+func parseByDay(s string) ([]WeekdayNum, error) {
+	var out []WeekdayNum
+	for _, item := range strings.Split(s, ",") {
+		if len(item) < 2 {
+			return nil, fmt.Errorf("invalid BYDAY value %q", item)
+		}
+		day, ok := weekdayCodes[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid BYDAY value %q", item)
+		}
+		wd := WeekdayNum{Day: day}
+		if prefix := item[:len(item)-2]; prefix != "" {
+			n, err := strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, fmt.Errorf("invalid BYDAY value %q", item)
+			}
+			wd.N = n
+		}
+		out = append(out, wd)
+	}
+	return out, nil
+}
+
+// parseUntil принимает дату-время в UTC, плавающее время (считается UTC)
+// или дату, которая включается целиком
+func parseUntil(s string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	t, err := time.Parse("20060102", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid UNTIL %q", s)
+	}
+	return t.Add(24*time.Hour - time.Second), nil
+}
+
+func weekdayCode(day time.Weekday) string {
+	for code, d := range weekdayCodes {
+		if d == day {
+			return code
+		}
+	}
+	return ""
+}
+
+func joinInts(values []int) string {
+	s := make([]string, 0, len(values))
+	for _, v := range values {
+		s = append(s, strconv.Itoa(v))
+	}
+	return strings.Join(s, ",")
+}
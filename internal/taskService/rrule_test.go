@@ -0,0 +1,199 @@
+package taskService
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "RRULE:freq=weekly;byday=mo,we", want: "FREQ=WEEKLY;BYDAY=MO,WE"},
+		{in: "FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR", want: "FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR"},
+		{in: "FREQ=DAILY;COUNT=5", want: "FREQ=DAILY;COUNT=5"},
+		{in: "FREQ=DAILY;UNTIL=20240105", want: "FREQ=DAILY;UNTIL=20240105T235959Z"},
+		{in: "FREQ=DAILY;UNTIL=20240105T120000Z", want: "FREQ=DAILY;UNTIL=20240105T120000Z"},
+		{in: "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=-1", want: "FREQ=YEARLY;BYMONTHDAY=-1;BYMONTH=2"},
+		{in: "", wantErr: true},
+		{in: "INTERVAL=2", wantErr: true},
+		{in: "FREQ=HOURLY", wantErr: true},
+		{in: "FREQ=DAILY;COUNT=3;UNTIL=20240105", wantErr: true},
+		{in: "FREQ=DAILY;COUNT=0", wantErr: true},
+		{in: "FREQ=DAILY;FREQ=WEEKLY", wantErr: true},
+		{in: "FREQ=WEEKLY;BYDAY=2MO", wantErr: true},
+		{in: "FREQ=YEARLY;BYDAY=1MO", wantErr: true},
+		{in: "FREQ=MONTHLY;BYDAY=6MO", wantErr: true},
+		{in: "FREQ=MONTHLY;BYMONTHDAY=0", wantErr: true},
+		{in: "FREQ=WEEKLY;BYMONTHDAY=1", wantErr: true},
+		{in: "FREQ=WEEKLY;WKST=SU", wantErr: true},
+	}
+	for _, tt := range tests {
+		rule, err := ParseRule(tt.in)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidRecurrence) {
+				t.Errorf("ParseRule(%q): got %v, want %v", tt.in, err, ErrInvalidRecurrence)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseRule(%q): %v", tt.in, err)
+			continue
+		}
+		if got := rule.String(); got != tt.want {
+			t.Errorf("ParseRule(%q): got %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRuleNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	utc := func(s string) time.Time {
+		t, _ := time.Parse("2006-01-02 15:04", s)
+		return t
+	}
+	local := func(s string) time.Time {
+		t, _ := time.ParseInLocation("2006-01-02 15:04", s, berlin)
+		return t
+	}
+
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		want  []time.Time
+		// end — после want вхождений больше нет
+		end bool
+	}{
+		{
+			name:  "weekly by day",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+			start: utc("2024-01-03 09:00"), // среда
+			want:  []time.Time{utc("2024-01-05 09:00"), utc("2024-01-08 09:00"), utc("2024-01-10 09:00"), utc("2024-01-12 09:00")},
+		},
+		{
+			name:  "every other week",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU",
+			start: utc("2024-01-02 09:00"),
+			want:  []time.Time{utc("2024-01-16 09:00"), utc("2024-01-30 09:00")},
+		},
+		{
+			name:  "last friday of the month",
+			rule:  "FREQ=MONTHLY;BYDAY=-1FR",
+			start: utc("2024-01-26 09:00"),
+			want:  []time.Time{utc("2024-02-23 09:00"), utc("2024-03-29 09:00"), utc("2024-04-26 09:00")},
+		},
+		{
+			name:  "second monday",
+			rule:  "FREQ=MONTHLY;BYDAY=2MO",
+			start: utc("2024-01-08 09:00"),
+			want:  []time.Time{utc("2024-02-12 09:00"), utc("2024-03-11 09:00")},
+		},
+		{
+			name:  "weekdays daily",
+			rule:  "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR",
+			start: utc("2024-01-05 09:00"), // пятница
+			want:  []time.Time{utc("2024-01-08 09:00"), utc("2024-01-09 09:00")},
+		},
+		{
+			name:  "month end skips short months",
+			rule:  "FREQ=MONTHLY",
+			start: utc("2024-01-31 09:00"),
+			want:  []time.Time{utc("2024-03-31 09:00"), utc("2024-05-31 09:00"), utc("2024-07-31 09:00")},
+		},
+		{
+			name:  "last day of every month",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start: utc("2024-01-31 09:00"),
+			want:  []time.Time{utc("2024-02-29 09:00"), utc("2024-03-31 09:00"), utc("2024-04-30 09:00")},
+		},
+		{
+			name:  "leap day",
+			rule:  "FREQ=YEARLY",
+			start: utc("2024-02-29 09:00"),
+			want:  []time.Time{utc("2028-02-29 09:00"), utc("2032-02-29 09:00")},
+		},
+		{
+			name:  "until date is inclusive",
+			rule:  "FREQ=DAILY;UNTIL=20240105",
+			start: utc("2024-01-03 09:00"),
+			want:  []time.Time{utc("2024-01-04 09:00"), utc("2024-01-05 09:00")},
+			end:   true,
+		},
+		{
+			name:  "until time",
+			rule:  "FREQ=DAILY;UNTIL=20240105T085959Z",
+			start: utc("2024-01-03 09:00"),
+			want:  []time.Time{utc("2024-01-04 09:00")},
+			end:   true,
+		},
+		{
+			// COUNT считает вхождения серии, Next его не ограничивает
+			name:  "count does not stop next",
+			rule:  "FREQ=DAILY;COUNT=2",
+			start: utc("2024-01-03 09:00"),
+			want:  []time.Time{utc("2024-01-04 09:00"), utc("2024-01-05 09:00"), utc("2024-01-06 09:00")},
+		},
+		{
+			name:  "impossible date",
+			rule:  "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30",
+			start: utc("2024-01-01 09:00"),
+			want:  nil,
+			end:   true,
+		},
+		{
+			name:  "wall clock kept across DST start",
+			rule:  "FREQ=DAILY",
+			start: local("2024-03-30 09:00"),
+			want:  []time.Time{local("2024-03-31 09:00"), local("2024-04-01 09:00")},
+		},
+		{
+			name:  "wall clock kept across DST end",
+			rule:  "FREQ=WEEKLY",
+			start: local("2024-10-21 09:00"),
+			want:  []time.Time{local("2024-10-28 09:00"), local("2024-11-04 09:00")},
+		},
+		{
+			// 02:30 31 марта в Берлине нет, вхождение сдвигается на час вперед
+			name:  "time in the DST gap",
+			rule:  "FREQ=DAILY",
+			start: local("2024-03-30 02:30"),
+			want:  []time.Time{local("2024-03-31 03:30"), local("2024-04-01 02:30")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRule(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseRule(%q): %v", tt.rule, err)
+			}
+			var got []time.Time
+			after := tt.start
+			for len(got) < len(tt.want)+1 {
+				next, ok := rule.Next(tt.start, after)
+				if !ok {
+					break
+				}
+				got = append(got, next)
+				after = next
+			}
+			if tt.end && len(got) > len(tt.want) {
+				t.Errorf("occurrence %d: got %s, want none", len(tt.want), got[len(tt.want)])
+			}
+			for i, want := range tt.want {
+				if i >= len(got) {
+					t.Fatalf("occurrence %d: got none, want %s", i, want)
+				}
+				if !got[i].Equal(want) {
+					t.Errorf("occurrence %d: got %s, want %s", i, got[i], want)
+				}
+			}
+		})
+	}
+}
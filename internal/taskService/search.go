@@ -0,0 +1,32 @@
+package taskService
+
+import (
+	"html"
+	"strings"
+)
+
+const (
+	// searchConfig — конфигурация текстового поиска, та же, что в миграции search_vector
+	searchConfig = "simple"
+
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+
+	// Маркеры подсветки из ts_headline. Текст задачи сначала экранируется,
+	// и только потом маркеры заменяются на <mark>, поэтому сниппет — безопасный HTML.
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+// SearchResult — задача, найденная полнотекстовым поиском
+type SearchResult struct {
+	Task
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+// highlight превращает сниппет из ts_headline в HTML с тегами <mark>
+func highlight(snippet string) string {
+	escaped := html.EscapeString(snippet)
+	return strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>").Replace(escaped)
+}
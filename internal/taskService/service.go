@@ -1,41 +1,693 @@
 package taskService
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"newproject/internal/identity"
+	"newproject/internal/pagination"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrTaskNotFound возвращается и для чужих задач, чтобы не раскрывать их существование
+	ErrTaskNotFound = fmt.Errorf("task not found: %w", gorm.ErrRecordNotFound)
+	// ErrForbidden — у вызывающего нет прав на операцию, например назначить
+	// задачу другому пользователю
+	ErrForbidden = errors.New("not allowed to access other users' tasks")
+	// ErrInvalidSearch — пустой поисковый запрос или неверный лимит
+	ErrInvalidSearch = errors.New("search query is required and limit must be between 1 and 100")
+	// ErrInvalidPriority — приоритет не из списка low/normal/high/urgent
+	ErrInvalidPriority = errors.New("priority must be one of low, normal, high, urgent")
+	// ErrInvalidParent — родительская задача не найдена или принадлежит другому пользователю
+	ErrInvalidParent = errors.New("parent task not found or owned by another user")
+	// ErrTaskCycle — задачу нельзя сделать подзадачей ее же потомка
+	ErrTaskCycle = errors.New("task cannot be moved under itself or its subtask")
+	// ErrOpenSubtasks — задачу нельзя закрыть, пока открыты ее подзадачи
+	ErrOpenSubtasks = errors.New("task has open subtasks")
+	// ErrInvalidProject — проект не найден или принадлежит не владельцу задачи
+	ErrInvalidProject = errors.New("project not found or owned by another user")
+	// ErrProjectArchived — в архивный проект нельзя добавлять задачи
+	ErrProjectArchived = errors.New("project is archived")
+	// ErrDependencyCycle — связь замкнула бы цепочку блокировок в цикл
+	ErrDependencyCycle = errors.New("dependency would create a cycle")
+	// ErrDependencyNotFound — удаляемой связи между задачами нет
+	ErrDependencyNotFound = fmt.Errorf("dependency not found: %w", gorm.ErrRecordNotFound)
+	// ErrTagNotFound возвращается и для чужих меток
+	ErrTagNotFound = fmt.Errorf("tag not found: %w", gorm.ErrRecordNotFound)
+	// ErrTagNotAttached — снимаемой метки на задаче нет
+	ErrTagNotAttached = fmt.Errorf("tag is not attached to the task: %w", gorm.ErrRecordNotFound)
+	// ErrInvalidTagName — пустое или слишком длинное название метки
+	ErrInvalidTagName = errors.New("tag name must be 1 to 64 characters")
+	// ErrInvalidTag — метка принадлежит не владельцу задачи
+	ErrInvalidTag = errors.New("tag must belong to the task owner")
+	// ErrTagExists — у пользователя уже есть метка с таким названием
+	ErrTagExists = errors.New("tag with this name already exists")
+	// ErrBlocked — задачу нельзя закрыть, пока не выполнены блокирующие ее задачи
+	ErrBlocked = errors.New("task is blocked by open tasks")
 )
 
+// ProjectLookup — то, что TaskService нужно знать о проектах, чтобы класть в них задачи
+type ProjectLookup interface {
+	// ProjectOwner возвращает владельца проекта и признак архивации
+	// или gorm.ErrRecordNotFound, если проекта нет
+	ProjectOwner(ctx context.Context, id uint) (ownerID uint, archived bool, err error)
+}
+
 type TaskService struct {
-	repo TaskRepository
+	repo       TaskRepository
+	projects   ProjectLookup
+	users      UserLookup
+	completion CompletionMode
 }
 
-func NewTaskService(repo TaskRepository) *TaskService {
-	return &TaskService{repo: repo}
+func NewTaskService(repo TaskRepository, projects ProjectLookup, users UserLookup, completion CompletionMode) *TaskService {
+	return &TaskService{repo: repo, projects: projects, users: users, completion: completion}
 }
 
-// CreateTask создает задачу
-func (s *TaskService) CreateTask(task Task) (Task, error) {
+// CreateTask создает задачу вызывающего. Если user_id не указан,
+// владельцем становится вызывающий.
+func (s *TaskService) CreateTask(ctx context.Context, task Task) (Task, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return Task{}, err
+	}
+
 	if task.UserID == 0 {
-		return Task{}, fmt.Errorf("user_id is required")
+		task.UserID = caller.UserID
+	}
+	if !canAssign(caller, task.UserID) {
+		return Task{}, ErrForbidden
+	}
+	if task.UserID != caller.UserID {
+		if err := s.checkUser(ctx, task.UserID); err != nil {
+			return Task{}, err
+		}
+	}
+	task.ParentID = nil
+	return s.create(ctx, task)
+}
+
+// CreateSubtask создает подзадачу. Ее владельцем всегда становится владелец родителя.
+func (s *TaskService) CreateSubtask(ctx context.Context, parentID uint, task Task) (Task, error) {
+	parent, err := s.editableTask(ctx, parentID)
+	if err != nil {
+		return Task{}, err
+	}
+
+	if task.UserID != 0 && task.UserID != parent.UserID {
+		return Task{}, ErrInvalidParent
+	}
+	task.UserID = parent.UserID
+	task.ParentID = &parent.ID
+	// Подзадача живет в проекте родителя
+	task.ProjectID = parent.ProjectID
+	return s.create(ctx, task)
+}
+
+// create проверяет поля новой задачи и сохраняет ее
+func (s *TaskService) create(ctx context.Context, task Task) (Task, error) {
+	if task.ProjectID != nil && *task.ProjectID == 0 {
+		task.ProjectID = nil
+	}
+	if task.ProjectID != nil {
+		if err := s.checkProject(ctx, *task.ProjectID, task.UserID); err != nil {
+			return Task{}, err
+		}
+	}
+
+	if task.Priority == "" {
+		task.Priority = PriorityNormal
+	}
+	if !validPriority(task.Priority) {
+		return Task{}, ErrInvalidPriority
+	}
+
+	status, err := s.resolveStatus(ctx, nil, task)
+	if err != nil {
+		return Task{}, err
+	}
+	task.StatusID, task.IsDone = &status.ID, status.Category == CategoryDone
+
+	task.CompletedAt = nil
+	if task.IsDone {
+		now := time.Now()
+		task.CompletedAt = &now
+	}
+
+	task.Recurrence, err = normalizeRecurrence(task.Recurrence)
+	if err != nil {
+		return Task{}, err
+	}
+	if task.SeriesID == nil && task.Recurrence != "" {
+		if task.SeriesID, err = s.startSeries(ctx, task); err != nil {
+			return Task{}, err
+		}
+	}
+
+	created, err := s.repo.CreateTask(ctx, task)
+	if err != nil {
+		return Task{}, err
+	}
+	created.Status = &status
+	return created, nil
+}
+
+// GetTasks возвращает страницу задач вызывающего, подходящих под фильтр,
+// и курсор следующей страницы. Фильтр по чужому user_id дает пустой список.
+func (s *TaskService) GetTasks(ctx context.Context, filter TaskFilter, page pagination.Page) ([]Task, string, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if filter.UserID != nil && *filter.UserID != caller.UserID {
+		return []Task{}, "", nil
+	}
+	filter.UserID = &caller.UserID
+	return s.repo.GetTasks(ctx, filter, page)
+}
+
+// GetAllTasks возвращает страницу задач всех пользователей, подходящих под фильтр.
+// Доступно только администраторам.
+func (s *TaskService) GetAllTasks(ctx context.Context, filter TaskFilter, page pagination.Page) ([]Task, string, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !caller.IsAdmin() {
+		return nil, "", ErrForbidden
+	}
+	return s.repo.GetTasks(ctx, filter, page)
+}
+
+// SearchTasks ищет задачи по тексту и возвращает их по убыванию релевантности.
+// Администратор ищет по всем задачам, остальные — только по своим.
+func (s *TaskService) SearchTasks(ctx context.Context, query string, limit int) ([]SearchResult, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query = strings.TrimSpace(query)
+	if query == "" || limit < 1 || limit > MaxSearchLimit {
+		return nil, ErrInvalidSearch
+	}
+
+	var filter TaskFilter
+	if !caller.IsAdmin() {
+		filter.UserID = &caller.UserID
+	}
+	return s.repo.SearchTasks(ctx, query, filter, limit)
+}
+
+// GetSubtasks возвращает страницу прямых подзадач задачи
+func (s *TaskService) GetSubtasks(ctx context.Context, parentID uint, page pagination.Page) ([]Task, string, error) {
+	if _, err := s.GetTaskByID(ctx, parentID); err != nil {
+		return nil, "", err
+	}
+	return s.repo.GetTasks(ctx, TaskFilter{ParentID: &parentID}, page)
+}
+
+// GetTaskByID возвращает задачу по ID, если она видна вызывающему
+func (s *TaskService) GetTaskByID(ctx context.Context, id uint) (Task, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return Task{}, err
+	}
+
+	task, err := s.repo.GetTaskByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Task{}, ErrTaskNotFound
+	} else if err != nil {
+		return Task{}, err
+	}
+
+	if !canAccess(caller, task) {
+		return Task{}, ErrTaskNotFound
+	}
+	return task, nil
+}
+
+// UpdateTaskByID обновляет задачу по ID. Нулевой user_id оставляет владельца прежним.
+// Правка повторяющейся задачи касается только этого вхождения.
+// Без status_id статус следует за is_done: берется первый статус, в который
+// разрешен переход из текущего.
+func (s *TaskService) UpdateTaskByID(ctx context.Context, id uint, task Task) (Task, error) {
+	return s.UpdateTask(ctx, id, task, SeriesEdit{Scope: ScopeThis})
+}
+
+// UpdateTask обновляет задачу и, если edit.Scope == ScopeFuture, ее серию.
+// Когда вхождение серии выполняется, создается следующее.
+// Сменить владельца может только сам владелец или администратор.
+func (s *TaskService) UpdateTask(ctx context.Context, id uint, task Task, edit SeriesEdit) (Task, error) {
+	existing, err := s.editableTask(ctx, id)
+	if err != nil {
+		return Task{}, err
+	}
+
+	scope, err := ParseEditScope(string(edit.Scope))
+	if err != nil {
+		return Task{}, err
+	}
+	task.SeriesID, task.Recurrence = existing.SeriesID, existing.Recurrence
+	if edit.Recurrence != nil {
+		recurrence, err := normalizeRecurrence(*edit.Recurrence)
+		if err != nil {
+			return Task{}, err
+		}
+		if existing.SeriesID != nil && scope != ScopeFuture && recurrence != existing.Recurrence {
+			return Task{}, ErrRecurrenceScope
+		}
+		task.Recurrence = recurrence
+	}
+
+	// Пустой приоритет оставляет текущий
+	if task.Priority != "" && !validPriority(task.Priority) {
+		return Task{}, ErrInvalidPriority
+	}
+
+	if task.UserID == 0 {
+		task.UserID = existing.UserID
+	}
+	if task.UserID != existing.UserID {
+		caller, _ := identity.FromContext(ctx)
+		if !canAssign(caller, task.UserID) || !canAssign(caller, existing.UserID) {
+			return Task{}, ErrForbidden
+		}
+		if err := s.checkUser(ctx, task.UserID); err != nil {
+			return Task{}, err
+		}
+	}
+
+	task.ParentID, err = s.resolveParent(ctx, existing, task)
+	if err != nil {
+		return Task{}, err
+	}
+	task.ProjectID, err = s.resolveProject(ctx, existing, task)
+	if err != nil {
+		return Task{}, err
+	}
+	status, err := s.resolveStatus(ctx, existing.Status, task)
+	if err != nil {
+		return Task{}, err
+	}
+	task.StatusID, task.IsDone = &status.ID, status.Category == CategoryDone
+
+	completing := task.IsDone && !existing.IsDone
+	if completing {
+		if err := s.checkCompletable(ctx, id); err != nil {
+			return Task{}, err
+		}
+	}
+
+	// Задача, серия, подзадачи и следующее вхождение меняются вместе:
+	// ошибка на любом шаге откатывает всю правку
+	var updated Task
+	err = s.repo.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if task.SeriesID == nil && task.Recurrence != "" {
+			if task.Priority == "" {
+				task.Priority = existing.Priority
+			}
+			if task.SeriesID, err = s.startSeries(ctx, task); err != nil {
+				return err
+			}
+		}
+
+		if updated, err = s.repo.UpdateTaskByID(ctx, id, task); err != nil {
+			return err
+		}
+		if existing.SeriesID != nil && scope == ScopeFuture {
+			if err := s.updateSeries(ctx, updated); err != nil {
+				return err
+			}
+		}
+		if completing && s.completion == CompletionCascade {
+			if err := s.repo.CompleteSubtasks(ctx, id); err != nil {
+				return err
+			}
+		}
+		if completing && updated.SeriesID != nil {
+			return s.nextOccurrence(ctx, updated)
+		}
+		return nil
+	})
+	if err != nil {
+		return Task{}, err
+	}
+	return updated, nil
+}
+
+// resolveProject возвращает проект задачи после обновления. В task.ProjectID
+// nil оставляет текущий проект, а 0 убирает задачу из проекта.
+func (s *TaskService) resolveProject(ctx context.Context, existing Task, task Task) (*uint, error) {
+	projectID := existing.ProjectID
+	if task.ProjectID != nil {
+		projectID = task.ProjectID
+	}
+	if projectID == nil || *projectID == 0 {
+		return nil, nil
+	}
+
+	if sameID(existing.ProjectID, projectID) && task.UserID == existing.UserID {
+		return projectID, nil
+	}
+	if err := s.checkProject(ctx, *projectID, task.UserID); err != nil {
+		return nil, err
+	}
+	return projectID, nil
+}
+
+// checkProject проверяет, что задачу владельца ownerID можно положить в проект
+func (s *TaskService) checkProject(ctx context.Context, projectID, ownerID uint) error {
+	projectOwner, archived, err := s.projects.ProjectOwner(ctx, projectID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && projectOwner != ownerID) {
+		return ErrInvalidProject
+	} else if err != nil {
+		return err
+	}
+	if archived {
+		return ErrProjectArchived
 	}
-	return s.repo.CreateTask(task)
+	return nil
 }
 
-// GetAllTasks возвращает все задачи
-func (s *TaskService) GetAllTasks() ([]Task, error) {
-	return s.repo.GetAllTasks()
+// checkCompletable проверяет, что задачу можно закрыть: ее не блокируют
+// открытые задачи, а подзадачи не мешают закрытию в текущем режиме
+func (s *TaskService) checkCompletable(ctx context.Context, id uint) error {
+	blockers, err := s.repo.CountOpenBlockers(ctx, id)
+	if err != nil {
+		return err
+	}
+	if blockers > 0 {
+		return ErrBlocked
+	}
+
+	switch s.completion {
+	case CompletionBlock:
+		open, err := s.repo.CountOpenSubtasks(ctx, id)
+		if err != nil {
+			return err
+		}
+		if open > 0 {
+			return ErrOpenSubtasks
+		}
+	case CompletionCascade:
+		// Каскад закроет и подзадачи, поэтому их тоже не должны блокировать
+		// открытые задачи за пределами поддерева
+		external, err := s.repo.CountExternalBlockers(ctx, id)
+		if err != nil {
+			return err
+		}
+		if external > 0 {
+			return ErrBlocked
+		}
+	}
+	return nil
+}
+
+// resolveParent возвращает родителя задачи после обновления. В task.ParentID
+// nil оставляет текущего родителя, а 0 делает задачу корневой.
+func (s *TaskService) resolveParent(ctx context.Context, existing Task, task Task) (*uint, error) {
+	parentID := existing.ParentID
+	if task.ParentID != nil {
+		parentID = task.ParentID
+	}
+	if parentID == nil || *parentID == 0 {
+		return nil, nil
+	}
+
+	moved := existing.ParentID == nil || *existing.ParentID != *parentID
+	if !moved && task.UserID == existing.UserID {
+		return parentID, nil
+	}
+
+	parent, err := s.GetTaskByID(ctx, *parentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && parent.UserID != task.UserID) {
+		return nil, ErrInvalidParent
+	} else if err != nil {
+		return nil, err
+	}
+
+	ancestors, err := s.repo.GetAncestorIDs(ctx, parent.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, ancestorID := range ancestors {
+		if ancestorID == existing.ID {
+			return nil, ErrTaskCycle
+		}
+	}
+	return &parent.ID, nil
+}
+
+// DeleteTaskByID удаляет задачу по ID. Участникам задачи удаление недоступно.
+func (s *TaskService) DeleteTaskByID(ctx context.Context, id uint) error {
+	task, err := s.GetTaskByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	caller, _ := identity.FromContext(ctx)
+	if !canAssign(caller, task.UserID) {
+		return ErrForbidden
+	}
+	return s.repo.DeleteTaskByID(ctx, id)
+}
+
+// GetTasksByUserID возвращает задачи пользователя по user_id.
+// Для всех, кроме администраторов, чужой список выглядит как несуществующий.
+func (s *TaskService) GetTasksByUserID(ctx context.Context, userID uint, page pagination.Page) ([]Task, string, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if caller.UserID != userID && !caller.IsAdmin() {
+		return nil, "", gorm.ErrRecordNotFound
+	}
+	return s.repo.GetTasks(ctx, TaskFilter{UserID: &userID}, page)
+}
+
+// AddDependency объявляет, что задача blockerID блокирует задачу blockedID.
+// Обе задачи должны быть доступны вызывающему.
+func (s *TaskService) AddDependency(ctx context.Context, blockerID, blockedID uint) error {
+	if _, err := s.editableTask(ctx, blockedID); err != nil {
+		return err
+	}
+	if _, err := s.GetTaskByID(ctx, blockerID); err != nil {
+		return err
+	}
+
+	if blockerID == blockedID {
+		return ErrDependencyCycle
+	}
+	// Если blockedID уже блокирует blockerID, новая связь замкнет цикл
+	cycle, err := s.repo.Blocks(ctx, blockedID, blockerID)
+	if err != nil {
+		return err
+	}
+	if cycle {
+		return ErrDependencyCycle
+	}
+	return s.repo.AddDependency(ctx, blockerID, blockedID)
 }
 
-// UpdateTaskByID обновляет задачу по ID
-func (s *TaskService) UpdateTaskByID(id uint, task Task) (Task, error) {
-	return s.repo.UpdateTaskByID(id, task)
+// RemoveDependency удаляет связь «blockerID блокирует blockedID»
+func (s *TaskService) RemoveDependency(ctx context.Context, blockerID, blockedID uint) error {
+	if _, err := s.editableTask(ctx, blockedID); err != nil {
+		return err
+	}
+	err := s.repo.RemoveDependency(ctx, blockerID, blockedID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrDependencyNotFound
+	}
+	return err
 }
 
-// DeleteTaskByID удаляет задачу по ID
-func (s *TaskService) DeleteTaskByID(id uint) error {
-	return s.repo.DeleteTaskByID(id)
+// GetDependencies возвращает задачи, блокирующие задачу id, и задачи, которые она блокирует.
+// Недоступные вызывающему задачи в ответ не попадают.
+func (s *TaskService) GetDependencies(ctx context.Context, id uint) (Dependencies, error) {
+	if _, err := s.GetTaskByID(ctx, id); err != nil {
+		return Dependencies{}, err
+	}
+	caller, _ := identity.FromContext(ctx)
+
+	blockers, err := s.repo.GetBlockers(ctx, id)
+	if err != nil {
+		return Dependencies{}, err
+	}
+	dependents, err := s.repo.GetDependents(ctx, id)
+	if err != nil {
+		return Dependencies{}, err
+	}
+	return Dependencies{
+		Blockers:   visibleTasks(caller, blockers),
+		Dependents: visibleTasks(caller, dependents),
+	}, nil
+}
+
+// CreateTag создает метку вызывающего
+func (s *TaskService) CreateTag(ctx context.Context, tag Tag) (Tag, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return Tag{}, err
+	}
+
+	tag.Name = strings.TrimSpace(tag.Name)
+	if tag.Name == "" || len(tag.Name) > 64 {
+		return Tag{}, ErrInvalidTagName
+	}
+	tag.UserID = caller.UserID
+
+	// Проверяем заранее, чтобы не разбирать ошибки уникального индекса разных СУБД
+	tags, err := s.repo.GetTagsByUserID(ctx, caller.UserID)
+	if err != nil {
+		return Tag{}, err
+	}
+	for _, existing := range tags {
+		if existing.Name == tag.Name {
+			return Tag{}, ErrTagExists
+		}
+	}
+	return s.repo.CreateTag(ctx, tag)
+}
+
+// GetTags возвращает метки вызывающего по алфавиту
+func (s *TaskService) GetTags(ctx context.Context) ([]Tag, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetTagsByUserID(ctx, caller.UserID)
+}
+
+// DeleteTag удаляет метку вызывающего и снимает ее со всех задач
+func (s *TaskService) DeleteTag(ctx context.Context, id uint) error {
+	if _, err := s.getTag(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.DeleteTagByID(ctx, id)
+}
+
+// AttachTag навешивает метку на задачу. Метка должна принадлежать владельцу задачи.
+func (s *TaskService) AttachTag(ctx context.Context, taskID, tagID uint) (Task, error) {
+	task, err := s.editableTask(ctx, taskID)
+	if err != nil {
+		return Task{}, err
+	}
+	tag, err := s.getTag(ctx, tagID)
+	if err != nil {
+		return Task{}, err
+	}
+	if tag.UserID != task.UserID {
+		return Task{}, ErrInvalidTag
+	}
+
+	if err := s.repo.AttachTag(ctx, taskID, tagID); err != nil {
+		return Task{}, err
+	}
+	return s.repo.GetTaskByID(ctx, taskID)
+}
+
+// DetachTag снимает метку с задачи
+func (s *TaskService) DetachTag(ctx context.Context, taskID, tagID uint) error {
+	if _, err := s.editableTask(ctx, taskID); err != nil {
+		return err
+	}
+
+	err := s.repo.DetachTag(ctx, taskID, tagID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrTagNotAttached
+	}
+	return err
+}
+
+// getTag загружает метку, если она принадлежит вызывающему или он администратор
+func (s *TaskService) getTag(ctx context.Context, id uint) (Tag, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return Tag{}, err
+	}
+
+	tag, err := s.repo.GetTagByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Tag{}, ErrTagNotFound
+	} else if err != nil {
+		return Tag{}, err
+	}
+
+	if tag.UserID != caller.UserID && !caller.IsAdmin() {
+		return Tag{}, ErrTagNotFound
+	}
+	return tag, nil
+}
+
+// GetTaskTreeByUserID возвращает страницу корневых задач пользователя
+// с вложенными подзадачами. Права доступа — как у GetTasksByUserID.
+func (s *TaskService) GetTaskTreeByUserID(ctx context.Context, userID uint, page pagination.Page) ([]TaskNode, string, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if caller.UserID != userID && !caller.IsAdmin() {
+		return nil, "", gorm.ErrRecordNotFound
+	}
+
+	roots, next, err := s.repo.GetTasks(ctx, TaskFilter{UserID: &userID, RootsOnly: true}, page)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(roots) == 0 {
+		return []TaskNode{}, next, nil
+	}
+
+	rootIDs := make([]uint, 0, len(roots))
+	for _, t := range roots {
+		rootIDs = append(rootIDs, t.ID)
+	}
+	descendants, err := s.repo.GetDescendants(ctx, rootIDs)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Подзадачи, переданные другому пользователю, в его дерево не попадают
+	own := descendants[:0]
+	for _, t := range descendants {
+		if t.UserID == userID {
+			own = append(own, t)
+		}
+	}
+	return buildTree(roots, own), next, nil
+}
+
+// canAccess сообщает, может ли вызывающий читать задачу
+func canAccess(caller identity.Caller, task Task) bool {
+	return task.UserID == caller.UserID || caller.IsAdmin() || roleOf(task, caller.UserID) != ""
+}
+
+// canEdit сообщает, может ли вызывающий менять задачу
+func canEdit(caller identity.Caller, task Task) bool {
+	return task.UserID == caller.UserID || caller.IsAdmin() || roleOf(task, caller.UserID) == RoleAssignee
+}
+
+// visibleTasks оставляет только задачи, доступные вызывающему
+func visibleTasks(caller identity.Caller, tasks []Task) []Task {
+	visible := make([]Task, 0, len(tasks))
+	for _, t := range tasks {
+		if canAccess(caller, t) {
+			visible = append(visible, t)
+		}
+	}
+	return visible
 }
 
-// GetTasksByUserID возвращает задачи пользователя по user_id
-func (s *TaskService) GetTasksByUserID(userID uint) ([]Task, error) {
-	return s.repo.GetTasksByUserID(userID)
+// canAssign сообщает, может ли вызывающий сделать userID владельцем задачи
+func canAssign(caller identity.Caller, userID uint) bool {
+	return userID == caller.UserID || caller.IsAdmin()
 }
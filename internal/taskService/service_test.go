@@ -0,0 +1,106 @@
+package taskService
+
+import (
+	"context"
+	"errors"
+	"newproject/internal/identity"
+	"newproject/internal/pagination"
+	"newproject/internal/tenant"
+	"testing"
+	"time"
+)
+
+var errBoom = errors.New("boom")
+
+// failingRepo ломает поиск вхождений серии — последний шаг UpdateTask
+type failingRepo struct {
+	*taskRepository
+}
+
+func (failingRepo) HasOccurrence(context.Context, uint, time.Time) (bool, error) {
+	return false, errBoom
+}
+
+type noProjects struct{}
+
+func (noProjects) ProjectOwner(context.Context, uint) (uint, bool, error) {
+	return 0, false, errors.New("no projects")
+}
+
+type anyUser struct{}
+
+func (anyUser) UserExists(context.Context, uint) (bool, error) {
+	return true, nil
+}
+
+func callerContext() context.Context {
+	ctx := tenant.WithWorkspace(context.Background(), 1)
+	return identity.WithCaller(ctx, identity.Caller{UserID: 1, Role: "user"})
+}
+
+func TestUpdateTaskRollsBackOnFailure(t *testing.T) {
+	repo := NewTaskRepository(openTestDB(t))
+	ctx := callerContext()
+	service := NewTaskService(repo, noProjects{}, anyUser{}, CompletionCascade)
+
+	parent, err := service.CreateTask(ctx, Task{Task: "daily", Recurrence: "FREQ=DAILY"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	child, err := service.CreateSubtask(ctx, parent.ID, Task{Task: "step"})
+	if err != nil {
+		t.Fatalf("create subtask: %v", err)
+	}
+
+	failing := NewTaskService(failingRepo{repo}, noProjects{}, anyUser{}, CompletionCascade)
+	if _, err := failing.UpdateTaskByID(ctx, parent.ID, Task{Task: "renamed", IsDone: true}); !errors.Is(err, errBoom) {
+		t.Fatalf("update: got %v, want %v", err, errBoom)
+	}
+
+	for _, id := range []uint{parent.ID, child.ID} {
+		task, err := repo.GetTaskByID(ctx, id)
+		if err != nil {
+			t.Fatalf("get %d: %v", id, err)
+		}
+		if task.IsDone || task.Task == "renamed" {
+			t.Errorf("task %d: got done=%v name=%q, want the update rolled back", id, task.IsDone, task.Task)
+		}
+	}
+}
+
+func TestCompletingSeriesStopsAtCount(t *testing.T) {
+	repo := NewTaskRepository(openTestDB(t))
+	ctx := callerContext()
+	service := NewTaskService(repo, noProjects{}, anyUser{}, CompletionBlock)
+
+	due := time.Date(2024, 1, 3, 9, 0, 0, 0, time.UTC)
+	task, err := service.CreateTask(ctx, Task{Task: "twice", DueAt: &due, Recurrence: "FREQ=DAILY;COUNT=2"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	var dues []time.Time
+	for i := 0; i < 3; i++ {
+		dues = append(dues, *task.DueAt)
+		if _, err := service.UpdateTaskByID(ctx, task.ID, Task{Task: task.Task, IsDone: true}); err != nil {
+			t.Fatalf("complete %d: %v", task.ID, err)
+		}
+		open, _, err := service.GetTasks(ctx, TaskFilter{IsDone: new(bool)}, pagination.Page{Limit: 10, Order: pagination.Order{Column: SortColumns["created_at"]}})
+		if err != nil {
+			t.Fatalf("get tasks: %v", err)
+		}
+		if len(open) == 0 {
+			break
+		}
+		task = open[0]
+	}
+
+	want := []time.Time{due, due.AddDate(0, 0, 1)}
+	if len(dues) != len(want) {
+		t.Fatalf("occurrences: got %v, want %v", dues, want)
+	}
+	for i := range want {
+		if !dues[i].Equal(want[i]) {
+			t.Errorf("occurrence %d: got %s, want %s", i, dues[i], want[i])
+		}
+	}
+}
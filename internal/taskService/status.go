@@ -0,0 +1,328 @@
+package taskService
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"newproject/internal/identity"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrStatusNotFound возвращается и для чужих статусов
+	ErrStatusNotFound = fmt.Errorf("status not found: %w", gorm.ErrRecordNotFound)
+	// ErrInvalidStatus — статус не найден на доске владельца задачи
+	ErrInvalidStatus = errors.New("status must belong to the task owner")
+	// ErrInvalidStatusName — пустое или слишком длинное название статуса
+	ErrInvalidStatusName = errors.New("status name must be 1 to 64 characters")
+	// ErrInvalidCategory — категория не из списка todo/in_progress/done
+	ErrInvalidCategory = errors.New("category must be one of todo, in_progress, done")
+	// ErrTransitionNotAllowed — из текущего статуса задачи нельзя перейти в запрошенный
+	ErrTransitionNotAllowed = errors.New("status transition is not allowed")
+	// ErrStatusInUse — в статусе есть задачи, поэтому его нельзя удалить
+	// или перенести между выполненными и невыполненными
+	ErrStatusInUse = errors.New("status has tasks")
+	// ErrStatusRequired — на доске должны остаться статус категории done и хотя бы один другой
+	ErrStatusRequired = errors.New("board must keep a done status and a not done status")
+	// ErrInvalidStatusOrder — в порядке есть чужие статусы или повторы
+	ErrInvalidStatusOrder = errors.New("status order must list the caller's statuses without repeats")
+)
+
+// StatusUpdate — изменения статуса. nil оставляет поле как есть.
+type StatusUpdate struct {
+	Name        *string
+	Category    *string
+	Transitions *[]uint
+}
+
+// GetStatuses возвращает доску вызывающего по порядку колонок.
+// При первом обращении пользователь получает доску по умолчанию.
+func (s *TaskService) GetStatuses(ctx context.Context) ([]TaskStatus, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.ensureStatuses(ctx, caller.UserID)
+}
+
+// CreateStatus добавляет статус в конец доски вызывающего
+func (s *TaskService) CreateStatus(ctx context.Context, status TaskStatus, transitions []uint) (TaskStatus, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return TaskStatus{}, err
+	}
+
+	status.Name = strings.TrimSpace(status.Name)
+	if status.Name == "" || len(status.Name) > 64 {
+		return TaskStatus{}, ErrInvalidStatusName
+	}
+	if !validCategory(status.Category) {
+		return TaskStatus{}, ErrInvalidCategory
+	}
+	status.UserID = caller.UserID
+
+	statuses, err := s.ensureStatuses(ctx, caller.UserID)
+	if err != nil {
+		return TaskStatus{}, err
+	}
+	if err := checkTransitions(statuses, transitions); err != nil {
+		return TaskStatus{}, err
+	}
+	return s.repo.CreateStatus(ctx, status, transitions)
+}
+
+// UpdateStatus меняет название, категорию или переходы статуса
+func (s *TaskService) UpdateStatus(ctx context.Context, id uint, update StatusUpdate) (TaskStatus, error) {
+	status, err := s.getStatus(ctx, id)
+	if err != nil {
+		return TaskStatus{}, err
+	}
+
+	if update.Name != nil {
+		status.Name = strings.TrimSpace(*update.Name)
+		if status.Name == "" || len(status.Name) > 64 {
+			return TaskStatus{}, ErrInvalidStatusName
+		}
+	}
+
+	statuses, err := s.repo.GetStatusesByUserID(ctx, status.UserID)
+	if err != nil {
+		return TaskStatus{}, err
+	}
+	if update.Category != nil && *update.Category != status.Category {
+		if !validCategory(*update.Category) {
+			return TaskStatus{}, ErrInvalidCategory
+		}
+		// Задачи в статусе получили бы другой is_done в обход проверок закрытия
+		if (*update.Category == CategoryDone) != (status.Category == CategoryDone) {
+			if err := s.checkUnused(ctx, id); err != nil {
+				return TaskStatus{}, err
+			}
+		}
+		status.Category = *update.Category
+		if !keepsBoard(statuses, status) {
+			return TaskStatus{}, ErrStatusRequired
+		}
+	}
+	if update.Transitions != nil {
+		if err := checkTransitions(statuses, *update.Transitions); err != nil {
+			return TaskStatus{}, err
+		}
+	}
+	return s.repo.UpdateStatus(ctx, status, update.Transitions)
+}
+
+// DeleteStatus удаляет пустой статус
+func (s *TaskService) DeleteStatus(ctx context.Context, id uint) error {
+	status, err := s.getStatus(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.checkUnused(ctx, id); err != nil {
+		return err
+	}
+
+	statuses, err := s.repo.GetStatusesByUserID(ctx, status.UserID)
+	if err != nil {
+		return err
+	}
+	rest := make([]TaskStatus, 0, len(statuses))
+	for _, st := range statuses {
+		if st.ID != id {
+			rest = append(rest, st)
+		}
+	}
+	if !hasDone(rest, true) || !hasDone(rest, false) {
+		return ErrStatusRequired
+	}
+	return s.repo.DeleteStatusByID(ctx, id)
+}
+
+// ReorderStatuses задает порядок колонок доски вызывающего
+func (s *TaskService) ReorderStatuses(ctx context.Context, statusIDs []uint) error {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := s.ensureStatuses(ctx, caller.UserID); err != nil {
+		return err
+	}
+	return s.repo.SetStatusOrder(ctx, caller.UserID, statusIDs)
+}
+
+// SetStatus переводит задачу в статус statusID. Переходы проверяет UpdateTask,
+// is_done задачи следует за категорией нового статуса.
+func (s *TaskService) SetStatus(ctx context.Context, id, statusID uint) (Task, error) {
+	existing, err := s.GetTaskByID(ctx, id)
+	if err != nil {
+		return Task{}, err
+	}
+
+	statuses, err := s.ensureStatuses(ctx, existing.UserID)
+	if err != nil {
+		return Task{}, err
+	}
+	target, ok := findStatus(statuses, statusID)
+	if !ok {
+		return Task{}, ErrInvalidStatus
+	}
+
+	task := Task{
+		Task:     existing.Task,
+		IsDone:   target.Category == CategoryDone,
+		UserID:   existing.UserID,
+		StatusID: &target.ID,
+	}
+	return s.UpdateTask(ctx, id, task, SeriesEdit{Scope: ScopeThis})
+}
+
+// resolveStatus выбирает статус задачи после создания или обновления.
+// Явный task.StatusID должен быть на доске владельца. Иначе статус следует
+// за is_done: текущий сохраняется, если он ему соответствует, а при смене
+// владельца или is_done берется первый подходящий статус, по возможности
+// той же категории. Новый статус должен быть разрешен переходами из current.
+func (s *TaskService) resolveStatus(ctx context.Context, current *TaskStatus, task Task) (TaskStatus, error) {
+	statuses, err := s.ensureStatuses(ctx, task.UserID)
+	if err != nil {
+		return TaskStatus{}, err
+	}
+
+	if task.StatusID != nil {
+		status, ok := findStatus(statuses, *task.StatusID)
+		if !ok {
+			return TaskStatus{}, ErrInvalidStatus
+		}
+		if !reachable(statuses, current, status) {
+			return TaskStatus{}, ErrTransitionNotAllowed
+		}
+		return status, nil
+	}
+
+	category := CategoryTodo
+	if task.IsDone {
+		category = CategoryDone
+	}
+	if current != nil && (current.Category == CategoryDone) == task.IsDone {
+		if current.UserID == task.UserID {
+			if status, ok := findStatus(statuses, current.ID); ok {
+				return status, nil
+			}
+		}
+		category = current.Category
+	}
+
+	for _, st := range statuses {
+		if st.Category == category && reachable(statuses, current, st) {
+			return st, nil
+		}
+	}
+	for _, st := range statuses {
+		if (st.Category == CategoryDone) == task.IsDone && reachable(statuses, current, st) {
+			return st, nil
+		}
+	}
+	if hasDone(statuses, task.IsDone) {
+		return TaskStatus{}, ErrTransitionNotAllowed
+	}
+	return TaskStatus{}, ErrStatusRequired
+}
+
+// reachable сообщает, разрешен ли переход из current в target. Переходы
+// действуют на доске владельца: задача, сменившая владельца, начинает с его
+// доски заново, а current с чужой доски ничего не ограничивает.
+func reachable(statuses []TaskStatus, current *TaskStatus, target TaskStatus) bool {
+	if current == nil || current.ID == target.ID {
+		return true
+	}
+	from, ok := findStatus(statuses, current.ID)
+	if !ok || len(from.Transitions) == 0 {
+		return true
+	}
+	_, allowed := findStatus(from.Transitions, target.ID)
+	return allowed
+}
+
+// ensureStatuses возвращает доску пользователя, при необходимости создавая ее
+func (s *TaskService) ensureStatuses(ctx context.Context, userID uint) ([]TaskStatus, error) {
+	statuses, err := s.repo.GetStatusesByUserID(ctx, userID)
+	if err != nil || len(statuses) > 0 {
+		return statuses, err
+	}
+	return s.repo.CreateDefaultStatuses(ctx, userID)
+}
+
+// getStatus загружает статус, если он принадлежит вызывающему или он администратор
+func (s *TaskService) getStatus(ctx context.Context, id uint) (TaskStatus, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return TaskStatus{}, err
+	}
+
+	status, err := s.repo.GetStatusByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return TaskStatus{}, ErrStatusNotFound
+	} else if err != nil {
+		return TaskStatus{}, err
+	}
+
+	if status.UserID != caller.UserID && !caller.IsAdmin() {
+		return TaskStatus{}, ErrStatusNotFound
+	}
+	return status, nil
+}
+
+// checkUnused проверяет, что в статусе нет задач
+func (s *TaskService) checkUnused(ctx context.Context, id uint) error {
+	count, err := s.repo.CountTasksWithStatus(ctx, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrStatusInUse
+	}
+	return nil
+}
+
+// checkTransitions проверяет, что переходы ведут в статусы той же доски
+func checkTransitions(statuses []TaskStatus, transitions []uint) error {
+	for _, id := range transitions {
+		if _, ok := findStatus(statuses, id); !ok {
+			return ErrInvalidStatus
+		}
+	}
+	return nil
+}
+
+// keepsBoard сообщает, останутся ли на доске выполненный и невыполненный статусы
+// после замены статуса changed
+func keepsBoard(statuses []TaskStatus, changed TaskStatus) bool {
+	board := make([]TaskStatus, 0, len(statuses))
+	for _, st := range statuses {
+		if st.ID == changed.ID {
+			st = changed
+		}
+		board = append(board, st)
+	}
+	return hasDone(board, true) && hasDone(board, false)
+}
+
+// hasDone сообщает, есть ли на доске статус, в котором задача выполнена (done) или нет
+func hasDone(statuses []TaskStatus, done bool) bool {
+	for _, st := range statuses {
+		if (st.Category == CategoryDone) == done {
+			return true
+		}
+	}
+	return false
+}
+
+func findStatus(statuses []TaskStatus, id uint) (TaskStatus, bool) {
+	for _, st := range statuses {
+		if st.ID == id {
+			return st, true
+		}
+	}
+	return TaskStatus{}, false
+}
@@ -0,0 +1,64 @@
+package taskService
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsDoneFollowsTransitions(t *testing.T) {
+	repo := NewTaskRepository(openTestDB(t))
+	ctx := callerContext()
+	service := NewTaskService(repo, noProjects{}, anyUser{}, CompletionBlock)
+
+	statuses, err := service.GetStatuses(ctx)
+	if err != nil {
+		t.Fatalf("get statuses: %v", err)
+	}
+	byName := map[string]TaskStatus{}
+	for _, st := range statuses {
+		byName[st.Name] = st
+	}
+	// Из backlog закрыть задачу можно только через in_progress
+	only := []uint{byName["in_progress"].ID}
+	if _, err := service.UpdateStatus(ctx, byName["backlog"].ID, StatusUpdate{Transitions: &only}); err != nil {
+		t.Fatalf("update status: %v", err)
+	}
+
+	task, err := service.CreateTask(ctx, Task{Task: "t"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := service.UpdateTaskByID(ctx, task.ID, Task{Task: "t", IsDone: true}); !errors.Is(err, ErrTransitionNotAllowed) {
+		t.Fatalf("close from backlog: got %v, want %v", err, ErrTransitionNotAllowed)
+	}
+	if _, err := service.SetStatus(ctx, task.ID, byName["review"].ID); !errors.Is(err, ErrTransitionNotAllowed) {
+		t.Fatalf("backlog to review: got %v, want %v", err, ErrTransitionNotAllowed)
+	}
+	got, err := service.GetTaskByID(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.IsDone || *got.StatusID != byName["backlog"].ID {
+		t.Fatalf("task: got done=%v status=%d, want backlog", got.IsDone, *got.StatusID)
+	}
+
+	if _, err := service.SetStatus(ctx, task.ID, byName["in_progress"].ID); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	done, err := service.UpdateTaskByID(ctx, task.ID, Task{Task: "t", IsDone: true})
+	if err != nil {
+		t.Fatalf("close from in_progress: %v", err)
+	}
+	if *done.StatusID != byName["done"].ID {
+		t.Errorf("closed status: got %d, want %d", *done.StatusID, byName["done"].ID)
+	}
+
+	// done ведет только в in_progress, туда и возвращается задача
+	reopened, err := service.UpdateTaskByID(ctx, task.ID, Task{Task: "t", IsDone: false})
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if *reopened.StatusID != byName["in_progress"].ID {
+		t.Errorf("reopened status: got %d, want %d", *reopened.StatusID, byName["in_progress"].ID)
+	}
+}
@@ -0,0 +1,61 @@
+package taskService
+
+import (
+	"context"
+	"errors"
+	"newproject/internal/identity"
+	"newproject/internal/pagination"
+
+	"gorm.io/gorm"
+)
+
+// ErrParentDeleted — подзадачу нельзя восстановить, пока ее родитель в корзине
+var ErrParentDeleted = errors.New("parent task is in the trash, restore it first")
+
+// GetDeletedTasks возвращает страницу корзины вызывающего.
+// Администратор видит корзину всего рабочего пространства.
+func (s *TaskService) GetDeletedTasks(ctx context.Context, page pagination.Page) ([]Task, string, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var filter TaskFilter
+	if !caller.IsAdmin() {
+		filter.UserID = &caller.UserID
+	}
+	return s.repo.GetDeletedTasks(ctx, filter, page)
+}
+
+// RestoreTask возвращает задачу из корзины вместе с подзадачами, удаленными
+// вместе с ней. Восстановить задачу может тот, кто мог ее удалить.
+func (s *TaskService) RestoreTask(ctx context.Context, id uint) (Task, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return Task{}, err
+	}
+
+	task, err := s.repo.GetDeletedTaskByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Task{}, ErrTaskNotFound
+	} else if err != nil {
+		return Task{}, err
+	}
+	if !canAssign(caller, task.UserID) {
+		return Task{}, ErrTaskNotFound
+	}
+
+	if task.ParentID != nil {
+		_, err := s.repo.GetTaskByID(ctx, *task.ParentID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return Task{}, ErrParentDeleted
+		} else if err != nil {
+			return Task{}, err
+		}
+	}
+
+	if err := s.repo.RestoreTask(ctx, id); err != nil {
+		return Task{}, err
+	}
+	return s.repo.GetTaskByID(ctx, id)
+}
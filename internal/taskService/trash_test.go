@@ -0,0 +1,163 @@
+package taskService
+
+import (
+	"context"
+	"errors"
+	"newproject/internal/identity"
+	"newproject/internal/pagination"
+	"newproject/internal/tenant"
+	"testing"
+	"time"
+)
+
+func TestDeletedTasksListing(t *testing.T) {
+	repo := NewTaskRepository(openTestDB(t))
+	ctx := callerContext()
+	service := NewTaskService(repo, noProjects{}, anyUser{}, CompletionBlock)
+
+	parent, child, _ := createChain(t, ctx, service)
+	lone, err := service.CreateTask(ctx, Task{Task: "lone"})
+	if err != nil {
+		t.Fatalf("create lone: %v", err)
+	}
+	if _, err := service.CreateTask(ctx, Task{Task: "alive"}); err != nil {
+		t.Fatalf("create alive: %v", err)
+	}
+	foreign, err := repo.CreateTask(ctx, Task{Task: "foreign", UserID: 2})
+	if err != nil {
+		t.Fatalf("create foreign: %v", err)
+	}
+	for _, id := range []uint{parent.ID, lone.ID} {
+		if err := service.DeleteTaskByID(ctx, id); err != nil {
+			t.Fatalf("delete %d: %v", id, err)
+		}
+	}
+	if err := repo.DeleteTaskByID(ctx, foreign.ID); err != nil {
+		t.Fatalf("delete foreign: %v", err)
+	}
+	if _, err := repo.GetDeletedTaskByID(ctx, child.ID); err != nil {
+		t.Errorf("child deleted with its parent: %v", err)
+	}
+
+	admin := identity.WithCaller(tenant.WithWorkspace(context.Background(), 1), identity.Caller{UserID: 3, WorkspaceRole: "admin"})
+	// Подзадачи, удаленные вместе с родителем, в корзину не попадают
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want []uint
+	}{
+		{name: "owner", ctx: ctx, want: []uint{lone.ID, parent.ID}},
+		{name: "workspace admin", ctx: admin, want: []uint{foreign.ID, lone.ID, parent.ID}},
+		{name: "other workspace", ctx: identity.WithCaller(tenant.WithWorkspace(context.Background(), 2), identity.Caller{UserID: 1})},
+	}
+	page := pagination.Page{Limit: 10, Order: pagination.ByDeletedAt}
+	for _, tt := range tests {
+		tasks, _, err := service.GetDeletedTasks(tt.ctx, page)
+		if err != nil {
+			t.Fatalf("%s: list: %v", tt.name, err)
+		}
+		got := map[uint]bool{}
+		for _, task := range tasks {
+			got[task.ID] = true
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %d tasks, want %v", tt.name, len(got), tt.want)
+			continue
+		}
+		for _, id := range tt.want {
+			if !got[id] {
+				t.Errorf("%s: task %d is missing", tt.name, id)
+			}
+		}
+	}
+}
+
+func TestRestoreTask(t *testing.T) {
+	repo := NewTaskRepository(openTestDB(t))
+	ctx := callerContext()
+	service := NewTaskService(repo, noProjects{}, anyUser{}, CompletionBlock)
+
+	parent, child, grandchild := createChain(t, ctx, service)
+	// Подзадача, удаленная раньше родителя, остается в корзине после его восстановления
+	if err := service.DeleteTaskByID(ctx, grandchild.ID); err != nil {
+		t.Fatalf("delete grandchild: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if err := service.DeleteTaskByID(ctx, parent.ID); err != nil {
+		t.Fatalf("delete parent: %v", err)
+	}
+
+	if _, err := service.RestoreTask(ctx, child.ID); !errors.Is(err, ErrParentDeleted) {
+		t.Errorf("child before parent: got %v, want %v", err, ErrParentDeleted)
+	}
+	stranger := identity.WithCaller(tenant.WithWorkspace(context.Background(), 1), identity.Caller{UserID: 2})
+	if _, err := service.RestoreTask(stranger, parent.ID); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("stranger: got %v, want %v", err, ErrTaskNotFound)
+	}
+	if _, err := service.RestoreTask(ctx, parent.ID); err != nil {
+		t.Fatalf("restore parent: %v", err)
+	}
+	if _, err := service.RestoreTask(ctx, parent.ID); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("restore twice: got %v, want %v", err, ErrTaskNotFound)
+	}
+
+	for _, task := range []Task{parent, child} {
+		if _, err := repo.GetTaskByID(ctx, task.ID); err != nil {
+			t.Errorf("%q restored: %v", task.Task, err)
+		}
+	}
+	if _, err := repo.GetDeletedTaskByID(ctx, grandchild.ID); err != nil {
+		t.Errorf("grandchild stays in the trash: %v", err)
+	}
+}
+
+func TestPurgeTasks(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewTaskRepository(db)
+	ctx := tenant.WithWorkspace(context.Background(), 1)
+	before := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name       string
+		deletedAt  *time.Time
+		wantPurged bool
+	}{
+		{name: "long ago", deletedAt: ptr(before.Add(-time.Hour)), wantPurged: true},
+		{name: "just before", deletedAt: ptr(before.Add(-time.Second)), wantPurged: true},
+		{name: "exactly at the boundary", deletedAt: &before},
+		{name: "after", deletedAt: ptr(before.Add(time.Second))},
+		{name: "not deleted"},
+	}
+	ids := make([]uint, len(tests))
+	for i, tt := range tests {
+		task, err := repo.CreateTask(ctx, Task{Task: tt.name, UserID: 1})
+		if err != nil {
+			t.Fatalf("%s: create: %v", tt.name, err)
+		}
+		if tt.deletedAt != nil {
+			if err := db.WithContext(ctx).Unscoped().Model(&Task{}).Where("id = ?", task.ID).Update("deleted_at", *tt.deletedAt).Error; err != nil {
+				t.Fatalf("%s: delete: %v", tt.name, err)
+			}
+		}
+		ids[i] = task.ID
+	}
+
+	purged, err := repo.PurgeTasks(ctx, before)
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if purged != 2 {
+		t.Errorf("purged: got %d, want 2", purged)
+	}
+	for i, tt := range tests {
+		var count int64
+		db.WithContext(ctx).Unscoped().Model(&Task{}).Where("id = ?", ids[i]).Count(&count)
+		if (count == 0) != tt.wantPurged {
+			t.Errorf("%s: got purged %v, want %v", tt.name, count == 0, tt.wantPurged)
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
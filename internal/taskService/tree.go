@@ -0,0 +1,54 @@
+package taskService
+
+import "fmt"
+
+// CompletionMode задает, как выполнение родительской задачи связано с подзадачами
+type CompletionMode string
+
+const (
+	// CompletionBlock запрещает закрыть задачу, пока открыта хотя бы одна подзадача
+	CompletionBlock CompletionMode = "block"
+	// CompletionCascade при закрытии задачи закрывает и все ее подзадачи
+	CompletionCascade CompletionMode = "cascade"
+	// CompletionOff не связывает выполнение задачи с подзадачами
+	CompletionOff CompletionMode = "off"
+)
+
+// ParseCompletionMode разбирает режим из конфигурации, пустая строка — CompletionBlock
+func ParseCompletionMode(s string) (CompletionMode, error) {
+	switch mode := CompletionMode(s); mode {
+	case "":
+		return CompletionBlock, nil
+	case CompletionBlock, CompletionCascade, CompletionOff:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown completion mode %q, want block, cascade or off", s)
+	}
+}
+
+// TaskNode — задача вместе с вложенными подзадачами
+type TaskNode struct {
+	Task
+	Subtasks []TaskNode `json:"subtasks"`
+}
+
+// buildTree раскладывает подзадачи по родителям. Подзадачи, чей родитель
+// не попал в выборку, отбрасываются.
+func buildTree(roots []Task, descendants []Task) []TaskNode {
+	children := make(map[uint][]Task)
+	for _, t := range descendants {
+		if t.ParentID != nil {
+			children[*t.ParentID] = append(children[*t.ParentID], t)
+		}
+	}
+
+	var build func(tasks []Task) []TaskNode
+	build = func(tasks []Task) []TaskNode {
+		nodes := make([]TaskNode, 0, len(tasks))
+		for _, t := range tasks {
+			nodes = append(nodes, TaskNode{Task: t, Subtasks: build(children[t.ID])})
+		}
+		return nodes
+	}
+	return build(roots)
+}
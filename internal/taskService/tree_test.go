@@ -0,0 +1,127 @@
+package taskService
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestResolveParent(t *testing.T) {
+	repo := NewTaskRepository(openTestDB(t))
+	ctx := callerContext()
+	service := NewTaskService(repo, noProjects{}, anyUser{}, CompletionBlock)
+
+	// a → b → c, d — отдельная корневая задача, foreign — задача другого владельца
+	a, err := service.CreateTask(ctx, Task{Task: "a"})
+	if err != nil {
+		t.Fatalf("create a: %v", err)
+	}
+	b, err := service.CreateSubtask(ctx, a.ID, Task{Task: "b"})
+	if err != nil {
+		t.Fatalf("create b: %v", err)
+	}
+	c, err := service.CreateSubtask(ctx, b.ID, Task{Task: "c"})
+	if err != nil {
+		t.Fatalf("create c: %v", err)
+	}
+	d, err := service.CreateTask(ctx, Task{Task: "d"})
+	if err != nil {
+		t.Fatalf("create d: %v", err)
+	}
+	foreign, err := repo.CreateTask(ctx, Task{Task: "foreign", UserID: 2})
+	if err != nil {
+		t.Fatalf("create foreign: %v", err)
+	}
+	id := func(v uint) *uint { return &v }
+
+	tests := []struct {
+		name     string
+		existing Task
+		parentID *uint
+		want     *uint
+		wantErr  error
+	}{
+		{name: "keep parent", existing: b, want: &a.ID},
+		{name: "make root", existing: b, parentID: id(0)},
+		{name: "move under another task", existing: c, parentID: &d.ID, want: &d.ID},
+		{name: "move root under a task", existing: d, parentID: &c.ID, want: &c.ID},
+		{name: "self", existing: a, parentID: &a.ID, wantErr: ErrTaskCycle},
+		{name: "under own child", existing: a, parentID: &b.ID, wantErr: ErrTaskCycle},
+		{name: "under own grandchild", existing: a, parentID: &c.ID, wantErr: ErrTaskCycle},
+		{name: "child under own child", existing: b, parentID: &c.ID, wantErr: ErrTaskCycle},
+		{name: "unknown parent", existing: d, parentID: id(999), wantErr: ErrInvalidParent},
+		{name: "parent of another owner", existing: d, parentID: &foreign.ID, wantErr: ErrInvalidParent},
+	}
+	for _, tt := range tests {
+		got, err := service.resolveParent(ctx, tt.existing, Task{UserID: tt.existing.UserID, ParentID: tt.parentID})
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Errorf("%s: got parent %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCompletingTaskWithOpenSubtasks(t *testing.T) {
+	tests := []struct {
+		mode          CompletionMode
+		wantErr       error
+		wantDone      bool
+		wantSubsDone  bool
+		closeSubtasks bool
+	}{
+		{mode: CompletionBlock, wantErr: ErrOpenSubtasks},
+		{mode: CompletionBlock, closeSubtasks: true, wantDone: true, wantSubsDone: true},
+		{mode: CompletionCascade, wantDone: true, wantSubsDone: true},
+		{mode: CompletionOff, wantDone: true},
+	}
+	for _, tt := range tests {
+		repo := NewTaskRepository(openTestDB(t))
+		ctx := callerContext()
+		service := NewTaskService(repo, noProjects{}, anyUser{}, tt.mode)
+
+		parent, child, grandchild := createChain(t, ctx, service)
+		if tt.closeSubtasks {
+			for _, sub := range []Task{grandchild, child} {
+				if _, err := service.UpdateTaskByID(ctx, sub.ID, Task{Task: sub.Task, IsDone: true}); err != nil {
+					t.Fatalf("%s: close %q: %v", tt.mode, sub.Task, err)
+				}
+			}
+		}
+
+		if _, err := service.UpdateTaskByID(ctx, parent.ID, Task{Task: parent.Task, IsDone: true}); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: complete: got %v, want %v", tt.mode, err, tt.wantErr)
+		}
+		for _, task := range []Task{parent, child, grandchild} {
+			stored, err := repo.GetTaskByID(ctx, task.ID)
+			if err != nil {
+				t.Fatalf("%s: get %q: %v", tt.mode, task.Task, err)
+			}
+			want := tt.wantSubsDone
+			if task.ID == parent.ID {
+				want = tt.wantDone
+			}
+			if stored.IsDone != want {
+				t.Errorf("%s: %q done: got %v, want %v", tt.mode, task.Task, stored.IsDone, want)
+			}
+		}
+	}
+}
+
+// createChain создает задачу с подзадачей и подподзадачей
+func createChain(t *testing.T, ctx context.Context, service *TaskService) (parent, child, grandchild Task) {
+	t.Helper()
+	var err error
+	if parent, err = service.CreateTask(ctx, Task{Task: "parent"}); err != nil {
+		t.Fatalf("create parent: %v", err)
+	}
+	if child, err = service.CreateSubtask(ctx, parent.ID, Task{Task: "child"}); err != nil {
+		t.Fatalf("create child: %v", err)
+	}
+	if grandchild, err = service.CreateSubtask(ctx, child.ID, Task{Task: "grandchild"}); err != nil {
+		t.Fatalf("create grandchild: %v", err)
+	}
+	return parent, child, grandchild
+}
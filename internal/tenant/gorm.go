@@ -0,0 +1,100 @@
+package tenant
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Plugin изолирует данные рабочих пространств. Модель участвует в изоляции,
+// если у нее есть поле с тегом gorm:"tenant", например
+//
+//	WorkspaceID uint `gorm:"not null;index;tenant"`
+//
+// Выборки, обновления и удаления таких моделей ограничиваются пространством
+// из контекста запроса (db.WithContext), а при создании поле заполняется им.
+// Без пространства в контексте запрос завершается ErrNoWorkspace,
+// если изоляция не снята через WithAllWorkspaces.
+// Raw и Exec не изолируются: вызывающий добавляет к ним условие из Condition.
+type Plugin struct{}
+
+func (Plugin) Name() string {
+	return "tenant"
+}
+
+func (Plugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().Before("gorm:create").Register("tenant:create", assign); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:query", restrict); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant:update", restrict); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("tenant:delete", restrict); err != nil {
+		return err
+	}
+	return callbacks.Row().Before("gorm:row").Register("tenant:row", restrict)
+}
+
+// tenantField возвращает поле рабочего пространства модели запроса или nil
+func tenantField(db *gorm.DB) *schema.Field {
+	if db.Statement.Schema == nil {
+		return nil
+	}
+	for _, field := range db.Statement.Schema.Fields {
+		if _, ok := field.TagSettings["TENANT"]; ok {
+			return field
+		}
+	}
+	return nil
+}
+
+// restrict добавляет к запросу условие на рабочее пространство
+func restrict(db *gorm.DB) {
+	field := tenantField(db)
+	if field == nil || db.Error != nil || allWorkspaces(db.Statement.Context) {
+		return
+	}
+	workspaceID, err := Require(db.Statement.Context)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: db.Statement.Table, Name: field.DBName}, Value: workspaceID},
+	}})
+}
+
+// assign проставляет создаваемым записям рабочее пространство из контекста
+func assign(db *gorm.DB) {
+	field := tenantField(db)
+	if field == nil || db.Error != nil || allWorkspaces(db.Statement.Context) {
+		return
+	}
+	workspaceID, err := Require(db.Statement.Context)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+
+	ctx := db.Statement.Context
+	switch value := db.Statement.ReflectValue; value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			item := reflect.Indirect(value.Index(i))
+			if err := field.Set(ctx, item, workspaceID); err != nil {
+				db.AddError(err)
+				return
+			}
+		}
+	case reflect.Struct:
+		if err := field.Set(ctx, value, workspaceID); err != nil {
+			db.AddError(err)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// note — изолированная модель для тестов
+type note struct {
+	ID          uint
+	WorkspaceID uint `gorm:"not null;tenant"`
+	Text        string
+}
+
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.Use(Plugin{}); err != nil {
+		t.Fatalf("tenant plugin: %v", err)
+	}
+	if err := db.AutoMigrate(&note{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func TestPluginRejectsQueriesWithoutWorkspace(t *testing.T) {
+	db := openTestDB(t).WithContext(context.Background())
+
+	checks := map[string]error{
+		"create": db.Create(&note{Text: "a"}).Error,
+		"find":   db.Find(&[]note{}).Error,
+		"count":  db.Model(&note{}).Count(new(int64)).Error,
+		"update": db.Model(&note{}).Where("id = ?", 1).Update("text", "b").Error,
+		"delete": db.Delete(&note{}, 1).Error,
+	}
+	for name, err := range checks {
+		if !errors.Is(err, ErrNoWorkspace) {
+			t.Errorf("%s: got %v, want %v", name, err, ErrNoWorkspace)
+		}
+	}
+}
+
+func TestPluginIsolatesWorkspaces(t *testing.T) {
+	db := openTestDB(t)
+	ws1 := db.WithContext(WithWorkspace(context.Background(), 1))
+	ws2 := db.WithContext(WithWorkspace(context.Background(), 2))
+
+	own := note{Text: "own"}
+	// Пространство берется из контекста, а не из записи
+	foreign := note{WorkspaceID: 1, Text: "foreign"}
+	if err := ws1.Create(&own).Error; err != nil {
+		t.Fatalf("create own: %v", err)
+	}
+	if err := ws2.Create(&foreign).Error; err != nil {
+		t.Fatalf("create foreign: %v", err)
+	}
+	if foreign.WorkspaceID != 2 {
+		t.Errorf("created workspace: got %d, want 2", foreign.WorkspaceID)
+	}
+
+	var notes []note
+	if err := ws1.Find(&notes).Error; err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if len(notes) != 1 || notes[0].ID != own.ID {
+		t.Errorf("find: got %+v, want only %d", notes, own.ID)
+	}
+	if err := ws1.First(&note{}, foreign.ID).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("first foreign: got %v, want %v", err, gorm.ErrRecordNotFound)
+	}
+
+	update := ws1.Model(&note{}).Where("id = ?", foreign.ID).Update("text", "changed")
+	if update.Error != nil || update.RowsAffected != 0 {
+		t.Errorf("update foreign: got %d rows, %v, want 0 rows", update.RowsAffected, update.Error)
+	}
+	remove := ws1.Delete(&note{}, foreign.ID)
+	if remove.Error != nil || remove.RowsAffected != 0 {
+		t.Errorf("delete foreign: got %d rows, %v, want 0 rows", remove.RowsAffected, remove.Error)
+	}
+
+	var stored note
+	if err := ws2.First(&stored, foreign.ID).Error; err != nil {
+		t.Fatalf("first in own workspace: %v", err)
+	}
+	if stored.Text != "foreign" {
+		t.Errorf("foreign text: got %q, want %q", stored.Text, "foreign")
+	}
+
+	var count int64
+	all := db.WithContext(WithAllWorkspaces(context.Background()))
+	if err := all.Model(&note{}).Count(&count).Error; err != nil || count != 2 {
+		t.Errorf("all workspaces: got %d, %v, want 2", count, err)
+	}
+}
+
+func TestCondition(t *testing.T) {
+	db := openTestDB(t)
+	ctx := WithWorkspace(context.Background(), 1)
+	db.WithContext(ctx).Create(&note{Text: "own"})
+	db.WithContext(WithWorkspace(context.Background(), 2)).Create(&note{Text: "foreign"})
+
+	if _, err := Condition(context.Background(), "workspace_id"); !errors.Is(err, ErrNoWorkspace) {
+		t.Errorf("without workspace: got %v, want %v", err, ErrNoWorkspace)
+	}
+
+	tests := []struct {
+		ctx  context.Context
+		want int
+	}{
+		{ctx: ctx, want: 1},
+		{ctx: WithAllWorkspaces(context.Background()), want: 2},
+	}
+	for _, tt := range tests {
+		cond, err := Condition(tt.ctx, "workspace_id")
+		if err != nil {
+			t.Fatalf("condition: %v", err)
+		}
+		var texts []string
+		if err := db.Raw("SELECT text FROM notes WHERE ?", cond).Scan(&texts).Error; err != nil {
+			t.Fatalf("raw: %v", err)
+		}
+		if len(texts) != tt.want {
+			t.Errorf("raw: got %v, want %d rows", texts, tt.want)
+		}
+	}
+}
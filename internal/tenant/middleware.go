@@ -0,0 +1,66 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"newproject/internal/identity"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// HeaderWorkspaceID — заголовок, которым клиент выбирает рабочее пространство запроса
+const HeaderWorkspaceID = "X-Workspace-ID"
+
+// Resolver выбирает рабочее пространство для вызывающего
+type Resolver interface {
+	// ResolveWorkspace возвращает requested, если пользователь в нем состоит,
+	// а при requested == 0 — его пространство по умолчанию, вместе с ролью
+	// пользователя в пространстве. Если пользователь не состоит в пространстве,
+	// возвращает ErrNotMember.
+	ResolveWorkspace(ctx context.Context, userID, requested uint) (workspaceID uint, role string, err error)
+}
+
+// Middleware кладет в контекст запроса рабочее пространство из заголовка
+// X-Workspace-ID или пространство вызывающего по умолчанию, а в вызывающего —
+// его роль в этом пространстве. Должен стоять
+// после identity.Middleware. Маршруты, для которых skipper вернул true,
+// пропускаются, и запросы к изолированным данным в них не проходят.
+func Middleware(resolver Resolver, skipper middleware.Skipper) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if skipper != nil && skipper(c) {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+			caller, err := identity.Require(ctx)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, "unauthenticated")
+			}
+
+			var requested uint
+			if header := c.Request().Header.Get(HeaderWorkspaceID); header != "" {
+				id, err := strconv.ParseUint(header, 10, 64)
+				if err != nil || id == 0 {
+					return echo.NewHTTPError(http.StatusBadRequest, "invalid "+HeaderWorkspaceID+" header")
+				}
+				requested = uint(id)
+			}
+
+			workspaceID, role, err := resolver.ResolveWorkspace(ctx, caller.UserID, requested)
+			if errors.Is(err, ErrNotMember) {
+				return echo.NewHTTPError(http.StatusForbidden, err.Error())
+			} else if err != nil {
+				return err
+			}
+
+			caller.WorkspaceRole = role
+			ctx = identity.WithCaller(WithWorkspace(ctx, workspaceID), caller)
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package tenant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"newproject/internal/identity"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+type membership struct {
+	workspaceID uint
+	role        string
+}
+
+// memberships — Resolver по таблице: пользователь → его членства, первое по умолчанию
+type memberships map[uint][]membership
+
+func (m memberships) ResolveWorkspace(_ context.Context, userID, requested uint) (uint, string, error) {
+	members := m[userID]
+	if len(members) == 0 {
+		return 0, "", ErrNotMember
+	}
+	if requested == 0 {
+		return members[0].workspaceID, members[0].role, nil
+	}
+	for _, member := range members {
+		if member.workspaceID == requested {
+			return member.workspaceID, member.role, nil
+		}
+	}
+	return 0, "", ErrNotMember
+}
+
+func TestMiddleware(t *testing.T) {
+	resolver := memberships{1: {{10, "member"}, {20, "owner"}}}
+	skipper := func(c echo.Context) bool { return c.Path() == "/public" }
+
+	tests := []struct {
+		name   string
+		path   string
+		caller *identity.Caller
+		header string
+		code   int
+		want   uint
+		role   string
+	}{
+		{name: "default workspace", path: "/data", caller: &identity.Caller{UserID: 1}, code: http.StatusOK, want: 10, role: "member"},
+		{name: "chosen workspace", path: "/data", caller: &identity.Caller{UserID: 1}, header: "20", code: http.StatusOK, want: 20, role: "owner"},
+		{name: "role is not taken from the caller", path: "/data", caller: &identity.Caller{UserID: 1, WorkspaceRole: "owner"}, code: http.StatusOK, want: 10, role: "member"},
+		{name: "not a member", path: "/data", caller: &identity.Caller{UserID: 1}, header: "30", code: http.StatusForbidden},
+		{name: "no workspaces", path: "/data", caller: &identity.Caller{UserID: 2}, code: http.StatusForbidden},
+		{name: "invalid header", path: "/data", caller: &identity.Caller{UserID: 1}, header: "x", code: http.StatusBadRequest},
+		{name: "zero header", path: "/data", caller: &identity.Caller{UserID: 1}, header: "0", code: http.StatusBadRequest},
+		{name: "anonymous", path: "/data", code: http.StatusUnauthorized},
+		{name: "skipped", path: "/public", code: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			var got uint
+			var isolated bool
+			var caller identity.Caller
+			handler := func(c echo.Context) error {
+				got, isolated = FromContext(c.Request().Context())
+				caller, _ = identity.FromContext(c.Request().Context())
+				return c.NoContent(http.StatusOK)
+			}
+			e.GET("/data", handler, Middleware(resolver, skipper))
+			e.GET("/public", handler, Middleware(resolver, skipper))
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.caller != nil {
+				req = req.WithContext(identity.WithCaller(req.Context(), *tt.caller))
+			}
+			if tt.header != "" {
+				req.Header.Set(HeaderWorkspaceID, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.code {
+				t.Fatalf("code: got %d, want %d", rec.Code, tt.code)
+			}
+			if tt.code == http.StatusOK && (got != tt.want || isolated != (tt.want != 0)) {
+				t.Errorf("workspace: got %d (%v), want %d", got, isolated, tt.want)
+			}
+			if caller.WorkspaceRole != tt.role {
+				t.Errorf("workspace role: got %q, want %q", caller.WorkspaceRole, tt.role)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrNoWorkspace — в контексте нет рабочего пространства, а запрос к данным его требует
+	ErrNoWorkspace = errors.New("workspace is not set")
+	// ErrNotMember — вызывающий не состоит в запрошенном рабочем пространстве
+	ErrNotMember = errors.New("not a member of the workspace")
+)
+
+type workspaceContextKey struct{}
+
+type allWorkspacesContextKey struct{}
+
+// WithWorkspace кладет рабочее пространство в контекст
+func WithWorkspace(ctx context.Context, workspaceID uint) context.Context {
+	return context.WithValue(ctx, workspaceContextKey{}, workspaceID)
+}
+
+// FromContext достает рабочее пространство из контекста
+func FromContext(ctx context.Context) (uint, bool) {
+	workspaceID, ok := ctx.Value(workspaceContextKey{}).(uint)
+	return workspaceID, ok && workspaceID != 0
+}
+
+// Require возвращает рабочее пространство или ErrNoWorkspace, если его нет
+func Require(ctx context.Context) (uint, error) {
+	workspaceID, ok := FromContext(ctx)
+	if !ok {
+		return 0, ErrNoWorkspace
+	}
+	return workspaceID, nil
+}
+
+// WithAllWorkspaces снимает изоляцию для запросов с этим контекстом. Нужна только
+// служебным задачам, которые обслуживают все пространства сразу, например очистке корзины.
+func WithAllWorkspaces(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allWorkspacesContextKey{}, true)
+}
+
+// allWorkspaces сообщает, снята ли изоляция для контекста
+func allWorkspaces(ctx context.Context) bool {
+	all, _ := ctx.Value(allWorkspacesContextKey{}).(bool)
+	return all
+}
+
+// Condition возвращает условие «column = пространство из контекста» для Raw
+// и Exec, которые Plugin не изолирует. Условие подставляется вместо ?:
+//
+//	db.Raw("SELECT id FROM tasks WHERE ?", cond)
+//
+// При снятой изоляции условие всегда истинно. Без пространства в контексте
+// возвращает ErrNoWorkspace.
+func Condition(ctx context.Context, column string) (clause.Expression, error) {
+	if allWorkspaces(ctx) {
+		return clause.Expr{SQL: "1 = 1"}, nil
+	}
+	workspaceID, err := Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return clause.Expr{SQL: column + " = ?", Vars: []any{workspaceID}}, nil
+}
@@ -1,18 +1,27 @@
 package userService
 
 import (
+	"newproject/internal/auditService"
 	"newproject/internal/taskService"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Структура для User в GORM
 type User struct {
 	ID        uint               `json:"id" gorm:"primaryKey"`
-	Email     string             `json:"email" gorm:"unique;not null"`
-	Password  string             `json:"password" gorm:"not null"`
+	Email     string             `json:"email" gorm:"not null;uniqueIndex:idx_users_email,where:deleted_at IS NULL"`
+	Password  string             `json:"-" gorm:"not null"`
 	Name      string             `json:"name" gorm:"not null"`
-	DeletedAt *time.Time         `json:"deleted_at,omitempty"`
+	Role      string             `json:"role" gorm:"not null;default:user"`
+	DeletedAt gorm.DeletedAt     `json:"deleted_at" gorm:"index"`
 	CreatedAt time.Time          `json:"created_at"`
 	UpdatedAt time.Time          `json:"updated_at"`
 	Tasks     []taskService.Task `json:"tasks" gorm:"foreignKey:UserID"`
 }
+
+// AuditEntity — имя пользователя в журнале аудита
+func (User) AuditEntity() string {
+	return auditService.EntityUser
+}
@@ -0,0 +1,64 @@
+package userService
+
+import (
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// passwordCost — текущая стоимость bcrypt. Если её поменять, хеши
+// существующих пользователей пересчитаются при следующем успешном входе.
+const passwordCost = 12
+
+// ErrInvalidCredentials возвращается при неверной паре email/пароль
+var ErrInvalidCredentials = errors.New("invalid email or password")
+
+var (
+	dummyHash     []byte
+	dummyHashOnce sync.Once
+)
+
+// hashPassword хеширует пароль с помощью bcrypt
+func hashPassword(password string) (string, error) {
+	if password == "" {
+		return "", errors.New("password is required")
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), passwordCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// checkPassword сверяет пароль с сохраненным значением и сообщает,
+// нужно ли пересчитать хеш (устаревшая стоимость или пароль в открытом виде)
+func checkPassword(stored, password string) (ok bool, needsRehash bool) {
+	if !isBcryptHash(stored) {
+		// Пароли, сохраненные до перехода на bcrypt, лежат в открытом виде
+		ok = subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+		return ok, ok
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(stored), []byte(password)); err != nil {
+		return false, false
+	}
+
+	cost, err := bcrypt.Cost([]byte(stored))
+	return true, err != nil || cost != passwordCost
+}
+
+// burnPasswordCheck выполняет сравнение с фиктивным хешем, чтобы время ответа
+// для несуществующего email не отличалось от неверного пароля
+func burnPasswordCheck(password string) {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), passwordCost)
+	})
+	_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+}
+
+func isBcryptHash(s string) bool {
+	return strings.HasPrefix(s, "$2a$") || strings.HasPrefix(s, "$2b$") || strings.HasPrefix(s, "$2y$")
+}
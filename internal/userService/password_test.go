@@ -0,0 +1,132 @@
+package userService
+
+import (
+	"context"
+	"errors"
+	"newproject/internal/models"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testUser — запрос на регистрацию с паролем secret
+func testUser(email string) models.User {
+	return models.User{Email: email, Password: "secret"}
+}
+
+func TestHashPassword(t *testing.T) {
+	hash, err := hashPassword("secret")
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	if !isBcryptHash(hash) {
+		t.Fatalf("hash: got %q, want a bcrypt hash", hash)
+	}
+	if cost, err := bcrypt.Cost([]byte(hash)); err != nil || cost != passwordCost {
+		t.Errorf("cost: got %d, %v, want %d", cost, err, passwordCost)
+	}
+	if _, err := hashPassword(""); err == nil {
+		t.Error("empty password: got no error")
+	}
+}
+
+func TestCheckPassword(t *testing.T) {
+	current, err := bcrypt.GenerateFromPassword([]byte("secret"), passwordCost)
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	cheap, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		stored     string
+		password   string
+		wantOK     bool
+		wantRehash bool
+	}{
+		{name: "current hash", stored: string(current), password: "secret", wantOK: true},
+		{name: "wrong password", stored: string(current), password: "guess"},
+		{name: "outdated cost", stored: string(cheap), password: "secret", wantOK: true, wantRehash: true},
+		{name: "wrong password with outdated cost", stored: string(cheap), password: "guess"},
+		{name: "legacy plaintext", stored: "secret", password: "secret", wantOK: true, wantRehash: true},
+		{name: "wrong legacy plaintext", stored: "secret", password: "guess"},
+		{name: "hash sent as password", stored: string(current), password: string(current)},
+		{name: "empty password", stored: string(current), password: ""},
+	}
+	for _, tt := range tests {
+		ok, rehash := checkPassword(tt.stored, tt.password)
+		if ok != tt.wantOK || rehash != tt.wantRehash {
+			t.Errorf("%s: got ok=%v rehash=%v, want ok=%v rehash=%v", tt.name, ok, rehash, tt.wantOK, tt.wantRehash)
+		}
+	}
+}
+
+func TestVerifyPasswordUpgradesStoredHash(t *testing.T) {
+	cheap, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		stored string
+	}{
+		{name: "legacy plaintext", stored: "secret"},
+		{name: "outdated cost", stored: string(cheap)},
+	}
+	for _, tt := range tests {
+		db := openTestDB(t)
+		s := NewUserService(NewUserRepository(db), nil)
+		ctx := context.Background()
+
+		user, err := s.CreateUser(ctx, testUser("a@x"))
+		if err != nil {
+			t.Fatalf("%s: create: %v", tt.name, err)
+		}
+		db.Model(&User{}).Where("id = ?", user.ID).Update("password", tt.stored)
+
+		if _, err := s.VerifyPassword(ctx, "a@x", "secret"); err != nil {
+			t.Fatalf("%s: verify: %v", tt.name, err)
+		}
+		var stored string
+		db.Model(&User{}).Where("id = ?", user.ID).Pluck("password", &stored)
+		if cost, err := bcrypt.Cost([]byte(stored)); err != nil || cost != passwordCost {
+			t.Errorf("%s: stored password: got cost %d, %v, want a hash with cost %d", tt.name, cost, err, passwordCost)
+		}
+		if _, err := s.VerifyPassword(ctx, "a@x", "secret"); err != nil {
+			t.Errorf("%s: verify after upgrade: %v", tt.name, err)
+		}
+		// После пересчета хеш сам по себе паролем не является
+		if _, err := s.VerifyPassword(ctx, "a@x", stored); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("%s: verify with the hash: got %v, want %v", tt.name, err, ErrInvalidCredentials)
+		}
+	}
+}
+
+func TestVerifyPasswordRejectsUnknownEmail(t *testing.T) {
+	s := NewUserService(NewUserRepository(openTestDB(t)), nil)
+	ctx := context.Background()
+	if _, err := s.CreateUser(ctx, testUser("a@x")); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	tests := []struct {
+		name, email, password string
+	}{
+		{name: "unknown email", email: "b@x", password: "secret"},
+		{name: "wrong password", email: "a@x", password: "guess"},
+	}
+	for _, tt := range tests {
+		if _, err := s.VerifyPassword(ctx, tt.email, tt.password); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, ErrInvalidCredentials)
+		}
+	}
+	// Для неизвестного email пароль сверяется с фиктивным хешем, чтобы ответ
+	// занимал столько же времени, сколько при неверном пароле
+	if cost, err := bcrypt.Cost(dummyHash); err != nil || cost != passwordCost {
+		t.Errorf("dummy hash: got cost %d, %v, want %d", cost, err, passwordCost)
+	}
+}
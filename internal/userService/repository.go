@@ -1,24 +1,42 @@
 package userService
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
+	"newproject/internal/models"
+	"newproject/internal/pagination"
 	"newproject/internal/taskService"
+	"newproject/internal/tenant"
+	"newproject/internal/workspaceService"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type UserRepository interface {
-	CreateUser(user User) (User, error)
-	GetAllUsers() ([]User, error)
-	UpdateUserByID(id uint, user User) (User, error)
-	DeleteUserByID(id uint) error
-	GetUserByID(id uint, user *User) error
-	GetTasksForUser(userID uint) ([]taskService.Task, error)
-	GetUserByEmail(email string) (*User, error)
+	CreateUser(ctx context.Context, user User) (User, error)
+	GetAllUsers(ctx context.Context, page pagination.Page) ([]User, string, error)
+	UpdateUserByID(ctx context.Context, id uint, user User) (User, error)
+	DeleteUserByID(ctx context.Context, id uint) error
+	GetUserByID(ctx context.Context, id uint, user *User) error
+	GetUserByIDAnyWorkspace(ctx context.Context, id uint, user *User) error
+	UserExists(ctx context.Context, id uint) (bool, error)
+	UserExistsAnyWorkspace(ctx context.Context, id uint) (bool, error)
+	GetTasksForUser(ctx context.Context, userID uint) ([]taskService.Task, error)
+	GetUserByEmailAnyWorkspace(ctx context.Context, email string) (*User, error)
+	UpdatePassword(ctx context.Context, id uint, passwordHash string) error
+	CountUsersByRole(ctx context.Context, role string) (int64, error)
+	GetDeletedUsers(ctx context.Context, page pagination.Page) ([]User, string, error)
+	GetDeletedUserByID(ctx context.Context, id uint, user *User) error
+	RestoreUser(ctx context.Context, id uint) error
+	PurgeUsers(ctx context.Context, before time.Time) (int64, error)
 }
 
+// signupLockKey — ключ advisory-блокировки Postgres, под которой регистрируются пользователи
+const signupLockKey = 0x75736572
+
 type userRepository struct {
 	db *gorm.DB
 }
@@ -27,31 +45,77 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
-func (r *userRepository) CreateUser(user User) (User, error) {
-	err := r.db.Create(&user).Error
+// CreateUser создает пользователя и добавляет его в рабочее пространство по умолчанию.
+// Если роль не задана, первый пользователь системы, включая удаленных, становится
+// администратором, остальные — обычными пользователями. Подсчет и вставка идут
+// в одной транзакции под блокировкой, так что одновременные первые регистрации
+// не получат роль администратора обе.
+func (r *userRepository) CreateUser(ctx context.Context, user User) (User, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockSignups(tx); err != nil {
+			return err
+		}
+		if user.Role == "" {
+			var count int64
+			if err := tx.Unscoped().Model(&User{}).Count(&count).Error; err != nil {
+				return err
+			}
+			user.Role = models.RoleUser
+			if count == 0 {
+				user.Role = models.RoleAdmin
+			}
+		}
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		return workspaceService.JoinDefaultWorkspace(tx, user.ID)
+	})
 	if err != nil {
 		log.Printf("Error creating user in DB: %v", err)
 	}
 	return user, err
 }
 
-func (r *userRepository) GetAllUsers() ([]User, error) {
+func (r *userRepository) GetAllUsers(ctx context.Context, page pagination.Page) ([]User, string, error) {
+	db, err := r.scoped(ctx)
+	if err != nil {
+		return nil, "", err
+	}
 	var users []User
-	err := r.db.Find(&users).Error
-	return users, err
+	if err := pagination.Apply(db, page).Find(&users).Error; err != nil {
+		return nil, "", err
+	}
+	users, next := pagination.Trim(users, page, func(u User) pagination.Cursor {
+		return page.CursorFor(u.CreatedAt, u.ID)
+	})
+	return users, next, nil
 }
 
-func (r *userRepository) UpdateUserByID(id uint, user User) (User, error) {
-	var existingUser User
-	err := r.db.First(&existingUser, id).Error
+func (r *userRepository) UpdateUserByID(ctx context.Context, id uint, user User) (User, error) {
+	db, err := r.scoped(ctx)
 	if err != nil {
+		return User{}, err
+	}
+	var existingUser User
+	if err := db.First(&existingUser, id).Error; err != nil {
 		return User{}, fmt.Errorf("user not found: %w", err)
 	}
 
-	existingUser.Email = user.Email
-	existingUser.Password = user.Password
+	if user.Name != "" {
+		existingUser.Name = user.Name
+	}
+	if user.Email != "" {
+		existingUser.Email = user.Email
+	}
+	// Пустой пароль означает, что его не меняют
+	if user.Password != "" {
+		existingUser.Password = user.Password
+	}
+	if user.Role != "" {
+		existingUser.Role = user.Role
+	}
 
-	err = r.db.Save(&existingUser).Error
+	err = r.db.WithContext(ctx).Save(&existingUser).Error
 	if err != nil {
 		return User{}, fmt.Errorf("error updating user: %w", err)
 	}
@@ -59,23 +123,57 @@ func (r *userRepository) UpdateUserByID(id uint, user User) (User, error) {
 	return existingUser, nil
 }
 
-func (r *userRepository) DeleteUserByID(id uint) error {
-	return r.db.Delete(&User{}, id).Error
+func (r *userRepository) DeleteUserByID(ctx context.Context, id uint) error {
+	db, err := r.scoped(ctx)
+	if err != nil {
+		return err
+	}
+	return db.Delete(&User{}, id).Error
+}
+
+func (r *userRepository) GetUserByID(ctx context.Context, id uint, user *User) error {
+	db, err := r.scoped(ctx)
+	if err != nil {
+		return err
+	}
+	return db.First(user, id).Error
 }
 
-func (r *userRepository) GetUserByID(id uint, user *User) error {
-	return r.db.First(user, id).Error
+// GetUserByIDAnyWorkspace ищет пользователя среди всех рабочих пространств.
+// Нужен только проверке токена: пространство запроса выбирается после нее.
+func (r *userRepository) GetUserByIDAnyWorkspace(ctx context.Context, id uint, user *User) error {
+	return r.db.WithContext(ctx).First(user, id).Error
 }
 
-func (r *userRepository) GetTasksForUser(userID uint) ([]taskService.Task, error) {
+func (r *userRepository) UserExists(ctx context.Context, id uint) (bool, error) {
+	db, err := r.scoped(ctx)
+	if err != nil {
+		return false, err
+	}
+	var count int64
+	err = db.Model(&User{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+// UserExistsAnyWorkspace сообщает, есть ли пользователь в системе. Нужен,
+// чтобы добавить в пространство того, кто в нем еще не состоит.
+func (r *userRepository) UserExistsAnyWorkspace(ctx context.Context, id uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *userRepository) GetTasksForUser(ctx context.Context, userID uint) ([]taskService.Task, error) {
 	var tasks []taskService.Task
-	err := r.db.Where("user_id = ?", userID).Find(&tasks).Error
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&tasks).Error
 	return tasks, err
 }
 
-func (r *userRepository) GetUserByEmail(email string) (*User, error) {
+// GetUserByEmailAnyWorkspace ищет пользователя среди всех рабочих пространств:
+// по email входят в систему, и он уникален во всей системе
+func (r *userRepository) GetUserByEmailAnyWorkspace(ctx context.Context, email string) (*User, error) {
 	var user User
-	result := r.db.Where("email = ?", email).First(&user)
+	result := r.db.WithContext(ctx).Where("email = ?", email).First(&user)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
@@ -84,3 +182,79 @@ func (r *userRepository) GetUserByEmail(email string) (*User, error) {
 	}
 	return &user, nil
 }
+
+func (r *userRepository) UpdatePassword(ctx context.Context, id uint, passwordHash string) error {
+	db, err := r.scoped(ctx)
+	if err != nil {
+		return err
+	}
+	return db.Model(&User{}).Where("id = ?", id).Update("password", passwordHash).Error
+}
+
+// CountUsersByRole считает пользователей по всей системе, роль не зависит от рабочего пространства
+func (r *userRepository) CountUsersByRole(ctx context.Context, role string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&User{}).Where("role = ?", role).Count(&count).Error
+	return count, err
+}
+
+// GetDeletedUsers возвращает страницу удаленных участников рабочего пространства
+func (r *userRepository) GetDeletedUsers(ctx context.Context, page pagination.Page) ([]User, string, error) {
+	db, err := r.scoped(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	var users []User
+	db = db.Unscoped().Where("deleted_at IS NOT NULL")
+	if err := pagination.Apply(db, page).Find(&users).Error; err != nil {
+		return nil, "", err
+	}
+	users, next := pagination.Trim(users, page, func(u User) pagination.Cursor {
+		return page.CursorFor(u.DeletedAt.Time, u.ID)
+	})
+	return users, next, nil
+}
+
+func (r *userRepository) GetDeletedUserByID(ctx context.Context, id uint, user *User) error {
+	db, err := r.scoped(ctx)
+	if err != nil {
+		return err
+	}
+	return db.Unscoped().Where("deleted_at IS NOT NULL").First(user, id).Error
+}
+
+func (r *userRepository) RestoreUser(ctx context.Context, id uint) error {
+	db, err := r.scoped(ctx)
+	if err != nil {
+		return err
+	}
+	return db.Unscoped().Model(&User{}).Where("id = ?", id).Update("deleted_at", nil).Error
+}
+
+// PurgeUsers окончательно удаляет пользователей, попавших в корзину раньше before.
+// Их задачи, проекты и членство в пространствах удаляются каскадом в базе.
+func (r *userRepository) PurgeUsers(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Unscoped().Where("deleted_at < ?", before).Delete(&User{})
+	return result.RowsAffected, result.Error
+}
+
+// scoped ограничивает выборку пользователей участниками рабочего пространства
+// из контекста. Без пространства возвращает tenant.ErrNoWorkspace: вход
+// и проверка токена идут через методы AnyWorkspace.
+func (r *userRepository) scoped(ctx context.Context) (*gorm.DB, error) {
+	member, err := tenant.Condition(ctx, "workspace_id")
+	if err != nil {
+		return nil, err
+	}
+	return r.db.WithContext(ctx).Where("users.id IN (SELECT user_id FROM workspace_members WHERE ?)", member), nil
+}
+
+// lockSignups ставит в очередь регистрации до конца транзакции tx. В Postgres
+// это advisory-блокировка; другие базы, например SQLite в тестах, и так
+// выполняют пишущие транзакции по одной.
+func lockSignups(tx *gorm.DB) error {
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+	return tx.Exec("SELECT pg_advisory_xact_lock(?)", signupLockKey).Error
+}
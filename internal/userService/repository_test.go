@@ -0,0 +1,211 @@
+package userService
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"newproject/internal/models"
+	"newproject/internal/pagination"
+	"newproject/internal/tenant"
+	"newproject/internal/workspaceService"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB открывает SQLite в файле: пишущие транзакции начинаются с
+// BEGIN IMMEDIATE и ждут друг друга, как под блокировкой в Postgres
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_txlock=immediate&_busy_timeout=5000&_foreign_keys=on"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.Use(tenant.Plugin{}); err != nil {
+		t.Fatalf("tenant plugin: %v", err)
+	}
+	if err := db.AutoMigrate(&workspaceService.Workspace{}, &User{}, &workspaceService.WorkspaceMember{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func TestFirstUserBecomesAdmin(t *testing.T) {
+	repo := NewUserRepository(openTestDB(t))
+	ctx := context.Background()
+
+	first, err := repo.CreateUser(ctx, User{Email: "first@x"})
+	if err != nil {
+		t.Fatalf("create first: %v", err)
+	}
+	second, err := repo.CreateUser(ctx, User{Email: "second@x"})
+	if err != nil {
+		t.Fatalf("create second: %v", err)
+	}
+	if first.Role != models.RoleAdmin {
+		t.Errorf("first role: got %q, want %q", first.Role, models.RoleAdmin)
+	}
+	if second.Role != models.RoleUser {
+		t.Errorf("second role: got %q, want %q", second.Role, models.RoleUser)
+	}
+}
+
+func TestConcurrentSignupsCreateOneAdmin(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewUserRepository(db)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := repo.CreateUser(context.Background(), User{Email: fmt.Sprintf("u%d@x", i)}); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("create: %v", err)
+	}
+
+	var admins int64
+	db.Model(&User{}).Where("role = ?", models.RoleAdmin).Count(&admins)
+	if admins != 1 {
+		t.Errorf("admins: got %d, want 1", admins)
+	}
+}
+
+func TestSignupCannotChooseRole(t *testing.T) {
+	s := NewUserService(NewUserRepository(openTestDB(t)), nil)
+	ctx := context.Background()
+
+	if _, err := s.CreateUser(ctx, models.User{Email: "first@x", Password: "secret"}); err != nil {
+		t.Fatalf("create first: %v", err)
+	}
+	boss, err := s.CreateUser(ctx, models.User{Email: "boss@x", Password: "secret", Role: models.RoleAdmin})
+	if err != nil {
+		t.Fatalf("create boss: %v", err)
+	}
+	if boss.Role != models.RoleUser {
+		t.Errorf("boss role: got %q, want %q", boss.Role, models.RoleUser)
+	}
+}
+
+func TestUpdateUserEmailMustBeUnique(t *testing.T) {
+	db := openTestDB(t)
+	s := NewUserService(NewUserRepository(db), nil)
+	bg := context.Background()
+
+	a, err := s.CreateUser(bg, models.User{Email: "a@x", Password: "secret"})
+	if err != nil {
+		t.Fatalf("create a: %v", err)
+	}
+	if _, err := s.CreateUser(bg, models.User{Email: "b@x", Password: "secret"}); err != nil {
+		t.Fatalf("create b: %v", err)
+	}
+	var defaultID uint
+	db.Model(&workspaceService.Workspace{}).Where("is_default = ?", true).Pluck("id", &defaultID)
+	ctx := tenant.WithWorkspace(bg, defaultID)
+
+	tests := []struct {
+		name, email string
+		wantErr     error
+	}{
+		{name: "email of another user", email: "b@x", wantErr: ErrEmailTaken},
+		{name: "own email", email: "a@x"},
+		{name: "free email", email: "c@x"},
+	}
+	for _, tt := range tests {
+		if _, err := s.UpdateUserByID(ctx, a.ID, models.User{Email: tt.email}); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestUsersAreScopedToWorkspace(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewUserRepository(db)
+	bg := context.Background()
+
+	own, err := repo.CreateUser(bg, User{Email: "own@x", Password: "old"})
+	if err != nil {
+		t.Fatalf("create own: %v", err)
+	}
+	foreign, err := repo.CreateUser(bg, User{Email: "foreign@x", Password: "old"})
+	if err != nil {
+		t.Fatalf("create foreign: %v", err)
+	}
+	// foreign переезжает из пространства по умолчанию в отдельное
+	team := workspaceService.Workspace{Name: "team"}
+	db.Create(&team)
+	db.Model(&workspaceService.WorkspaceMember{}).Where("user_id = ?", foreign.ID).Update("workspace_id", team.ID)
+
+	var defaultID uint
+	db.Model(&workspaceService.Workspace{}).Where("is_default = ?", true).Pluck("id", &defaultID)
+	ctx := tenant.WithWorkspace(bg, defaultID)
+
+	if err := repo.GetUserByID(ctx, foreign.ID, &User{}); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("get foreign: got %v, want %v", err, gorm.ErrRecordNotFound)
+	}
+	if exists, err := repo.UserExists(ctx, foreign.ID); err != nil || exists {
+		t.Errorf("foreign exists: got %v, %v, want false", exists, err)
+	}
+	page := pagination.Page{Limit: 10, Order: pagination.Order{Column: pagination.Column{Name: "created_at", Kind: pagination.KindTime}}}
+	if users, _, err := repo.GetAllUsers(ctx, page); err != nil || len(users) != 1 || users[0].ID != own.ID {
+		t.Errorf("list: got %v, %v, want only %d", users, err, own.ID)
+	}
+	if _, err := repo.UpdateUserByID(ctx, foreign.ID, User{Name: "changed"}); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("update foreign: got %v, want %v", err, gorm.ErrRecordNotFound)
+	}
+	if err := repo.UpdatePassword(ctx, foreign.ID, "new"); err != nil {
+		t.Errorf("update foreign password: %v", err)
+	}
+	if err := repo.DeleteUserByID(ctx, foreign.ID); err != nil {
+		t.Errorf("delete foreign: %v", err)
+	}
+
+	var stored User
+	if err := repo.GetUserByIDAnyWorkspace(bg, foreign.ID, &stored); err != nil {
+		t.Fatalf("get foreign in any workspace: %v", err)
+	}
+	if stored.Name == "changed" || stored.Password != "old" {
+		t.Errorf("foreign: got name %q password %q, want it unchanged", stored.Name, stored.Password)
+	}
+	if exists, err := repo.UserExistsAnyWorkspace(bg, foreign.ID); err != nil || !exists {
+		t.Errorf("foreign exists in any workspace: got %v, %v, want true", exists, err)
+	}
+	if user, err := repo.GetUserByEmailAnyWorkspace(bg, "foreign@x"); err != nil || user == nil {
+		t.Errorf("get foreign by email: got %v, %v, want the user", user, err)
+	}
+}
+
+func TestUsersRequireWorkspace(t *testing.T) {
+	repo := NewUserRepository(openTestDB(t))
+	ctx := context.Background()
+	user, err := repo.CreateUser(ctx, User{Email: "a@x"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	checks := map[string]error{
+		"get":      repo.GetUserByID(ctx, user.ID, &User{}),
+		"exists":   func() error { _, err := repo.UserExists(ctx, user.ID); return err }(),
+		"update":   func() error { _, err := repo.UpdateUserByID(ctx, user.ID, User{Name: "b"}); return err }(),
+		"password": repo.UpdatePassword(ctx, user.ID, "new"),
+		"delete":   repo.DeleteUserByID(ctx, user.ID),
+		"restore":  repo.RestoreUser(ctx, user.ID),
+	}
+	for name, err := range checks {
+		if !errors.Is(err, tenant.ErrNoWorkspace) {
+			t.Errorf("%s: got %v, want %v", name, err, tenant.ErrNoWorkspace)
+		}
+	}
+}
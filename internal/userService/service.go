@@ -1,20 +1,33 @@
 package userService
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
 	"newproject/internal/models"
+	"newproject/internal/pagination"
 	"newproject/internal/taskService"
+	"newproject/internal/tenant"
 
 	"gorm.io/gorm"
 )
 
+var (
+	// ErrInvalidRole возвращается для неизвестной роли
+	ErrInvalidRole = errors.New("invalid role")
+	// ErrEmailTaken — email уже занят другим пользователем
+	ErrEmailTaken = errors.New("email is taken by another user")
+)
+
 type UserService struct {
 	repo        UserRepository
 	taskService *taskService.TaskService
 }
 
+// NewUserService создает сервис пользователей. Роль администратора при
+// регистрации получает только первый пользователь, остальным ее выдают
+// администраторы или команда cmd/grant-admin.
 func NewUserService(repo UserRepository, taskService *taskService.TaskService) *UserService {
 	return &UserService{
 		repo:        repo,
@@ -23,8 +36,8 @@ func NewUserService(repo UserRepository, taskService *taskService.TaskService) *
 }
 
 // CreateUser создает нового пользователя
-func (s *UserService) CreateUser(user models.User) (models.User, error) {
-	existingUser, err := s.repo.GetUserByEmail(user.Email)
+func (s *UserService) CreateUser(ctx context.Context, user models.User) (models.User, error) {
+	existingUser, err := s.repo.GetUserByEmailAnyWorkspace(ctx, user.Email)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		log.Printf("Error checking user existence: %v", err)
 		return models.User{}, fmt.Errorf("error checking user existence: %w", err)
@@ -35,7 +48,15 @@ func (s *UserService) CreateUser(user models.User) (models.User, error) {
 	}
 
 	userForRepo := toUserRepo(user)
-	createdUser, err := s.repo.CreateUser(userForRepo)
+	userForRepo.Password, err = hashPassword(user.Password)
+	if err != nil {
+		return models.User{}, fmt.Errorf("error hashing password: %w", err)
+	}
+
+	// Роль выбирает репозиторий в той же транзакции, что и вставку
+	userForRepo.Role = ""
+
+	createdUser, err := s.repo.CreateUser(ctx, userForRepo)
 	if err != nil {
 		log.Printf("Error creating user in repository: %v", err)
 		return models.User{}, fmt.Errorf("error creating user in repository: %w", err)
@@ -43,11 +64,11 @@ func (s *UserService) CreateUser(user models.User) (models.User, error) {
 	return toUserModel(createdUser), nil
 }
 
-// GetAllUsers возвращает всех пользователей
-func (s *UserService) GetAllUsers() ([]models.User, error) {
-	users, err := s.repo.GetAllUsers()
+// GetAllUsers возвращает страницу пользователей и курсор следующей страницы
+func (s *UserService) GetAllUsers(ctx context.Context, page pagination.Page) ([]models.User, string, error) {
+	users, next, err := s.repo.GetAllUsers(ctx, page)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching users: %w", err)
+		return nil, "", fmt.Errorf("error fetching users: %w", err)
 	}
 
 	modelUsers := make([]models.User, len(users))
@@ -55,31 +76,173 @@ func (s *UserService) GetAllUsers() ([]models.User, error) {
 		modelUsers[i] = toUserModel(u)
 	}
 
-	return modelUsers, nil
+	return modelUsers, next, nil
 }
 
-// DeleteUserByID удаляет пользователя по ID
-func (s *UserService) DeleteUserByID(id uint) error {
-	return s.repo.DeleteUserByID(id)
+// GetUserByID возвращает пользователя по ID
+func (s *UserService) GetUserByID(ctx context.Context, id uint) (models.User, error) {
+	var user User
+	if err := s.repo.GetUserByID(ctx, id, &user); err != nil {
+		return models.User{}, err
+	}
+	return toUserModel(user), nil
+}
+
+// GetUserByIDAnyWorkspace возвращает пользователя по ID без учета рабочего
+// пространства. Только для проверки токенов, которая идет до выбора пространства.
+func (s *UserService) GetUserByIDAnyWorkspace(ctx context.Context, id uint) (models.User, error) {
+	var user User
+	if err := s.repo.GetUserByIDAnyWorkspace(ctx, id, &user); err != nil {
+		return models.User{}, err
+	}
+	return toUserModel(user), nil
+}
+
+// DeleteUserByID удаляет учетную запись пользователя, и он пропадает из всех
+// рабочих пространств. Задачи пространства запроса, где он был исполнителем,
+// переходят к reassignTo, если он задан; из остальных чужих задач во всех
+// пространствах пользователь просто снимается.
+func (s *UserService) DeleteUserByID(ctx context.Context, id uint, reassignTo *uint) error {
+	everywhere := tenant.WithAllWorkspaces(ctx)
+	var user User
+	if err := s.repo.GetUserByID(everywhere, id, &user); err != nil {
+		return err
+	}
+	if reassignTo != nil {
+		if err := s.taskService.ReleaseUser(ctx, id, reassignTo); err != nil {
+			return err
+		}
+	}
+	if err := s.taskService.ReleaseUser(everywhere, id, nil); err != nil {
+		return err
+	}
+	return s.repo.DeleteUserByID(everywhere, id)
+}
+
+// GetDeletedUsers возвращает страницу удаленных пользователей
+func (s *UserService) GetDeletedUsers(ctx context.Context, page pagination.Page) ([]User, string, error) {
+	users, next, err := s.repo.GetDeletedUsers(ctx, page)
+	if err != nil {
+		return nil, "", fmt.Errorf("error fetching deleted users: %w", err)
+	}
+	return users, next, nil
+}
+
+// RestoreUser возвращает пользователя из корзины. Снятые при удалении
+// назначения на чужие задачи не возвращаются.
+func (s *UserService) RestoreUser(ctx context.Context, id uint) (models.User, error) {
+	var user User
+	if err := s.repo.GetDeletedUserByID(ctx, id, &user); err != nil {
+		return models.User{}, err
+	}
+
+	existing, err := s.repo.GetUserByEmailAnyWorkspace(ctx, user.Email)
+	if err != nil {
+		return models.User{}, fmt.Errorf("error checking user existence: %w", err)
+	}
+	if existing != nil {
+		return models.User{}, ErrEmailTaken
+	}
+
+	if err := s.repo.RestoreUser(ctx, id); err != nil {
+		return models.User{}, fmt.Errorf("error restoring user: %w", err)
+	}
+	return toUserModel(user), nil
 }
 
 // UpdateUserByID обновляет пользователя по ID
-func (s *UserService) UpdateUserByID(id uint, user models.User) (models.User, error) {
+func (s *UserService) UpdateUserByID(ctx context.Context, id uint, user models.User) (models.User, error) {
+	if user.Role != "" && !isValidRole(user.Role) {
+		return models.User{}, ErrInvalidRole
+	}
+
 	userForRepo := toUserRepo(user)
-	updatedUser, err := s.repo.UpdateUserByID(id, userForRepo)
+	if user.Password != "" {
+		hash, err := hashPassword(user.Password)
+		if err != nil {
+			return models.User{}, fmt.Errorf("error hashing password: %w", err)
+		}
+		userForRepo.Password = hash
+	}
+	// email уникален во всей системе, как при регистрации и восстановлении
+	if user.Email != "" {
+		existing, err := s.repo.GetUserByEmailAnyWorkspace(ctx, user.Email)
+		if err != nil {
+			return models.User{}, fmt.Errorf("error checking user existence: %w", err)
+		}
+		if existing != nil && existing.ID != id {
+			return models.User{}, ErrEmailTaken
+		}
+	}
+
+	updatedUser, err := s.repo.UpdateUserByID(ctx, id, userForRepo)
 	if err != nil {
 		return models.User{}, fmt.Errorf("error updating user: %w", err)
 	}
 	return toUserModel(updatedUser), nil
 }
 
+// VerifyPassword проверяет email и пароль пользователя. Если хеш был
+// посчитан с устаревшими параметрами, он прозрачно пересчитывается.
+func (s *UserService) VerifyPassword(ctx context.Context, email, password string) (models.User, error) {
+	user, err := s.repo.GetUserByEmailAnyWorkspace(ctx, email)
+	if err != nil {
+		return models.User{}, fmt.Errorf("error fetching user: %w", err)
+	}
+	if user == nil {
+		burnPasswordCheck(password)
+		return models.User{}, ErrInvalidCredentials
+	}
+
+	ok, needsRehash := checkPassword(user.Password, password)
+	if !ok {
+		return models.User{}, ErrInvalidCredentials
+	}
+
+	if needsRehash {
+		hash, err := hashPassword(password)
+		if err == nil {
+			// Вход идет до выбора рабочего пространства
+			err = s.repo.UpdatePassword(tenant.WithAllWorkspaces(ctx), user.ID, hash)
+		}
+		if err != nil {
+			// Вход не блокируем: хеш пересчитается при следующей попытке
+			log.Printf("Error rehashing password for user %d: %v", user.ID, err)
+		}
+	}
+
+	return toUserModel(*user), nil
+}
+
 // GetUserTasks возвращает задачи пользователя
-func (s *UserService) GetUserTasks(userID uint) ([]taskService.Task, error) {
-	tasks, err := s.taskService.GetTasksByUserID(userID)
+func (s *UserService) GetUserTasks(ctx context.Context, userID uint, page pagination.Page) ([]taskService.Task, string, error) {
+	tasks, next, err := s.taskService.GetTasksByUserID(ctx, userID, page)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching user tasks: %w", err)
+		return nil, "", fmt.Errorf("error fetching user tasks: %w", err)
 	}
-	return tasks, nil
+	return tasks, next, nil
+}
+
+// GetUserAssignedTasks возвращает задачи, где пользователь участвует в роли role
+func (s *UserService) GetUserAssignedTasks(ctx context.Context, userID uint, role string, page pagination.Page) ([]taskService.Task, string, error) {
+	tasks, next, err := s.taskService.GetAssignedTasks(ctx, userID, role, page)
+	if err != nil {
+		return nil, "", fmt.Errorf("error fetching assigned tasks: %w", err)
+	}
+	return tasks, next, nil
+}
+
+// GetUserTaskTree возвращает задачи пользователя деревом подзадач
+func (s *UserService) GetUserTaskTree(ctx context.Context, userID uint, page pagination.Page) ([]taskService.TaskNode, string, error) {
+	nodes, next, err := s.taskService.GetTaskTreeByUserID(ctx, userID, page)
+	if err != nil {
+		return nil, "", fmt.Errorf("error fetching user task tree: %w", err)
+	}
+	return nodes, next, nil
+}
+
+func isValidRole(role string) bool {
+	return role == models.RoleUser || role == models.RoleAdmin
 }
 
 // Преобразование из models.User в User
@@ -89,15 +252,16 @@ func toUserRepo(u models.User) User {
 		Name:     u.Name,
 		Email:    u.Email,
 		Password: u.Password,
+		Role:     u.Role,
 	}
 }
 
-// Преобразование из User в models.User
+// Преобразование из User в models.User. Хеш пароля наружу не отдается.
 func toUserModel(u User) models.User {
 	return models.User{
-		ID:       u.ID,
-		Name:     u.Name,
-		Email:    u.Email,
-		Password: u.Password,
+		ID:    u.ID,
+		Name:  u.Name,
+		Email: u.Email,
+		Role:  u.Role,
 	}
 }
@@ -0,0 +1,129 @@
+package userService
+
+import (
+	"context"
+	"errors"
+	"newproject/internal/models"
+	"newproject/internal/pagination"
+	"newproject/internal/tenant"
+	"newproject/internal/workspaceService"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestDeletedUsersListingAndRestore(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewUserRepository(db)
+	s := NewUserService(repo, nil)
+	bg := context.Background()
+
+	var users []models.User
+	for _, email := range []string{"a@x", "b@x", "c@x"} {
+		user, err := s.CreateUser(bg, models.User{Email: email, Password: "secret"})
+		if err != nil {
+			t.Fatalf("create %s: %v", email, err)
+		}
+		users = append(users, user)
+	}
+	var defaultID uint
+	db.Model(&workspaceService.Workspace{}).Where("is_default = ?", true).Pluck("id", &defaultID)
+	ctx := tenant.WithWorkspace(bg, defaultID)
+	for _, user := range users[1:] {
+		if err := repo.DeleteUserByID(ctx, user.ID); err != nil {
+			t.Fatalf("delete %s: %v", user.Email, err)
+		}
+	}
+
+	page := pagination.Page{Limit: 10, Order: pagination.ByDeletedAt}
+	deleted, _, err := s.GetDeletedUsers(ctx, page)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(deleted) != 2 || deleted[0].ID != users[2].ID || deleted[1].ID != users[1].ID {
+		t.Errorf("list: got %v, want users %d and %d, deleted last first", deleted, users[2].ID, users[1].ID)
+	}
+	if other, _, err := s.GetDeletedUsers(tenant.WithWorkspace(bg, defaultID+1), page); err != nil || len(other) != 0 {
+		t.Errorf("list in another workspace: got %v, %v, want none", other, err)
+	}
+
+	// Email удаленного b@x успели занять
+	if _, err := s.CreateUser(bg, models.User{Email: "b@x", Password: "secret"}); err != nil {
+		t.Fatalf("create second b@x: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		id      uint
+		wantErr error
+	}{
+		{name: "email taken", id: users[1].ID, wantErr: ErrEmailTaken},
+		{name: "not deleted", id: users[0].ID, wantErr: gorm.ErrRecordNotFound},
+		{name: "unknown", id: 999, wantErr: gorm.ErrRecordNotFound},
+		{name: "restore", id: users[2].ID},
+		{name: "restore twice", id: users[2].ID, wantErr: gorm.ErrRecordNotFound},
+	}
+	for _, tt := range tests {
+		if _, err := s.RestoreUser(ctx, tt.id); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+	if err := repo.GetUserByID(ctx, users[2].ID, &User{}); err != nil {
+		t.Errorf("restored user: %v", err)
+	}
+	if err := repo.GetDeletedUserByID(ctx, users[1].ID, &User{}); err != nil {
+		t.Errorf("user with a taken email stays in the trash: %v", err)
+	}
+}
+
+func TestPurgeUsers(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewUserRepository(db)
+	bg := context.Background()
+	before := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		email      string
+		deletedAt  *time.Time
+		wantPurged bool
+	}{
+		{email: "long-ago@x", deletedAt: ptr(before.Add(-time.Hour)), wantPurged: true},
+		{email: "just-before@x", deletedAt: ptr(before.Add(-time.Second)), wantPurged: true},
+		{email: "boundary@x", deletedAt: &before},
+		{email: "after@x", deletedAt: ptr(before.Add(time.Second))},
+		{email: "alive@x"},
+	}
+	ids := make([]uint, len(tests))
+	for i, tt := range tests {
+		user, err := repo.CreateUser(bg, User{Email: tt.email})
+		if err != nil {
+			t.Fatalf("%s: create: %v", tt.email, err)
+		}
+		if tt.deletedAt != nil {
+			if err := db.Unscoped().Model(&User{}).Where("id = ?", user.ID).Update("deleted_at", *tt.deletedAt).Error; err != nil {
+				t.Fatalf("%s: delete: %v", tt.email, err)
+			}
+		}
+		ids[i] = user.ID
+	}
+
+	purged, err := repo.PurgeUsers(bg, before)
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if purged != 2 {
+		t.Errorf("purged: got %d, want 2", purged)
+	}
+	for i, tt := range tests {
+		var count int64
+		db.Unscoped().Model(&User{}).Where("id = ?", ids[i]).Count(&count)
+		if (count == 0) != tt.wantPurged {
+			t.Errorf("%s: got purged %v, want %v", tt.email, count == 0, tt.wantPurged)
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -2,8 +2,10 @@ package tasks
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/oapi-codegen/runtime"
@@ -16,9 +18,21 @@ type Error struct {
 
 // NewTaskRequest defines model for NewTaskRequest.
 type NewTaskRequest struct {
-	IsDone *bool   `json:"is_done,omitempty"`
-	Task   *string `json:"task,omitempty"`
-	UserId *int64  `json:"user_id,omitempty"`
+	// DueAt Deadline of the task
+	DueAt    *time.Time    `json:"due_at,omitempty"`
+	IsDone   *bool         `json:"is_done,omitempty"`
+	Priority *TaskPriority `json:"priority,omitempty"`
+
+	// ProjectId Project to put the task into
+	ProjectId *int64 `json:"project_id,omitempty"`
+
+	// Recurrence iCalendar RRULE that makes the task recurring
+	Recurrence *string `json:"recurrence,omitempty"`
+
+	// StatusId Board status of the task, derived from is_done when absent
+	StatusId *int64  `json:"status_id,omitempty"`
+	Task     *string `json:"task,omitempty"`
+	UserId   *int64  `json:"user_id,omitempty"`
 }
 
 // NewUserRequest defines model for NewUserRequest.
@@ -31,13 +45,35 @@ type NewUserRequest struct {
 type StrictMiddlewareFunc func(f echo.HandlerFunc) echo.HandlerFunc
 
 type StrictHandler interface {
-	GetTasks(ctx context.Context) ([]Task, error)
-	GetTasksByUserID(ctx context.Context, userID int64) ([]Task, error)
+	GetTasks(ctx context.Context, params GetTasksParams) (TaskPage, error)
+	GetTasksByUserID(ctx context.Context, userID int64, params GetTasksParams) (TaskPage, error)
+	GetTasksSearch(ctx context.Context, params GetTasksSearchParams) (TaskSearchResults, error)
 	PostTasks(ctx context.Context, req NewTaskRequest) (Task, error)
 	DeleteTasksId(ctx context.Context, id int64) error
-	PatchTasksId(ctx context.Context, id int64, req PatchTasksIdJSONRequestBody) (Task, error)
-	GetUsers(ctx context.Context) ([]User, error)                    // Добавлено
-	PostUsers(ctx context.Context, req NewUserRequest) (User, error) // Добавлено
+	PatchTasksId(ctx context.Context, id int64, params PatchTasksIdParams, req PatchTasksIdJSONRequestBody) (Task, error)
+	GetTasksIdSubtasks(ctx context.Context, id int64, params GetTasksIdSubtasksParams) (TaskPage, error)
+	PostTasksIdSubtasks(ctx context.Context, id int64, req NewTaskRequest) (Task, error)
+	GetTasksIdActivity(ctx context.Context, id int64, params GetTasksIdActivityParams) (TaskEventPage, error)
+	GetTasksIdDependencies(ctx context.Context, id int64) (TaskDependencies, error)
+	PostTasksIdDependencies(ctx context.Context, id int64, req NewDependencyRequest) (TaskDependencies, error)
+	DeleteTasksIdDependenciesBlockerId(ctx context.Context, id int64, blockerId int64) error
+	PutTasksIdTagsTagId(ctx context.Context, id int64, tagId int64) (Task, error)
+	DeleteTasksIdTagsTagId(ctx context.Context, id int64, tagId int64) error
+	GetTags(ctx context.Context) (TagList, error)
+	PostTags(ctx context.Context, req NewTagRequest) (Tag, error)
+	DeleteTagsId(ctx context.Context, id int64) error
+	PutTasksIdAssigneesUserId(ctx context.Context, id int64, userId int64) (Task, error)
+	DeleteTasksIdAssigneesUserId(ctx context.Context, id int64, userId int64) error
+	PutTasksIdWatchersUserId(ctx context.Context, id int64, userId int64) (Task, error)
+	DeleteTasksIdWatchersUserId(ctx context.Context, id int64, userId int64) error
+	PatchTasksIdStatus(ctx context.Context, id int64, req TaskStatusChangeRequest) (Task, error)
+	GetStatuses(ctx context.Context) (TaskStatusList, error)
+	PostStatuses(ctx context.Context, req NewTaskStatusRequest) (TaskStatus, error)
+	PutStatusesOrder(ctx context.Context, req StatusOrderRequest) error
+	PatchStatusesId(ctx context.Context, id int64, req UpdateTaskStatusRequest) (TaskStatus, error)
+	DeleteStatusesId(ctx context.Context, id int64) error
+	GetUsers(ctx context.Context, params GetUsersParams) (UserPage, error) // Добавлено
+	PostUsers(ctx context.Context, req NewUserRequest) (User, error)       // Добавлено
 }
 
 func NewStrictHandler(handler StrictHandler, middlewares []StrictMiddlewareFunc) ServerInterface {
@@ -49,14 +85,22 @@ type strictHandler struct {
 	middlewares []StrictMiddlewareFunc
 }
 
-func (sh *strictHandler) GetTasks(ctx echo.Context) error {
-	tasks, err := sh.handler.GetTasks(ctx.Request().Context())
+func (sh *strictHandler) GetTasks(ctx echo.Context, params GetTasksParams) error {
+	tasks, err := sh.handler.GetTasks(ctx.Request().Context(), params)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return toHTTPError(err)
 	}
 	return ctx.JSON(http.StatusOK, tasks)
 }
 
+func (sh *strictHandler) GetTasksSearch(ctx echo.Context, params GetTasksSearchParams) error {
+	results, err := sh.handler.GetTasksSearch(ctx.Request().Context(), params)
+	if err != nil {
+		return toHTTPError(err)
+	}
+	return ctx.JSON(http.StatusOK, results)
+}
+
 func (sh *strictHandler) PostTasks(ctx echo.Context) error {
 	var req NewTaskRequest
 	if err := ctx.Bind(&req); err != nil {
@@ -64,7 +108,7 @@ func (sh *strictHandler) PostTasks(ctx echo.Context) error {
 	}
 	task, err := sh.handler.PostTasks(ctx.Request().Context(), req)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return toHTTPError(err)
 	}
 	return ctx.JSON(http.StatusCreated, task)
 }
@@ -72,27 +116,223 @@ func (sh *strictHandler) PostTasks(ctx echo.Context) error {
 func (sh *strictHandler) DeleteTasksId(ctx echo.Context, id int64) error {
 	err := sh.handler.DeleteTasksId(ctx.Request().Context(), id)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return toHTTPError(err)
 	}
 	return ctx.NoContent(http.StatusNoContent)
 }
 
-func (sh *strictHandler) PatchTasksId(ctx echo.Context, id int64) error {
+func (sh *strictHandler) PatchTasksId(ctx echo.Context, id int64, params PatchTasksIdParams) error {
 	var req PatchTasksIdJSONRequestBody
 	if err := ctx.Bind(&req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
 	}
-	task, err := sh.handler.PatchTasksId(ctx.Request().Context(), id, req)
+	task, err := sh.handler.PatchTasksId(ctx.Request().Context(), id, params, req)
+	if err != nil {
+		return toHTTPError(err)
+	}
+	return ctx.JSON(http.StatusOK, task)
+}
+
+func (sh *strictHandler) GetTasksIdSubtasks(ctx echo.Context, id int64, params GetTasksIdSubtasksParams) error {
+	tasks, err := sh.handler.GetTasksIdSubtasks(ctx.Request().Context(), id, params)
+	if err != nil {
+		return toHTTPError(err)
+	}
+	return ctx.JSON(http.StatusOK, tasks)
+}
+
+func (sh *strictHandler) PostTasksIdSubtasks(ctx echo.Context, id int64) error {
+	var req NewTaskRequest
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	task, err := sh.handler.PostTasksIdSubtasks(ctx.Request().Context(), id, req)
+	if err != nil {
+		return toHTTPError(err)
+	}
+	return ctx.JSON(http.StatusCreated, task)
+}
+
+func (sh *strictHandler) GetTasksIdActivity(ctx echo.Context, id int64, params GetTasksIdActivityParams) error {
+	events, err := sh.handler.GetTasksIdActivity(ctx.Request().Context(), id, params)
+	if err != nil {
+		return toHTTPError(err)
+	}
+	return ctx.JSON(http.StatusOK, events)
+}
+
+func (sh *strictHandler) GetTasksIdDependencies(ctx echo.Context, id int64) error {
+	deps, err := sh.handler.GetTasksIdDependencies(ctx.Request().Context(), id)
+	if err != nil {
+		return toHTTPError(err)
+	}
+	return ctx.JSON(http.StatusOK, deps)
+}
+
+func (sh *strictHandler) PostTasksIdDependencies(ctx echo.Context, id int64) error {
+	var req NewDependencyRequest
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	deps, err := sh.handler.PostTasksIdDependencies(ctx.Request().Context(), id, req)
+	if err != nil {
+		return toHTTPError(err)
+	}
+	return ctx.JSON(http.StatusCreated, deps)
+}
+
+func (sh *strictHandler) DeleteTasksIdDependenciesBlockerId(ctx echo.Context, id int64, blockerId int64) error {
+	err := sh.handler.DeleteTasksIdDependenciesBlockerId(ctx.Request().Context(), id, blockerId)
+	if err != nil {
+		return toHTTPError(err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (sh *strictHandler) PutTasksIdTagsTagId(ctx echo.Context, id int64, tagId int64) error {
+	task, err := sh.handler.PutTasksIdTagsTagId(ctx.Request().Context(), id, tagId)
+	if err != nil {
+		return toHTTPError(err)
+	}
+	return ctx.JSON(http.StatusOK, task)
+}
+
+func (sh *strictHandler) DeleteTasksIdTagsTagId(ctx echo.Context, id int64, tagId int64) error {
+	err := sh.handler.DeleteTasksIdTagsTagId(ctx.Request().Context(), id, tagId)
+	if err != nil {
+		return toHTTPError(err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (sh *strictHandler) GetTags(ctx echo.Context) error {
+	tags, err := sh.handler.GetTags(ctx.Request().Context())
+	if err != nil {
+		return toHTTPError(err)
+	}
+	return ctx.JSON(http.StatusOK, tags)
+}
+
+func (sh *strictHandler) PostTags(ctx echo.Context) error {
+	var req NewTagRequest
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	tag, err := sh.handler.PostTags(ctx.Request().Context(), req)
+	if err != nil {
+		return toHTTPError(err)
+	}
+	return ctx.JSON(http.StatusCreated, tag)
+}
+
+func (sh *strictHandler) DeleteTagsId(ctx echo.Context, id int64) error {
+	err := sh.handler.DeleteTagsId(ctx.Request().Context(), id)
+	if err != nil {
+		return toHTTPError(err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (sh *strictHandler) PutTasksIdAssigneesUserId(ctx echo.Context, id int64, userId int64) error {
+	task, err := sh.handler.PutTasksIdAssigneesUserId(ctx.Request().Context(), id, userId)
+	if err != nil {
+		return toHTTPError(err)
+	}
+	return ctx.JSON(http.StatusOK, task)
+}
+
+func (sh *strictHandler) DeleteTasksIdAssigneesUserId(ctx echo.Context, id int64, userId int64) error {
+	err := sh.handler.DeleteTasksIdAssigneesUserId(ctx.Request().Context(), id, userId)
+	if err != nil {
+		return toHTTPError(err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (sh *strictHandler) PutTasksIdWatchersUserId(ctx echo.Context, id int64, userId int64) error {
+	task, err := sh.handler.PutTasksIdWatchersUserId(ctx.Request().Context(), id, userId)
+	if err != nil {
+		return toHTTPError(err)
+	}
+	return ctx.JSON(http.StatusOK, task)
+}
+
+func (sh *strictHandler) DeleteTasksIdWatchersUserId(ctx echo.Context, id int64, userId int64) error {
+	err := sh.handler.DeleteTasksIdWatchersUserId(ctx.Request().Context(), id, userId)
+	if err != nil {
+		return toHTTPError(err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (sh *strictHandler) PatchTasksIdStatus(ctx echo.Context, id int64) error {
+	var req TaskStatusChangeRequest
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	task, err := sh.handler.PatchTasksIdStatus(ctx.Request().Context(), id, req)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return toHTTPError(err)
 	}
 	return ctx.JSON(http.StatusOK, task)
 }
 
-func (sh *strictHandler) GetUsers(ctx echo.Context) error {
-	users, err := sh.handler.GetUsers(ctx.Request().Context())
+func (sh *strictHandler) GetStatuses(ctx echo.Context) error {
+	statuses, err := sh.handler.GetStatuses(ctx.Request().Context())
+	if err != nil {
+		return toHTTPError(err)
+	}
+	return ctx.JSON(http.StatusOK, statuses)
+}
+
+func (sh *strictHandler) PostStatuses(ctx echo.Context) error {
+	var req NewTaskStatusRequest
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	status, err := sh.handler.PostStatuses(ctx.Request().Context(), req)
+	if err != nil {
+		return toHTTPError(err)
+	}
+	return ctx.JSON(http.StatusCreated, status)
+}
+
+func (sh *strictHandler) PutStatusesOrder(ctx echo.Context) error {
+	var req StatusOrderRequest
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	err := sh.handler.PutStatusesOrder(ctx.Request().Context(), req)
+	if err != nil {
+		return toHTTPError(err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (sh *strictHandler) PatchStatusesId(ctx echo.Context, id int64) error {
+	var req UpdateTaskStatusRequest
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	status, err := sh.handler.PatchStatusesId(ctx.Request().Context(), id, req)
+	if err != nil {
+		return toHTTPError(err)
+	}
+	return ctx.JSON(http.StatusOK, status)
+}
+
+func (sh *strictHandler) DeleteStatusesId(ctx echo.Context, id int64) error {
+	err := sh.handler.DeleteStatusesId(ctx.Request().Context(), id)
+	if err != nil {
+		return toHTTPError(err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (sh *strictHandler) GetUsers(ctx echo.Context, params GetUsersParams) error {
+	users, err := sh.handler.GetUsers(ctx.Request().Context(), params)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return toHTTPError(err)
 	}
 	return ctx.JSON(http.StatusOK, users)
 }
@@ -104,31 +344,407 @@ func (sh *strictHandler) PostUsers(ctx echo.Context) error {
 	}
 	user, err := sh.handler.PostUsers(ctx.Request().Context(), req)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return toHTTPError(err)
 	}
 	return ctx.JSON(http.StatusCreated, user)
 }
 
+// toHTTPError keeps status codes chosen by the handler and maps everything else to 500.
+func toHTTPError(err error) error {
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+}
+
 // Task defines model for Task.
 type Task struct {
-	Id     *int64  `json:"id,omitempty"`
-	IsDone *bool   `json:"is_done,omitempty"`
-	Task   *string `json:"task,omitempty"`
-	UserId *int64  `json:"user_id,omitempty"`
+	// Assignees Assignees and watchers of the task besides its owner
+	Assignees *[]TaskAssignee `json:"assignees,omitempty"`
+
+	// CompletedAt When the task was marked done, set by the server
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+
+	// DueAt Deadline of the task
+	DueAt  *time.Time `json:"due_at,omitempty"`
+	Id     *int64     `json:"id,omitempty"`
+	IsDone *bool      `json:"is_done,omitempty"`
+
+	// ParentId Parent task, absent for top-level tasks
+	ParentId *int64 `json:"parent_id,omitempty"`
+
+	// Position Place of the task in the manual order of its project
+	Position *int          `json:"position,omitempty"`
+	Priority *TaskPriority `json:"priority,omitempty"`
+
+	// ProjectId Project of the task, absent for tasks outside projects
+	ProjectId *int64 `json:"project_id,omitempty"`
+
+	// Recurrence iCalendar RRULE of the series, absent for one-off tasks
+	Recurrence *string `json:"recurrence,omitempty"`
+
+	// SeriesId Series of a recurring task
+	SeriesId *int64 `json:"series_id,omitempty"`
+
+	// Status Board status of the task
+	Status *TaskStatusRef `json:"status,omitempty"`
+
+	// StatusId Board status of the task
+	StatusId *int64  `json:"status_id,omitempty"`
+	Tags     *[]Tag  `json:"tags,omitempty"`
+	Task     *string `json:"task,omitempty"`
+	UserId   *int64  `json:"user_id,omitempty"`
+}
+
+// TaskAssignee defines model for TaskAssignee.
+type TaskAssignee struct {
+	Role   TaskAssigneeRole `json:"role"`
+	UserId int64            `json:"user_id"`
+}
+
+// TaskAssigneeRole defines model for TaskAssignee.Role.
+type TaskAssigneeRole string
+
+// Defines values for TaskAssigneeRole.
+const (
+	TaskAssigneeRoleAssignee TaskAssigneeRole = "assignee"
+	TaskAssigneeRoleWatcher  TaskAssigneeRole = "watcher"
+)
+
+// TaskStatusRef defines model for TaskStatusRef.
+type TaskStatusRef struct {
+	Category TaskStatusCategory `json:"category"`
+	Id       int64              `json:"id"`
+	Name     string             `json:"name"`
+}
+
+// TaskStatus defines model for TaskStatus.
+type TaskStatus struct {
+	Category TaskStatusCategory `json:"category"`
+	Id       int64              `json:"id"`
+	Name     string             `json:"name"`
+
+	// Position Place of the status on the board
+	Position int `json:"position"`
+
+	// Transitions Statuses a task may move to from this one, empty allows any
+	Transitions []int64 `json:"transitions"`
+}
+
+// TaskStatusCategory defines model for TaskStatusCategory.
+type TaskStatusCategory string
+
+// Defines values for TaskStatusCategory.
+const (
+	TaskStatusCategoryDone       TaskStatusCategory = "done"
+	TaskStatusCategoryInProgress TaskStatusCategory = "in_progress"
+	TaskStatusCategoryTodo       TaskStatusCategory = "todo"
+)
+
+// TaskStatusList defines model for TaskStatusList.
+type TaskStatusList struct {
+	Items []TaskStatus `json:"items"`
+}
+
+// NewTaskStatusRequest defines model for NewTaskStatusRequest.
+type NewTaskStatusRequest struct {
+	Category TaskStatusCategory `json:"category"`
+	Name     string             `json:"name"`
+
+	// Transitions Statuses a task may move to from this one, empty allows any
+	Transitions *[]int64 `json:"transitions,omitempty"`
+}
+
+// UpdateTaskStatusRequest defines model for UpdateTaskStatusRequest.
+type UpdateTaskStatusRequest struct {
+	Category *TaskStatusCategory `json:"category,omitempty"`
+	Name     *string             `json:"name,omitempty"`
+
+	// Transitions Replaces the allowed transitions, the current ones are kept when absent
+	Transitions *[]int64 `json:"transitions,omitempty"`
+}
+
+// StatusOrderRequest defines model for StatusOrderRequest.
+type StatusOrderRequest struct {
+	// StatusIds Statuses in the new order, unlisted ones follow in their current order
+	StatusIds []int64 `json:"status_ids"`
+}
+
+// TaskStatusChangeRequest defines model for TaskStatusChangeRequest.
+type TaskStatusChangeRequest struct {
+	StatusId int64 `json:"status_id"`
+}
+
+// Tag defines model for Tag.
+type Tag struct {
+	Color string `json:"color"`
+	Id    int64  `json:"id"`
+	Name  string `json:"name"`
+}
+
+// TagList defines model for TagList.
+type TagList struct {
+	Items []Tag `json:"items"`
+}
+
+// NewTagRequest defines model for NewTagRequest.
+type NewTagRequest struct {
+	Color *string `json:"color,omitempty"`
+	Name  string  `json:"name"`
+}
+
+// NewDependencyRequest defines model for NewDependencyRequest.
+type NewDependencyRequest struct {
+	// BlockedBy ID of the task that has to be done first
+	BlockedBy int64 `json:"blocked_by"`
+}
+
+// TaskDependencies defines model for TaskDependencies.
+type TaskDependencies struct {
+	// Blockers Tasks that have to be done before this one
+	Blockers []Task `json:"blockers"`
+
+	// Dependents Tasks waiting for this one
+	Dependents []Task `json:"dependents"`
+}
+
+// TaskEvent defines model for TaskEvent.
+type TaskEvent struct {
+	// ActorId User who made the change, absent for system changes
+	ActorId   *int64    `json:"actor_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Id        int64     `json:"id"`
+
+	// NewValue New title for renamed, new owner ID for reassigned
+	NewValue *string `json:"new_value,omitempty"`
+
+	// OldValue Previous title for renamed, previous owner ID for reassigned
+	OldValue *string `json:"old_value,omitempty"`
+
+	// Summary Human-readable description of the event
+	Summary string        `json:"summary"`
+	TaskId  int64         `json:"task_id"`
+	Type    TaskEventType `json:"type"`
+}
+
+// TaskEventType defines model for TaskEvent.Type.
+type TaskEventType string
+
+// Defines values for TaskEventType.
+const (
+	TaskEventTypeCompleted  TaskEventType = "completed"
+	TaskEventTypeCreated    TaskEventType = "created"
+	TaskEventTypeReassigned TaskEventType = "reassigned"
+	TaskEventTypeRenamed    TaskEventType = "renamed"
+	TaskEventTypeReopened   TaskEventType = "reopened"
+)
+
+// TaskEventPage defines model for TaskEventPage.
+type TaskEventPage struct {
+	Items []TaskEvent `json:"items"`
+
+	// NextCursor Opaque cursor of the next page, absent on the last page
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
+// TaskPriority defines model for TaskPriority.
+type TaskPriority string
+
+// Defines values for TaskPriority.
+const (
+	TaskPriorityHigh   TaskPriority = "high"
+	TaskPriorityLow    TaskPriority = "low"
+	TaskPriorityNormal TaskPriority = "normal"
+	TaskPriorityUrgent TaskPriority = "urgent"
+)
+
+// TaskSearchResult defines model for TaskSearchResult.
+type TaskSearchResult struct {
+	// Rank Relevance of the task to the query, higher is better
+	Rank float32 `json:"rank"`
+
+	// Snippet Fragment of the task text with matches wrapped in <mark>, HTML-escaped
+	Snippet string `json:"snippet"`
+	Task    Task   `json:"task"`
 }
+
+// TaskSearchResults defines model for TaskSearchResults.
+type TaskSearchResults struct {
+	Items []TaskSearchResult `json:"items"`
+}
+
+// TaskPage defines model for TaskPage.
+type TaskPage struct {
+	Items []Task `json:"items"`
+
+	// NextCursor Opaque cursor of the next page, absent on the last page
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
 type PatchTasksIdJSONRequestBody struct {
-	Task   string `json:"task"`
-	IsDone bool   `json:"is_done"`
-	UserId int64  `json:"user_id"`
+	// Task New name, the current one is kept when absent
+	Task *string `json:"task,omitempty"`
+
+	// IsDone New completion state, the current one is kept when absent
+	IsDone *bool `json:"is_done,omitempty"`
+	UserId int64 `json:"user_id"`
+
+	// DueAt New deadline, the current one is kept when absent
+	DueAt *time.Time `json:"due_at,omitempty"`
+
+	// Priority New priority, the current one is kept when absent
+	Priority *TaskPriority `json:"priority,omitempty"`
+
+	// ParentId New parent task, 0 makes the task top-level, the current one is kept when absent
+	ParentId *int64 `json:"parent_id,omitempty"`
+
+	// ProjectId New project, 0 takes the task out of its project, the current one is kept when absent
+	ProjectId *int64 `json:"project_id,omitempty"`
+
+	// Recurrence New RRULE, an empty string stops the series, the current one is kept when absent
+	Recurrence *string `json:"recurrence,omitempty"`
 }
 
 // User defines model for User.
 type User struct {
 	Email    *string `json:"email,omitempty"`
 	Id       *int64  `json:"id,omitempty"`
+	Role     *string `json:"role,omitempty"`
 	Username *string `json:"username,omitempty"`
 }
 
+// UserPage defines model for UserPage.
+type UserPage struct {
+	Items []User `json:"items"`
+
+	// NextCursor Opaque cursor of the next page, absent on the last page
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
+// GetTasksParams defines parameters for GetTasks.
+type GetTasksParams struct {
+	// Limit Maximum number of items to return
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Value of next_cursor from the previous page
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// IsDone Only tasks with this completion state
+	IsDone *bool `form:"is_done,omitempty" json:"is_done,omitempty"`
+
+	// UserId Only tasks owned by this user
+	UserId *int64 `form:"user_id,omitempty" json:"user_id,omitempty"`
+
+	// Q Case-insensitive substring of the task text
+	Q *string `form:"q,omitempty" json:"q,omitempty"`
+
+	// Sort Sort field, prefixed with "-" for descending order
+	Sort *GetTasksParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// CreatedAfter Only tasks created at or after this time
+	CreatedAfter *time.Time `form:"created_after,omitempty" json:"created_after,omitempty"`
+
+	// CreatedBefore Only tasks created before this time
+	CreatedBefore *time.Time `form:"created_before,omitempty" json:"created_before,omitempty"`
+
+	// UpdatedAfter Only tasks updated at or after this time
+	UpdatedAfter *time.Time `form:"updated_after,omitempty" json:"updated_after,omitempty"`
+
+	// UpdatedBefore Only tasks updated before this time
+	UpdatedBefore *time.Time `form:"updated_before,omitempty" json:"updated_before,omitempty"`
+
+	// Overdue Only open tasks past their due date (true) or only the rest (false)
+	Overdue *bool `form:"overdue,omitempty" json:"overdue,omitempty"`
+
+	// ProjectId Only tasks of this project
+	ProjectId *int64 `form:"project_id,omitempty" json:"project_id,omitempty"`
+
+	// StatusId Only tasks in this board status
+	StatusId *int64 `form:"status_id,omitempty" json:"status_id,omitempty"`
+
+	// Tag Only tasks with these tags, see tag_match
+	Tag *[]int64 `form:"tag,omitempty" json:"tag,omitempty"`
+
+	// TagMatch Whether a task needs any (default) or all of the tags
+	TagMatch *GetTasksParamsTagMatch `form:"tag_match,omitempty" json:"tag_match,omitempty"`
+}
+
+// GetTasksParamsTagMatch defines parameters for GetTasks.
+type GetTasksParamsTagMatch string
+
+// Defines values for GetTasksParamsTagMatch.
+const (
+	GetTasksParamsTagMatchAll GetTasksParamsTagMatch = "all"
+	GetTasksParamsTagMatchAny GetTasksParamsTagMatch = "any"
+)
+
+// GetTasksParamsSort defines parameters for GetTasks.
+type GetTasksParamsSort string
+
+// Defines values for GetTasksParamsSort.
+const (
+	GetTasksParamsSortCreatedAt      GetTasksParamsSort = "created_at"
+	GetTasksParamsSortIsDone         GetTasksParamsSort = "is_done"
+	GetTasksParamsSortMinusCreatedAt GetTasksParamsSort = "-created_at"
+	GetTasksParamsSortMinusIsDone    GetTasksParamsSort = "-is_done"
+	GetTasksParamsSortMinusTask      GetTasksParamsSort = "-task"
+	GetTasksParamsSortMinusUpdatedAt GetTasksParamsSort = "-updated_at"
+	GetTasksParamsSortTask           GetTasksParamsSort = "task"
+	GetTasksParamsSortUpdatedAt      GetTasksParamsSort = "updated_at"
+)
+
+// GetTasksSearchParams defines parameters for GetTasksSearch.
+type GetTasksSearchParams struct {
+	// Q Full-text search query
+	Q string `form:"q" json:"q"`
+
+	// Limit Maximum number of items to return
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// GetTasksIdSubtasksParams defines parameters for GetTasksIdSubtasks.
+type GetTasksIdSubtasksParams struct {
+	// Limit Maximum number of items to return
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Value of next_cursor from the previous page
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// GetTasksIdActivityParams defines parameters for GetTasksIdActivity.
+type GetTasksIdActivityParams struct {
+	// Limit Maximum number of items to return
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Value of next_cursor from the previous page
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// PatchTasksIdParams defines parameters for PatchTasksId.
+type PatchTasksIdParams struct {
+	// Scope Which occurrences of a recurring task the change applies to
+	Scope *PatchTasksIdParamsScope `form:"scope,omitempty" json:"scope,omitempty"`
+}
+
+// PatchTasksIdParamsScope defines parameters for PatchTasksId.
+type PatchTasksIdParamsScope string
+
+// Defines values for PatchTasksIdParamsScope.
+const (
+	PatchTasksIdParamsScopeFuture PatchTasksIdParamsScope = "future"
+	PatchTasksIdParamsScopeThis   PatchTasksIdParamsScope = "this"
+)
+
+// GetUsersParams defines parameters for GetUsers.
+type GetUsersParams struct {
+	// Limit Maximum number of items to return
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Value of next_cursor from the previous page
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
 // PostTasksJSONRequestBody defines body for PostTasks for application/json ContentType.
 type PostTasksJSONRequestBody = NewTaskRequest
 
@@ -142,19 +758,85 @@ type PostUsersJSONRequestBody = NewUserRequest
 type ServerInterface interface {
 	// Get all tasks
 	// (GET /tasks)
-	GetTasks(ctx echo.Context) error
+	GetTasks(ctx echo.Context, params GetTasksParams) error
 	// Create a new task
 	// (POST /tasks)
 	PostTasks(ctx echo.Context) error
+	// Full-text search over tasks
+	// (GET /tasks/search)
+	GetTasksSearch(ctx echo.Context, params GetTasksSearchParams) error
 	// Delete a task by ID
 	// (DELETE /tasks/{id})
 	DeleteTasksId(ctx echo.Context, id int64) error
 	// Update a task by ID
 	// (PATCH /tasks/{id})
-	PatchTasksId(ctx echo.Context, id int64) error
+	PatchTasksId(ctx echo.Context, id int64, params PatchTasksIdParams) error
+	// List direct subtasks of a task
+	// (GET /tasks/{id}/subtasks)
+	GetTasksIdSubtasks(ctx echo.Context, id int64, params GetTasksIdSubtasksParams) error
+	// Create a subtask
+	// (POST /tasks/{id}/subtasks)
+	PostTasksIdSubtasks(ctx echo.Context, id int64) error
+	// Activity feed of a task
+	// (GET /tasks/{id}/activity)
+	GetTasksIdActivity(ctx echo.Context, id int64, params GetTasksIdActivityParams) error
+	// List blockers and dependents of a task
+	// (GET /tasks/{id}/dependencies)
+	GetTasksIdDependencies(ctx echo.Context, id int64) error
+	// Declare that another task blocks this one
+	// (POST /tasks/{id}/dependencies)
+	PostTasksIdDependencies(ctx echo.Context, id int64) error
+	// Remove a blocker of a task
+	// (DELETE /tasks/{id}/dependencies/{blocker_id})
+	DeleteTasksIdDependenciesBlockerId(ctx echo.Context, id int64, blockerId int64) error
+	// Attach a tag to a task
+	// (PUT /tasks/{id}/tags/{tag_id})
+	PutTasksIdTagsTagId(ctx echo.Context, id int64, tagId int64) error
+	// Detach a tag from a task
+	// (DELETE /tasks/{id}/tags/{tag_id})
+	DeleteTasksIdTagsTagId(ctx echo.Context, id int64, tagId int64) error
+	// List tags of the caller
+	// (GET /tags)
+	GetTags(ctx echo.Context) error
+	// Create a tag
+	// (POST /tags)
+	PostTags(ctx echo.Context) error
+	// Delete a tag
+	// (DELETE /tags/{id})
+	DeleteTagsId(ctx echo.Context, id int64) error
+	// Make a user an assignee of a task
+	// (PUT /tasks/{id}/assignees/{user_id})
+	PutTasksIdAssigneesUserId(ctx echo.Context, id int64, userId int64) error
+	// Remove an assignee from a task
+	// (DELETE /tasks/{id}/assignees/{user_id})
+	DeleteTasksIdAssigneesUserId(ctx echo.Context, id int64, userId int64) error
+	// Make a user a watcher of a task
+	// (PUT /tasks/{id}/watchers/{user_id})
+	PutTasksIdWatchersUserId(ctx echo.Context, id int64, userId int64) error
+	// Remove a watcher from a task
+	// (DELETE /tasks/{id}/watchers/{user_id})
+	DeleteTasksIdWatchersUserId(ctx echo.Context, id int64, userId int64) error
+	// Move a task to another board status
+	// (PATCH /tasks/{id}/status)
+	PatchTasksIdStatus(ctx echo.Context, id int64) error
+	// List board statuses of the caller
+	// (GET /statuses)
+	GetStatuses(ctx echo.Context) error
+	// Create a board status
+	// (POST /statuses)
+	PostStatuses(ctx echo.Context) error
+	// Reorder board statuses
+	// (PUT /statuses/order)
+	PutStatusesOrder(ctx echo.Context) error
+	// Update a board status
+	// (PATCH /statuses/{id})
+	PatchStatusesId(ctx echo.Context, id int64) error
+	// Delete a board status
+	// (DELETE /statuses/{id})
+	DeleteStatusesId(ctx echo.Context, id int64) error
 	// Get all users
 	// (GET /users)
-	GetUsers(ctx echo.Context) error
+	GetUsers(ctx echo.Context, params GetUsersParams) error
 	// Create a new user
 	// (POST /users)
 	PostUsers(ctx echo.Context) error
@@ -167,18 +849,153 @@ type ServerInterfaceWrapper struct {
 
 // GetTasks converts echo context to params.
 func (w *ServerInterfaceWrapper) GetTasks(ctx echo.Context) error {
-	err := w.Handler.GetTasks(ctx)
-	return err
-}
+	var err error
 
-// PostTasks converts echo context to params.
-func (w *ServerInterfaceWrapper) PostTasks(ctx echo.Context) error {
-	err := w.Handler.PostTasks(ctx)
-	return err
-}
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTasksParams
+	// ------------- Optional query parameter "limit" -------------
 
-// DeleteTasksId converts echo context to params.
-func (w *ServerInterfaceWrapper) DeleteTasksId(ctx echo.Context) error {
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// ------------- Optional query parameter "is_done" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "is_done", ctx.QueryParams(), &params.IsDone)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter is_done: %s", err))
+	}
+
+	// ------------- Optional query parameter "user_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "user_id", ctx.QueryParams(), &params.UserId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter user_id: %s", err))
+	}
+
+	// ------------- Optional query parameter "q" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "q", ctx.QueryParams(), &params.Q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter q: %s", err))
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// ------------- Optional query parameter "created_after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "created_after", ctx.QueryParams(), &params.CreatedAfter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter created_after: %s", err))
+	}
+
+	// ------------- Optional query parameter "created_before" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "created_before", ctx.QueryParams(), &params.CreatedBefore)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter created_before: %s", err))
+	}
+
+	// ------------- Optional query parameter "updated_after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "updated_after", ctx.QueryParams(), &params.UpdatedAfter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter updated_after: %s", err))
+	}
+
+	// ------------- Optional query parameter "updated_before" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "updated_before", ctx.QueryParams(), &params.UpdatedBefore)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter updated_before: %s", err))
+	}
+
+	// ------------- Optional query parameter "overdue" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "overdue", ctx.QueryParams(), &params.Overdue)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter overdue: %s", err))
+	}
+
+	// ------------- Optional query parameter "project_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "project_id", ctx.QueryParams(), &params.ProjectId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter project_id: %s", err))
+	}
+
+	// ------------- Optional query parameter "status_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status_id", ctx.QueryParams(), &params.StatusId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter status_id: %s", err))
+	}
+
+	// ------------- Optional query parameter "tag" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tag", ctx.QueryParams(), &params.Tag)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tag: %s", err))
+	}
+
+	// ------------- Optional query parameter "tag_match" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tag_match", ctx.QueryParams(), &params.TagMatch)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tag_match: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetTasks(ctx, params)
+	return err
+}
+
+// PostTasks converts echo context to params.
+func (w *ServerInterfaceWrapper) PostTasks(ctx echo.Context) error {
+	err := w.Handler.PostTasks(ctx)
+	return err
+}
+
+// GetTasksSearch converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTasksSearch(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTasksSearchParams
+	// ------------- Required query parameter "q" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "q", ctx.QueryParams(), &params.Q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter q: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetTasksSearch(ctx, params)
+	return err
+}
+
+// DeleteTasksId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteTasksId(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "id" -------------
 	var id int64
@@ -204,14 +1021,416 @@ func (w *ServerInterfaceWrapper) PatchTasksId(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
 	}
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PatchTasksIdParams
+	// ------------- Optional query parameter "scope" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "scope", ctx.QueryParams(), &params.Scope)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter scope: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PatchTasksId(ctx, id, params)
+	return err
+}
+
+// GetTasksIdSubtasks converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTasksIdSubtasks(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTasksIdSubtasksParams
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetTasksIdSubtasks(ctx, id, params)
+	return err
+}
+
+// PostTasksIdSubtasks converts echo context to params.
+func (w *ServerInterfaceWrapper) PostTasksIdSubtasks(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostTasksIdSubtasks(ctx, id)
+	return err
+}
+
+// GetTasksIdActivity converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTasksIdActivity(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTasksIdActivityParams
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetTasksIdActivity(ctx, id, params)
+	return err
+}
+
+// GetTasksIdDependencies converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTasksIdDependencies(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetTasksIdDependencies(ctx, id)
+	return err
+}
+
+// PostTasksIdDependencies converts echo context to params.
+func (w *ServerInterfaceWrapper) PostTasksIdDependencies(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.PatchTasksId(ctx, id)
+	err = w.Handler.PostTasksIdDependencies(ctx, id)
+	return err
+}
+
+// DeleteTasksIdDependenciesBlockerId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteTasksIdDependenciesBlockerId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "blocker_id" -------------
+	var blockerId int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "blocker_id", ctx.Param("blocker_id"), &blockerId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter blocker_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteTasksIdDependenciesBlockerId(ctx, id, blockerId)
+	return err
+}
+
+// PutTasksIdTagsTagId converts echo context to params.
+func (w *ServerInterfaceWrapper) PutTasksIdTagsTagId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "tag_id" -------------
+	var tagId int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tag_id", ctx.Param("tag_id"), &tagId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tag_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PutTasksIdTagsTagId(ctx, id, tagId)
+	return err
+}
+
+// DeleteTasksIdTagsTagId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteTasksIdTagsTagId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "tag_id" -------------
+	var tagId int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tag_id", ctx.Param("tag_id"), &tagId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tag_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteTasksIdTagsTagId(ctx, id, tagId)
+	return err
+}
+
+// GetTags converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTags(ctx echo.Context) error {
+	err := w.Handler.GetTags(ctx)
+	return err
+}
+
+// PostTags converts echo context to params.
+func (w *ServerInterfaceWrapper) PostTags(ctx echo.Context) error {
+	err := w.Handler.PostTags(ctx)
+	return err
+}
+
+// DeleteTagsId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteTagsId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteTagsId(ctx, id)
+	return err
+}
+
+// PutTasksIdAssigneesUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) PutTasksIdAssigneesUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "user_id" -------------
+	var userId int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "user_id", ctx.Param("user_id"), &userId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter user_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PutTasksIdAssigneesUserId(ctx, id, userId)
+	return err
+}
+
+// DeleteTasksIdAssigneesUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteTasksIdAssigneesUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "user_id" -------------
+	var userId int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "user_id", ctx.Param("user_id"), &userId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter user_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteTasksIdAssigneesUserId(ctx, id, userId)
+	return err
+}
+
+// PutTasksIdWatchersUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) PutTasksIdWatchersUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "user_id" -------------
+	var userId int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "user_id", ctx.Param("user_id"), &userId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter user_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PutTasksIdWatchersUserId(ctx, id, userId)
+	return err
+}
+
+// DeleteTasksIdWatchersUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteTasksIdWatchersUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "user_id" -------------
+	var userId int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "user_id", ctx.Param("user_id"), &userId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter user_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteTasksIdWatchersUserId(ctx, id, userId)
+	return err
+}
+
+// PatchTasksIdStatus converts echo context to params.
+func (w *ServerInterfaceWrapper) PatchTasksIdStatus(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PatchTasksIdStatus(ctx, id)
+	return err
+}
+
+// GetStatuses converts echo context to params.
+func (w *ServerInterfaceWrapper) GetStatuses(ctx echo.Context) error {
+	err := w.Handler.GetStatuses(ctx)
+	return err
+}
+
+// PostStatuses converts echo context to params.
+func (w *ServerInterfaceWrapper) PostStatuses(ctx echo.Context) error {
+	err := w.Handler.PostStatuses(ctx)
+	return err
+}
+
+// PutStatusesOrder converts echo context to params.
+func (w *ServerInterfaceWrapper) PutStatusesOrder(ctx echo.Context) error {
+	err := w.Handler.PutStatusesOrder(ctx)
+	return err
+}
+
+// PatchStatusesId converts echo context to params.
+func (w *ServerInterfaceWrapper) PatchStatusesId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PatchStatusesId(ctx, id)
+	return err
+}
+
+// DeleteStatusesId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteStatusesId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteStatusesId(ctx, id)
 	return err
 }
 
 // GetUsers converts echo context to params.
 func (w *ServerInterfaceWrapper) GetUsers(ctx echo.Context) error {
-	err := w.Handler.GetUsers(ctx)
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUsersParams
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetUsers(ctx, params)
 	return err
 }
 
@@ -251,8 +1470,30 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 
 	router.GET(baseURL+"/tasks", wrapper.GetTasks)
 	router.POST(baseURL+"/tasks", wrapper.PostTasks)
+	router.GET(baseURL+"/tasks/search", wrapper.GetTasksSearch)
 	router.DELETE(baseURL+"/tasks/:id", wrapper.DeleteTasksId)
 	router.PATCH(baseURL+"/tasks/:id", wrapper.PatchTasksId)
+	router.GET(baseURL+"/tasks/:id/subtasks", wrapper.GetTasksIdSubtasks)
+	router.POST(baseURL+"/tasks/:id/subtasks", wrapper.PostTasksIdSubtasks)
+	router.GET(baseURL+"/tasks/:id/activity", wrapper.GetTasksIdActivity)
+	router.GET(baseURL+"/tasks/:id/dependencies", wrapper.GetTasksIdDependencies)
+	router.POST(baseURL+"/tasks/:id/dependencies", wrapper.PostTasksIdDependencies)
+	router.DELETE(baseURL+"/tasks/:id/dependencies/:blocker_id", wrapper.DeleteTasksIdDependenciesBlockerId)
+	router.PUT(baseURL+"/tasks/:id/tags/:tag_id", wrapper.PutTasksIdTagsTagId)
+	router.DELETE(baseURL+"/tasks/:id/tags/:tag_id", wrapper.DeleteTasksIdTagsTagId)
+	router.GET(baseURL+"/tags", wrapper.GetTags)
+	router.POST(baseURL+"/tags", wrapper.PostTags)
+	router.DELETE(baseURL+"/tags/:id", wrapper.DeleteTagsId)
+	router.PUT(baseURL+"/tasks/:id/assignees/:user_id", wrapper.PutTasksIdAssigneesUserId)
+	router.DELETE(baseURL+"/tasks/:id/assignees/:user_id", wrapper.DeleteTasksIdAssigneesUserId)
+	router.PUT(baseURL+"/tasks/:id/watchers/:user_id", wrapper.PutTasksIdWatchersUserId)
+	router.DELETE(baseURL+"/tasks/:id/watchers/:user_id", wrapper.DeleteTasksIdWatchersUserId)
+	router.PATCH(baseURL+"/tasks/:id/status", wrapper.PatchTasksIdStatus)
+	router.GET(baseURL+"/statuses", wrapper.GetStatuses)
+	router.POST(baseURL+"/statuses", wrapper.PostStatuses)
+	router.PUT(baseURL+"/statuses/order", wrapper.PutStatusesOrder)
+	router.PATCH(baseURL+"/statuses/:id", wrapper.PatchStatusesId)
+	router.DELETE(baseURL+"/statuses/:id", wrapper.DeleteStatusesId)
 	router.GET(baseURL+"/users", wrapper.GetUsers)
 	router.POST(baseURL+"/users", wrapper.PostUsers)
 
@@ -32,7 +32,12 @@ func (s *StrictHandler) DeleteTasksId(ctx echo.Context, id int64) error {
 
 // DeleteUsersId implements ServerInterface.
 func (s *StrictHandler) DeleteUsersId(ctx echo.Context, id int) error {
-	panic("unimplemented")
+	return s.userHandler.DeleteUsersId(ctx)
+}
+
+// DeleteUsersIdAccount implements ServerInterface.
+func (s *StrictHandler) DeleteUsersIdAccount(ctx echo.Context, id int) error {
+	return s.userHandler.DeleteUsersIdAccount(ctx)
 }
 
 // GetTasks implements ServerInterface.
@@ -41,13 +46,13 @@ func (s *StrictHandler) GetTasks(ctx echo.Context) error {
 }
 
 // GetUsers implements ServerInterface.
-func (s *StrictHandler) GetUsers(ctx echo.Context) error {
-	panic("unimplemented")
+func (s *StrictHandler) GetUsers(ctx echo.Context, params GetUsersParams) error {
+	return s.userHandler.GetUsers(ctx)
 }
 
 // GetUsersIdTasks implements ServerInterface.
-func (s *StrictHandler) GetUsersIdTasks(ctx echo.Context, id int64) error {
-	panic("unimplemented")
+func (s *StrictHandler) GetUsersIdTasks(ctx echo.Context, id int64, params GetUsersIdTasksParams) error {
+	return s.userHandler.GetUsersIdTasks(ctx)
 }
 
 // PatchTasksId implements ServerInterface.
@@ -57,7 +62,7 @@ func (s *StrictHandler) PatchTasksId(ctx echo.Context, id int64) error {
 
 // PatchUsersId implements ServerInterface.
 func (s *StrictHandler) PatchUsersId(ctx echo.Context, id int) error {
-	panic("unimplemented")
+	return s.userHandler.PatchUsersId(ctx)
 }
 
 // PostTasks implements ServerInterface.
@@ -67,7 +72,7 @@ func (s *StrictHandler) PostTasks(ctx echo.Context) error {
 
 // PostUsers implements ServerInterface.
 func (s *StrictHandler) PostUsers(ctx echo.Context) error {
-	panic("unimplemented")
+	return s.userHandler.PostUsers(ctx)
 }
 
 // NewStrictHandler - создает строгий хендлер для пользователей
@@ -89,23 +94,93 @@ type NewTaskRequest struct {
 
 // Task defines model for Task.
 type Task struct {
-	Id     *int64  `json:"id,omitempty"`
-	IsDone *bool   `json:"is_done,omitempty"`
-	Task   *string `json:"task,omitempty"`
+	// Assignees Assignees and watchers of the task besides its owner
+	Assignees *[]TaskAssignee `json:"assignees,omitempty"`
+
+	// CompletedAt When the task was marked done, set by the server
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+
+	// DueAt Deadline of the task
+	DueAt    *time.Time `json:"due_at,omitempty"`
+	Id       *int64     `json:"id,omitempty"`
+	IsDone   *bool      `json:"is_done,omitempty"`
+
+	// ParentId Parent task, absent for top-level tasks
+	ParentId *int64 `json:"parent_id,omitempty"`
+
+	// Position Place of the task in the manual order of its project
+	Position *int    `json:"position,omitempty"`
+	Priority *string `json:"priority,omitempty"`
+
+	// ProjectId Project of the task, absent for tasks outside projects
+	ProjectId *int64 `json:"project_id,omitempty"`
+
+	// Recurrence iCalendar RRULE of the series, absent for one-off tasks
+	Recurrence *string `json:"recurrence,omitempty"`
+
+	// SeriesId Series of a recurring task
+	SeriesId *int64 `json:"series_id,omitempty"`
+
+	// Status Board status of the task
+	Status *TaskStatusRef `json:"status,omitempty"`
+
+	// StatusId Board status of the task
+	StatusId *int64 `json:"status_id,omitempty"`
+	Tags      *[]Tag `json:"tags,omitempty"`
+	Task     *string    `json:"task,omitempty"`
 
 	// UserId ID of the user who owns this task
 	UserId *int64 `json:"user_id,omitempty"`
 }
 
+// TaskAssignee defines model for TaskAssignee.
+type TaskAssignee struct {
+	Role   string `json:"role"`
+	UserId int64  `json:"user_id"`
+}
+
+// TaskStatusRef defines model for TaskStatusRef.
+type TaskStatusRef struct {
+	Category string `json:"category"`
+	Id       int64  `json:"id"`
+	Name     string `json:"name"`
+}
+
+// NewUserRequest defines model for NewUserRequest.
+type NewUserRequest struct {
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+// UpdateUserRequest defines model for UpdateUserRequest.
+type UpdateUserRequest struct {
+	Email    *string `json:"email,omitempty"`
+	Name     *string `json:"name,omitempty"`
+	Password *string `json:"password,omitempty"`
+
+	// Role Only admins may change roles
+	Role *UserRole `json:"role,omitempty"`
+}
+
 // User defines model for User.
 type User struct {
 	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 	Email     *string    `json:"email,omitempty"`
 	Id        *int64     `json:"id,omitempty"`
 	Name      *string    `json:"name,omitempty"`
-	Password  *string    `json:"password,omitempty"`
+	Role      *UserRole  `json:"role,omitempty"`
 }
 
+// UserRole defines model for UserRole.
+type UserRole string
+
+// Defines values for UserRole.
+const (
+	UserRoleAdmin UserRole = "admin"
+	UserRoleUser  UserRole = "user"
+)
+
 // PostTasksJSONRequestBody defines body for PostTasks for application/json ContentType.
 type PostTasksJSONRequestBody = NewTaskRequest
 
@@ -113,21 +188,77 @@ type PostTasksJSONRequestBody = NewTaskRequest
 type PatchTasksIdJSONRequestBody = Task
 
 // PostUsersJSONRequestBody defines body for PostUsers for application/json ContentType.
-type PostUsersJSONRequestBody = User
+type PostUsersJSONRequestBody = NewUserRequest
 
 // PatchUsersIdJSONRequestBody defines body for PatchUsersId for application/json ContentType.
-type PatchUsersIdJSONRequestBody = User
+type PatchUsersIdJSONRequestBody = UpdateUserRequest
 
-// GetUsersResponse defines the response model for GetUsers.
-type GetUsersResponse struct {
-	Users []User `json:"users"`
+// Tag defines model for Tag.
+type Tag struct {
+	Color string `json:"color"`
+	Id    int64  `json:"id"`
+	Name  string `json:"name"`
 }
 
-// GetUsersIdTasksResponse defines the response model for GetUsersIdTasks.
-type GetUsersIdTasksResponse struct {
-	Tasks []Task `json:"tasks"`
+// TaskPage defines model for TaskPage.
+type TaskPage struct {
+	Items []Task `json:"items"`
+
+	// NextCursor Opaque cursor of the next page, absent on the last page
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
+// UserPage defines model for UserPage.
+type UserPage struct {
+	Items []User `json:"items"`
+
+	// NextCursor Opaque cursor of the next page, absent on the last page
+	NextCursor *string `json:"next_cursor,omitempty"`
 }
 
+// GetUsersParams defines parameters for GetUsers.
+type GetUsersParams struct {
+	// Limit Maximum number of items to return
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Value of next_cursor from the previous page
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// GetUsersIdTasksParams defines parameters for GetUsersIdTasks.
+type GetUsersIdTasksParams struct {
+	// Limit Maximum number of items to return
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Value of next_cursor from the previous page
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// View Response shape: flat list or top-level tasks with nested subtasks
+	View *GetUsersIdTasksParamsView `form:"view,omitempty" json:"view,omitempty"`
+
+	// Relation Owned tasks, or tasks where the user is an assignee or a watcher
+	Relation *GetUsersIdTasksParamsRelation `form:"relation,omitempty" json:"relation,omitempty"`
+}
+
+// GetUsersIdTasksParamsRelation defines parameters for GetUsersIdTasks.
+type GetUsersIdTasksParamsRelation string
+
+// Defines values for GetUsersIdTasksParamsRelation.
+const (
+	GetUsersIdTasksParamsRelationAssigned GetUsersIdTasksParamsRelation = "assigned"
+	GetUsersIdTasksParamsRelationOwned    GetUsersIdTasksParamsRelation = "owned"
+	GetUsersIdTasksParamsRelationWatching GetUsersIdTasksParamsRelation = "watching"
+)
+
+// GetUsersIdTasksParamsView defines parameters for GetUsersIdTasks.
+type GetUsersIdTasksParamsView string
+
+// Defines values for GetUsersIdTasksParamsView.
+const (
+	GetUsersIdTasksParamsViewFlat GetUsersIdTasksParamsView = "flat"
+	GetUsersIdTasksParamsViewTree GetUsersIdTasksParamsView = "tree"
+)
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 	// Get all tasks
@@ -144,7 +275,7 @@ type ServerInterface interface {
 	PatchTasksId(ctx echo.Context, id int64) error
 	// Get all users
 	// (GET /users)
-	GetUsers(ctx echo.Context) error
+	GetUsers(ctx echo.Context, params GetUsersParams) error
 	// Create a new user
 	// (POST /users)
 	PostUsers(ctx echo.Context) error
@@ -154,9 +285,12 @@ type ServerInterface interface {
 	// Update user by ID
 	// (PATCH /users/{id})
 	PatchUsersId(ctx echo.Context, id int) error
+	// Delete a user account
+	// (DELETE /users/{id}/account)
+	DeleteUsersIdAccount(ctx echo.Context, id int) error
 	// Get all tasks for a user
 	// (GET /users/{id}/tasks)
-	GetUsersIdTasks(ctx echo.Context, id int64) error
+	GetUsersIdTasks(ctx echo.Context, id int64, params GetUsersIdTasksParams) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -205,7 +339,16 @@ func (w *ServerInterfaceWrapper) PatchTasksId(ctx echo.Context) error {
 // GetUsers converts echo context to params.
 func (w *ServerInterfaceWrapper) GetUsers(ctx echo.Context) error {
 	var err error
-	err = w.Handler.GetUsers(ctx)
+	var params GetUsersParams
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+	err = w.Handler.GetUsers(ctx, params)
 	return err
 }
 
@@ -240,6 +383,18 @@ func (w *ServerInterfaceWrapper) PatchUsersId(ctx echo.Context) error {
 	return err
 }
 
+// DeleteUsersIdAccount converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteUsersIdAccount(ctx echo.Context) error {
+	var err error
+	var id int
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+	err = w.Handler.DeleteUsersIdAccount(ctx, id)
+	return err
+}
+
 // GetUsersIdTasks converts echo context to params.
 func (w *ServerInterfaceWrapper) GetUsersIdTasks(ctx echo.Context) error {
 	var err error
@@ -248,7 +403,16 @@ func (w *ServerInterfaceWrapper) GetUsersIdTasks(ctx echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
 	}
-	err = w.Handler.GetUsersIdTasks(ctx, id)
+	var params GetUsersIdTasksParams
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+	err = w.Handler.GetUsersIdTasks(ctx, id, params)
 	return err
 }
 
@@ -281,5 +445,6 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	router.POST(baseURL+"/users", wrapper.PostUsers)
 	router.DELETE(baseURL+"/users/:id", wrapper.DeleteUsersId)
 	router.PATCH(baseURL+"/users/:id", wrapper.PatchUsersId)
+	router.DELETE(baseURL+"/users/:id/account", wrapper.DeleteUsersIdAccount)
 	router.GET(baseURL+"/users/:id/tasks", wrapper.GetUsersIdTasks)
 }
@@ -0,0 +1,181 @@
+package webhookService
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"newproject/internal/tenant"
+	"strconv"
+	"time"
+)
+
+// Заголовки запроса к получателю
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderEventID   = "X-Webhook-Event-Id"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	// HeaderSignature — "sha256=" и HMAC-SHA256 от "<timestamp>.<тело>" на секрете подписки
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// DispatcherConfig — настройки отправки. Нулевые поля заменяются значениями по умолчанию.
+type DispatcherConfig struct {
+	// Interval — как часто искать доставки, которым пора уйти
+	Interval time.Duration
+	// BaseDelay — пауза перед второй попыткой, дальше она удваивается
+	BaseDelay time.Duration
+	// MaxAttempts — после стольких неудачных попыток доставка считается проваленной
+	MaxAttempts int
+	// BatchSize — сколько доставок отправляется за один обход
+	BatchSize int
+	// Lease — на сколько обход забирает доставки себе. Должен быть больше
+	// времени, за которое обход успевает отправить всю пачку.
+	Lease time.Duration
+}
+
+var defaultDispatcherConfig = DispatcherConfig{
+	Interval:    5 * time.Second,
+	BaseDelay:   30 * time.Second,
+	MaxAttempts: 8,
+	BatchSize:   50,
+	Lease:       10 * time.Minute,
+}
+
+// Dispatcher отправляет доставки из очереди и повторяет неудачные с
+// экспоненциальной паузой. Доставка гарантируется хотя бы один раз:
+// получатель отбрасывает повторы по заголовку X-Webhook-Event-Id.
+type Dispatcher struct {
+	repo   WebhookRepository
+	client *http.Client
+	config DispatcherConfig
+}
+
+func NewDispatcher(repo WebhookRepository, client *http.Client, config DispatcherConfig) *Dispatcher {
+	if config.Interval <= 0 {
+		config.Interval = defaultDispatcherConfig.Interval
+	}
+	if config.BaseDelay <= 0 {
+		config.BaseDelay = defaultDispatcherConfig.BaseDelay
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultDispatcherConfig.MaxAttempts
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultDispatcherConfig.BatchSize
+	}
+	if config.Lease <= 0 {
+		config.Lease = defaultDispatcherConfig.Lease
+	}
+	return &Dispatcher{repo: repo, client: client, config: config}
+}
+
+// Run обходит очередь, пока не отменен ctx
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.DispatchDue(ctx); err != nil {
+			log.Printf("Error dispatching webhooks: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue отправляет доставки, чья очередная попытка уже наступила.
+// Очередь общая для всех рабочих пространств, несколько диспетчеров
+// не отправляют одну доставку одновременно.
+func (d *Dispatcher) DispatchDue(ctx context.Context) error {
+	ctx = tenant.WithAllWorkspaces(ctx)
+	deliveries, err := d.repo.ClaimDueDeliveries(ctx, time.Now(), d.config.Lease, d.config.BatchSize)
+	if err != nil {
+		return fmt.Errorf("error fetching due deliveries: %w", err)
+	}
+
+	for _, delivery := range deliveries {
+		d.attempt(ctx, &delivery)
+		if err := d.repo.SaveDelivery(ctx, delivery); err != nil {
+			return fmt.Errorf("error saving delivery %d: %w", delivery.ID, err)
+		}
+	}
+	return nil
+}
+
+// attempt делает одну попытку и записывает ее итог в доставку
+func (d *Dispatcher) attempt(ctx context.Context, delivery *WebhookDelivery) {
+	delivery.Attempts++
+	code, err := d.send(ctx, *delivery)
+	delivery.ResponseCode = code
+
+	now := time.Now()
+	switch {
+	case err == nil:
+		delivery.Status, delivery.Error = StatusSucceeded, ""
+		delivery.DeliveredAt, delivery.NextAttemptAt = &now, nil
+	case delivery.Attempts >= d.config.MaxAttempts:
+		delivery.Status, delivery.Error = StatusFailed, err.Error()
+		delivery.NextAttemptAt = nil
+	default:
+		next := now.Add(d.backoff(delivery.Attempts))
+		delivery.Error, delivery.NextAttemptAt = err.Error(), &next
+	}
+}
+
+// send отправляет тело доставки получателю. Успехом считается ответ 2xx.
+func (d *Dispatcher) send(ctx context.Context, delivery WebhookDelivery) (*int, error) {
+	if delivery.Webhook == nil {
+		return nil, fmt.Errorf("webhook %d not found", delivery.WebhookID)
+	}
+
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderEventID, strconv.FormatUint(uint64(delivery.EventID), 10))
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Webhook.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	// Тело ответа не нужно, но его дочитывание позволяет переиспользовать соединение
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	code := resp.StatusCode
+	if code < 200 || code > 299 {
+		return &code, fmt.Errorf("unexpected response status %d", code)
+	}
+	return &code, nil
+}
+
+// backoff возвращает паузу после attempts неудачных попыток
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	return d.config.BaseDelay << (attempts - 1)
+}
+
+// Sign подписывает тело запроса так же, как его проверяет получатель
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
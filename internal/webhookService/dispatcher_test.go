@@ -0,0 +1,236 @@
+package webhookService
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"newproject/internal/tenant"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_txlock=immediate&_busy_timeout=5000"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.Use(tenant.Plugin{}); err != nil {
+		t.Fatalf("tenant plugin: %v", err)
+	}
+	if err := db.AutoMigrate(&Webhook{}, &WebhookDelivery{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// enqueue создает подписку на url и одну доставку, которой уже пора уйти
+func enqueue(t *testing.T, repo *webhookRepository, url string) WebhookDelivery {
+	t.Helper()
+	ctx := tenant.WithWorkspace(context.Background(), 1)
+	webhook, err := repo.CreateWebhook(ctx, Webhook{URL: url, Secret: "s3cret", Events: EventTaskCreated})
+	if err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	now := time.Now()
+	delivery, err := repo.CreateDelivery(ctx, WebhookDelivery{
+		WebhookID:     webhook.ID,
+		EventID:       7,
+		Event:         EventTaskCreated,
+		Payload:       `{"id":1}`,
+		Status:        StatusPending,
+		NextAttemptAt: &now,
+	})
+	if err != nil {
+		t.Fatalf("create delivery: %v", err)
+	}
+	return delivery
+}
+
+// reload читает доставку заново
+func reload(t *testing.T, repo *webhookRepository, delivery WebhookDelivery) WebhookDelivery {
+	t.Helper()
+	got, err := repo.GetDeliveryByID(tenant.WithWorkspace(context.Background(), 1), delivery.WebhookID, delivery.ID)
+	if err != nil {
+		t.Fatalf("get delivery: %v", err)
+	}
+	return got
+}
+
+// makeDue возвращает доставку в очередь, не дожидаясь паузы
+func makeDue(t *testing.T, db *gorm.DB, delivery WebhookDelivery) {
+	t.Helper()
+	ctx := tenant.WithAllWorkspaces(context.Background())
+	if err := db.WithContext(ctx).Model(&WebhookDelivery{}).Where("id = ?", delivery.ID).
+		Update("next_attempt_at", time.Now().Add(-time.Second)).Error; err != nil {
+		t.Fatalf("make due: %v", err)
+	}
+}
+
+func TestDispatchSignsRequest(t *testing.T) {
+	type request struct {
+		header http.Header
+		body   []byte
+	}
+	requests := make(chan request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- request{header: r.Header.Clone(), body: body}
+	}))
+	defer server.Close()
+
+	repo := NewWebhookRepository(openTestDB(t))
+	delivery := enqueue(t, repo, server.URL)
+
+	dispatcher := NewDispatcher(repo, server.Client(), DispatcherConfig{})
+	if err := dispatcher.DispatchDue(context.Background()); err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+
+	req := <-requests
+	if string(req.body) != delivery.Payload {
+		t.Errorf("body: got %s, want %s", req.body, delivery.Payload)
+	}
+	timestamp, err := strconv.ParseInt(req.header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		t.Fatalf("timestamp header %q: %v", req.header.Get(HeaderTimestamp), err)
+	}
+	if got, want := req.header.Get(HeaderSignature), Sign("s3cret", timestamp, req.body); got != want {
+		t.Errorf("signature: got %q, want %q", got, want)
+	}
+	if got := req.header.Get(HeaderEvent); got != EventTaskCreated {
+		t.Errorf("event header: got %q, want %q", got, EventTaskCreated)
+	}
+	if got := req.header.Get(HeaderEventID); got != "7" {
+		t.Errorf("event id header: got %q, want %q", got, "7")
+	}
+	if got, want := req.header.Get(HeaderDelivery), strconv.FormatUint(uint64(delivery.ID), 10); got != want {
+		t.Errorf("delivery header: got %q, want %q", got, want)
+	}
+
+	got := reload(t, repo, delivery)
+	if got.Status != StatusSucceeded || got.Attempts != 1 || got.DeliveredAt == nil || got.NextAttemptAt != nil {
+		t.Errorf("delivery: got status %s, attempts %d, delivered %v, next %v", got.Status, got.Attempts, got.DeliveredAt, got.NextAttemptAt)
+	}
+}
+
+func TestDispatchBacksOffAndGivesUp(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	db := openTestDB(t)
+	repo := NewWebhookRepository(db)
+	delivery := enqueue(t, repo, server.URL)
+
+	const baseDelay = time.Hour
+	dispatcher := NewDispatcher(repo, server.Client(), DispatcherConfig{BaseDelay: baseDelay, MaxAttempts: 4})
+
+	for attempt, delay := range []time.Duration{baseDelay, 2 * baseDelay, 4 * baseDelay} {
+		before := time.Now()
+		if err := dispatcher.DispatchDue(context.Background()); err != nil {
+			t.Fatalf("dispatch %d: %v", attempt+1, err)
+		}
+		after := time.Now()
+
+		got := reload(t, repo, delivery)
+		if got.Status != StatusPending || got.Attempts != attempt+1 {
+			t.Fatalf("attempt %d: got status %s, attempts %d", attempt+1, got.Status, got.Attempts)
+		}
+		if got.ResponseCode == nil || *got.ResponseCode != http.StatusInternalServerError {
+			t.Errorf("attempt %d: got response code %v, want 500", attempt+1, got.ResponseCode)
+		}
+		if got.NextAttemptAt == nil || got.NextAttemptAt.Before(before.Add(delay)) || got.NextAttemptAt.After(after.Add(delay)) {
+			t.Errorf("attempt %d: got next attempt %v, want %v after the attempt", attempt+1, got.NextAttemptAt, delay)
+		}
+
+		// Пока пауза не вышла, доставка не отправляется
+		if err := dispatcher.DispatchDue(context.Background()); err != nil {
+			t.Fatalf("dispatch before due: %v", err)
+		}
+		if n := calls.Load(); n != int32(attempt+1) {
+			t.Fatalf("attempt %d: got %d requests, want %d", attempt+1, n, attempt+1)
+		}
+		makeDue(t, db, delivery)
+	}
+
+	if err := dispatcher.DispatchDue(context.Background()); err != nil {
+		t.Fatalf("last dispatch: %v", err)
+	}
+	got := reload(t, repo, delivery)
+	if got.Status != StatusFailed || got.Attempts != 4 || got.NextAttemptAt != nil || got.Error == "" {
+		t.Errorf("after max attempts: got status %s, attempts %d, next %v, error %q", got.Status, got.Attempts, got.NextAttemptAt, got.Error)
+	}
+
+	makeDue(t, db, delivery)
+	if err := dispatcher.DispatchDue(context.Background()); err != nil {
+		t.Fatalf("dispatch failed delivery: %v", err)
+	}
+	if n := calls.Load(); n != 4 {
+		t.Errorf("failed delivery was sent again: got %d requests, want 4", n)
+	}
+}
+
+func TestClaimedDeliveriesAreNotClaimedAgain(t *testing.T) {
+	repo := NewWebhookRepository(openTestDB(t))
+	for i := 0; i < 10; i++ {
+		enqueue(t, repo, "http://example.invalid")
+	}
+	ctx := tenant.WithAllWorkspaces(context.Background())
+	now := time.Now()
+
+	var mu sync.Mutex
+	claimed := make(map[uint]int)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			deliveries, err := repo.ClaimDueDeliveries(ctx, now, time.Minute, 3)
+			if err != nil {
+				t.Errorf("claim: %v", err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, d := range deliveries {
+				if d.Webhook == nil {
+					t.Errorf("delivery %d: webhook not loaded", d.ID)
+				}
+				claimed[d.ID]++
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(claimed) != 10 {
+		t.Errorf("got %d claimed deliveries, want 10", len(claimed))
+	}
+	for id, n := range claimed {
+		if n != 1 {
+			t.Errorf("delivery %d claimed %d times", id, n)
+		}
+	}
+
+	// Аренда истекла — процесс, забравший доставки, считается упавшим
+	deliveries, err := repo.ClaimDueDeliveries(ctx, now.Add(2*time.Minute), time.Minute, 100)
+	if err != nil {
+		t.Fatalf("claim after lease: %v", err)
+	}
+	if len(deliveries) != 10 {
+		t.Errorf("after lease: got %d deliveries, want 10", len(deliveries))
+	}
+}
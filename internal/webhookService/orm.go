@@ -0,0 +1,93 @@
+package webhookService
+
+import (
+	"newproject/internal/outbox"
+	"strings"
+	"time"
+)
+
+// События, на которые можно подписаться
+const (
+	EventTaskCreated   = outbox.EventTaskCreated
+	EventTaskUpdated   = outbox.EventTaskUpdated
+	EventTaskCompleted = outbox.EventTaskCompleted
+	EventTaskDeleted   = outbox.EventTaskDeleted
+	EventTaskRestored  = outbox.EventTaskRestored
+	EventUserCreated   = outbox.EventUserCreated
+	EventUserUpdated   = outbox.EventUserUpdated
+	EventUserDeleted   = outbox.EventUserDeleted
+	EventUserRestored  = outbox.EventUserRestored
+)
+
+// Состояния доставки
+const (
+	// StatusPending — доставка ждет первой или очередной попытки
+	StatusPending = "pending"
+	// StatusSucceeded — получатель ответил 2xx
+	StatusSucceeded = "succeeded"
+	// StatusFailed — попытки исчерпаны
+	StatusFailed = "failed"
+)
+
+// Webhook — подписка рабочего пространства на события. Секрет подписывает
+// тела запросов и отдается только при создании.
+type Webhook struct {
+	ID          uint   `gorm:"primarykey" json:"id"`
+	WorkspaceID uint   `gorm:"not null;index;tenant" json:"workspace_id"`
+	URL         string `gorm:"size:2048;not null" json:"url"`
+	Secret      string `gorm:"size:128;not null" json:"-"`
+	// Events — события подписки через запятую
+	Events    string    `gorm:"size:512;not null" json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// EventList возвращает события подписки списком
+func (s Webhook) EventList() []string {
+	return strings.Split(s.Events, ",")
+}
+
+// subscribed сообщает, подписана ли подписка на событие
+func (s Webhook) subscribed(event string) bool {
+	for _, e := range s.EventList() {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery — отправка одного события одной подписке. Повторная отправка
+// по запросу создает новую доставку с тем же EventID, поэтому журнал
+// попыток не переписывается.
+type WebhookDelivery struct {
+	ID          uint     `gorm:"primarykey" json:"id"`
+	WorkspaceID uint     `gorm:"not null;index;tenant" json:"workspace_id"`
+	WebhookID   uint     `gorm:"not null;index" json:"webhook_id"`
+	Webhook     *Webhook `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	// EventID — ID сообщения outbox, из которого родилось событие.
+	// Получатель отбрасывает по нему повторы.
+	EventID  uint   `gorm:"not null;index" json:"event_id"`
+	Event    string `gorm:"size:32;not null" json:"event"`
+	Payload  string `gorm:"type:text;not null" json:"payload"`
+	Status   string `gorm:"type:varchar(16);not null;default:pending" json:"status"`
+	Attempts int    `gorm:"not null;default:0" json:"attempts"`
+	// ResponseCode — HTTP-код последней попытки, nil, если ответа не было
+	ResponseCode *int `json:"response_code"`
+	// Error — причина неудачи последней попытки
+	Error         string     `gorm:"type:text;not null;default:''" json:"error"`
+	NextAttemptAt *time.Time `gorm:"index" json:"next_attempt_at"`
+	DeliveredAt   *time.Time `json:"delivered_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// validEvent сообщает, можно ли подписаться на событие
+func validEvent(event string) bool {
+	switch event {
+	case EventTaskCreated, EventTaskUpdated, EventTaskCompleted, EventTaskDeleted, EventTaskRestored,
+		EventUserCreated, EventUserUpdated, EventUserDeleted, EventUserRestored:
+		return true
+	}
+	return false
+}
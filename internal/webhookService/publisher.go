@@ -0,0 +1,61 @@
+package webhookService
+
+import (
+	"context"
+	"fmt"
+	"newproject/internal/outbox"
+	"newproject/internal/tenant"
+	"time"
+)
+
+// Publisher ставит события outbox в очередь доставки подписанным на них
+// вебхукам пространства. Повтор события не создает доставок еще раз.
+// Изменения вне рабочих пространств вебхуков не порождают.
+type Publisher struct {
+	repo WebhookRepository
+}
+
+func NewPublisher(repo WebhookRepository) *Publisher {
+	return &Publisher{repo: repo}
+}
+
+func (p *Publisher) Publish(ctx context.Context, msg outbox.OutboxMessage) error {
+	if msg.WorkspaceID == nil {
+		return nil
+	}
+	ctx = tenant.WithWorkspace(ctx, *msg.WorkspaceID)
+
+	webhooks, err := p.repo.GetWebhooks(ctx)
+	if err != nil {
+		return fmt.Errorf("webhooks: %w", err)
+	}
+	var deliveries []WebhookDelivery
+	var payload []byte
+	now := time.Now()
+	for _, webhook := range webhooks {
+		if !webhook.subscribed(msg.Event) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = msg.Body(); err != nil {
+				return fmt.Errorf("webhooks: %w", err)
+			}
+		}
+		deliveries = append(deliveries, WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       msg.ID,
+			Event:         msg.Event,
+			Payload:       string(payload),
+			Status:        StatusPending,
+			NextAttemptAt: &now,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	if err := p.repo.CreateEventDeliveries(ctx, msg.ID, deliveries); err != nil {
+		return fmt.Errorf("webhooks: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package webhookService
+
+import (
+	"context"
+	"newproject/internal/pagination"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, webhook Webhook) (Webhook, error)
+	GetWebhooks(ctx context.Context) ([]Webhook, error)
+	GetWebhookByID(ctx context.Context, id uint) (Webhook, error)
+	DeleteWebhookByID(ctx context.Context, id uint) error
+	CreateDelivery(ctx context.Context, delivery WebhookDelivery) (WebhookDelivery, error)
+	CreateEventDeliveries(ctx context.Context, eventID uint, deliveries []WebhookDelivery) error
+	GetDeliveries(ctx context.Context, webhookID uint, page pagination.Page) ([]WebhookDelivery, string, error)
+	GetDeliveryByID(ctx context.Context, webhookID, id uint) (WebhookDelivery, error)
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]WebhookDelivery, error)
+	SaveDelivery(ctx context.Context, delivery WebhookDelivery) error
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) *webhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) CreateWebhook(ctx context.Context, webhook Webhook) (Webhook, error) {
+	err := r.db.WithContext(ctx).Create(&webhook).Error
+	return webhook, err
+}
+
+func (r *webhookRepository) GetWebhooks(ctx context.Context) ([]Webhook, error) {
+	var webhooks []Webhook
+	err := r.db.WithContext(ctx).Order("id").Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *webhookRepository) GetWebhookByID(ctx context.Context, id uint) (Webhook, error) {
+	var webhook Webhook
+	err := r.db.WithContext(ctx).First(&webhook, id).Error
+	return webhook, err
+}
+
+// DeleteWebhookByID удаляет подписку, ее доставки удаляются каскадом в базе
+func (r *webhookRepository) DeleteWebhookByID(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&Webhook{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery WebhookDelivery) (WebhookDelivery, error) {
+	err := r.db.WithContext(ctx).Create(&delivery).Error
+	return delivery, err
+}
+
+// CreateEventDeliveries создает доставки события, пропуская подписки,
+// у которых доставка этого события уже есть
+func (r *webhookRepository) CreateEventDeliveries(ctx context.Context, eventID uint, deliveries []WebhookDelivery) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing []uint
+		if err := tx.Model(&WebhookDelivery{}).Where("event_id = ?", eventID).Pluck("webhook_id", &existing).Error; err != nil {
+			return err
+		}
+		delivered := make(map[uint]bool, len(existing))
+		for _, id := range existing {
+			delivered[id] = true
+		}
+
+		var fresh []WebhookDelivery
+		for _, delivery := range deliveries {
+			if !delivered[delivery.WebhookID] {
+				fresh = append(fresh, delivery)
+			}
+		}
+		if len(fresh) == 0 {
+			return nil
+		}
+		return tx.Create(&fresh).Error
+	})
+}
+
+// GetDeliveries возвращает страницу доставок подписки
+func (r *webhookRepository) GetDeliveries(ctx context.Context, webhookID uint, page pagination.Page) ([]WebhookDelivery, string, error) {
+	var deliveries []WebhookDelivery
+	if err := pagination.Apply(r.db.WithContext(ctx).Where("webhook_id = ?", webhookID), page).Find(&deliveries).Error; err != nil {
+		return nil, "", err
+	}
+	deliveries, next := pagination.Trim(deliveries, page, func(d WebhookDelivery) pagination.Cursor {
+		return page.CursorFor(d.CreatedAt, d.ID)
+	})
+	return deliveries, next, nil
+}
+
+func (r *webhookRepository) GetDeliveryByID(ctx context.Context, webhookID, id uint) (WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	err := r.db.WithContext(ctx).Where("webhook_id = ?", webhookID).First(&delivery, id).Error
+	return delivery, err
+}
+
+// ClaimDueDeliveries забирает доставки, чья очередная попытка уже наступила,
+// вместе с подписками и сдвигает их попытку на lease вперед. Пока срок не
+// вышел, другие диспетчеры их не берут; если процесс упадет, доставки
+// вернутся в очередь сами.
+func (r *webhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		if err := tx.Model(&WebhookDelivery{}).Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", StatusPending, now).
+			Order("next_attempt_at, id").
+			Limit(limit).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		if err := tx.Model(&WebhookDelivery{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(lease)).Error; err != nil {
+			return err
+		}
+		return tx.Preload("Webhook").Where("id IN ?", ids).Order("id").Find(&deliveries).Error
+	})
+	return deliveries, err
+}
+
+// SaveDelivery сохраняет результат попытки
+func (r *webhookRepository) SaveDelivery(ctx context.Context, delivery WebhookDelivery) error {
+	return r.db.WithContext(ctx).Model(&delivery).Select("status", "attempts", "response_code", "error", "next_attempt_at", "delivered_at").
+		Updates(&delivery).Error
+}
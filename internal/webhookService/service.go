@@ -0,0 +1,134 @@
+package webhookService
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"newproject/internal/pagination"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrWebhookNotFound — подписки нет в рабочем пространстве
+	ErrWebhookNotFound = fmt.Errorf("webhook not found: %w", gorm.ErrRecordNotFound)
+	// ErrDeliveryNotFound — доставки нет у подписки
+	ErrDeliveryNotFound = fmt.Errorf("delivery not found: %w", gorm.ErrRecordNotFound)
+	// ErrInvalidURL — адрес получателя не абсолютный http(s)-URL
+	ErrInvalidURL = errors.New("url must be an absolute http or https URL")
+	// ErrInvalidEvents — пустой список событий или неизвестное событие
+	ErrInvalidEvents = errors.New("events must be a non-empty list of known event types")
+	// ErrInvalidSecret — секрет слишком короткий или длинный
+	ErrInvalidSecret = errors.New("secret must be 16 to 128 characters")
+)
+
+type WebhookService struct {
+	repo WebhookRepository
+}
+
+func NewWebhookService(repo WebhookRepository) *WebhookService {
+	return &WebhookService{repo: repo}
+}
+
+// CreateWebhook подписывает адрес на события рабочего пространства из контекста.
+// Пустой secret заменяется случайным. Права на управление вебхуками проверяет вызывающий.
+func (s *WebhookService) CreateWebhook(ctx context.Context, rawURL string, events []string, secret string) (Webhook, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(rawURL) > 2048 {
+		return Webhook{}, ErrInvalidURL
+	}
+
+	if len(events) == 0 {
+		return Webhook{}, ErrInvalidEvents
+	}
+	seen := make(map[string]bool, len(events))
+	unique := make([]string, 0, len(events))
+	for _, event := range events {
+		if !validEvent(event) {
+			return Webhook{}, ErrInvalidEvents
+		}
+		if !seen[event] {
+			seen[event] = true
+			unique = append(unique, event)
+		}
+	}
+
+	if secret == "" {
+		if secret, err = newSecret(); err != nil {
+			return Webhook{}, fmt.Errorf("error generating secret: %w", err)
+		}
+	} else if len(secret) < 16 || len(secret) > 128 {
+		return Webhook{}, ErrInvalidSecret
+	}
+
+	return s.repo.CreateWebhook(ctx, Webhook{URL: rawURL, Secret: secret, Events: strings.Join(unique, ",")})
+}
+
+// GetWebhooks возвращает подписки рабочего пространства
+func (s *WebhookService) GetWebhooks(ctx context.Context) ([]Webhook, error) {
+	return s.repo.GetWebhooks(ctx)
+}
+
+// DeleteWebhook удаляет подписку вместе с журналом ее доставок
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id uint) error {
+	err := s.repo.DeleteWebhookByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrWebhookNotFound
+	}
+	return err
+}
+
+// GetDeliveries возвращает страницу журнала доставок подписки
+func (s *WebhookService) GetDeliveries(ctx context.Context, webhookID uint, page pagination.Page) ([]WebhookDelivery, string, error) {
+	if err := s.checkWebhook(ctx, webhookID); err != nil {
+		return nil, "", err
+	}
+	return s.repo.GetDeliveries(ctx, webhookID, page)
+}
+
+// Redeliver ставит событие доставки в очередь еще раз. Создается новая
+// доставка с тем же телом и EventID, она уходит при ближайшем обходе.
+func (s *WebhookService) Redeliver(ctx context.Context, webhookID, deliveryID uint) (WebhookDelivery, error) {
+	if err := s.checkWebhook(ctx, webhookID); err != nil {
+		return WebhookDelivery{}, err
+	}
+
+	original, err := s.repo.GetDeliveryByID(ctx, webhookID, deliveryID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return WebhookDelivery{}, ErrDeliveryNotFound
+	} else if err != nil {
+		return WebhookDelivery{}, err
+	}
+
+	now := time.Now()
+	return s.repo.CreateDelivery(ctx, WebhookDelivery{
+		WebhookID:     webhookID,
+		EventID:       original.EventID,
+		Event:         original.Event,
+		Payload:       original.Payload,
+		Status:        StatusPending,
+		NextAttemptAt: &now,
+	})
+}
+
+func (s *WebhookService) checkWebhook(ctx context.Context, id uint) error {
+	_, err := s.repo.GetWebhookByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrWebhookNotFound
+	}
+	return err
+}
+
+// newSecret генерирует случайный секрет подписи
+func newSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,53 @@
+package workspaceService
+
+import (
+	"newproject/internal/models"
+	"time"
+)
+
+// Роли участника рабочего пространства
+const (
+	// RoleOwner — владелец: управляет пространством и участниками, включая владельцев
+	RoleOwner = models.WorkspaceRoleOwner
+	// RoleAdmin — администратор: управляет пространством и участниками, кроме владельцев
+	RoleAdmin = models.WorkspaceRoleAdmin
+	// RoleMember — участник: работает с задачами пространства
+	RoleMember = models.WorkspaceRoleMember
+)
+
+// DefaultWorkspaceName — название пространства, в которое попадают новые пользователи
+const DefaultWorkspaceName = "Default"
+
+// Workspace — рабочее пространство команды. Задачи, проекты, метки и доски
+// статусов принадлежат пространству и не видны из других.
+type Workspace struct {
+	ID   uint   `gorm:"primarykey" json:"id"`
+	Name string `gorm:"size:64;not null" json:"name"`
+	// IsDefault отмечает единственное пространство, куда добавляются новые пользователи
+	IsDefault bool      `gorm:"not null;default:false;uniqueIndex:idx_workspaces_default,where:is_default" json:"is_default"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WorkspaceMember — участие пользователя в рабочем пространстве
+type WorkspaceMember struct {
+	WorkspaceID uint      `gorm:"primaryKey;autoIncrement:false" json:"workspace_id"`
+	UserID      uint      `gorm:"primaryKey;autoIncrement:false;index" json:"user_id"`
+	Role        string    `gorm:"type:varchar(16);not null" json:"role"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// WorkspaceSummary — пространство вместе с ролью в нем вызывающего
+type WorkspaceSummary struct {
+	Workspace
+	Role string `json:"role"`
+}
+
+// validRole сообщает, является ли role одной из ролей участника
+func validRole(role string) bool {
+	switch role {
+	case RoleOwner, RoleAdmin, RoleMember:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package workspaceService
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type WorkspaceRepository interface {
+	CreateWorkspace(ctx context.Context, workspace Workspace, ownerID uint) (Workspace, error)
+	GetWorkspaceByID(ctx context.Context, id uint) (Workspace, error)
+	GetWorkspacesByUserID(ctx context.Context, userID uint) ([]WorkspaceSummary, error)
+	UpdateWorkspace(ctx context.Context, workspace Workspace) (Workspace, error)
+	GetMembers(ctx context.Context, workspaceID uint) ([]WorkspaceMember, error)
+	GetMember(ctx context.Context, workspaceID, userID uint) (WorkspaceMember, error)
+	SetMember(ctx context.Context, member WorkspaceMember) (WorkspaceMember, error)
+	RemoveMember(ctx context.Context, workspaceID, userID uint) error
+	CountOwners(ctx context.Context, workspaceID uint) (int64, error)
+	DefaultMember(ctx context.Context, userID uint) (WorkspaceMember, error)
+}
+
+// liveMember отсекает участников, чьи учетные записи лежат в корзине: членство
+// сохраняется, чтобы восстановленный пользователь вернулся в свои пространства
+const liveMember = "user_id IN (SELECT id FROM users WHERE deleted_at IS NULL)"
+
+type workspaceRepository struct {
+	db *gorm.DB
+}
+
+func NewWorkspaceRepository(db *gorm.DB) *workspaceRepository {
+	return &workspaceRepository{db: db}
+}
+
+// CreateWorkspace создает пространство, владельцем которого становится ownerID
+func (r *workspaceRepository) CreateWorkspace(ctx context.Context, workspace Workspace, ownerID uint) (Workspace, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&workspace).Error; err != nil {
+			return err
+		}
+		return tx.Create(&WorkspaceMember{WorkspaceID: workspace.ID, UserID: ownerID, Role: RoleOwner}).Error
+	})
+	return workspace, err
+}
+
+func (r *workspaceRepository) GetWorkspaceByID(ctx context.Context, id uint) (Workspace, error) {
+	var workspace Workspace
+	err := r.db.WithContext(ctx).First(&workspace, id).Error
+	return workspace, err
+}
+
+// GetWorkspacesByUserID возвращает пространства пользователя вместе с его ролью
+// в порядке вступления
+func (r *workspaceRepository) GetWorkspacesByUserID(ctx context.Context, userID uint) ([]WorkspaceSummary, error) {
+	var workspaces []WorkspaceSummary
+	err := r.db.WithContext(ctx).Model(&Workspace{}).
+		Select("workspaces.*, m.role").
+		Joins("JOIN workspace_members m ON m.workspace_id = workspaces.id").
+		Where("m.user_id = ?", userID).
+		Order("m.created_at, workspaces.id").
+		Find(&workspaces).Error
+	return workspaces, err
+}
+
+func (r *workspaceRepository) UpdateWorkspace(ctx context.Context, workspace Workspace) (Workspace, error) {
+	err := r.db.WithContext(ctx).Save(&workspace).Error
+	return workspace, err
+}
+
+func (r *workspaceRepository) GetMembers(ctx context.Context, workspaceID uint) ([]WorkspaceMember, error) {
+	var members []WorkspaceMember
+	err := r.db.WithContext(ctx).Where("workspace_id = ?", workspaceID).Where(liveMember).
+		Order("created_at, user_id").Find(&members).Error
+	return members, err
+}
+
+func (r *workspaceRepository) GetMember(ctx context.Context, workspaceID, userID uint) (WorkspaceMember, error) {
+	var member WorkspaceMember
+	err := r.db.WithContext(ctx).Where("workspace_id = ? AND user_id = ?", workspaceID, userID).First(&member).Error
+	return member, err
+}
+
+// SetMember добавляет пользователя в пространство или меняет его роль
+func (r *workspaceRepository) SetMember(ctx context.Context, member WorkspaceMember) (WorkspaceMember, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&WorkspaceMember{}).Where("workspace_id = ? AND user_id = ?", member.WorkspaceID, member.UserID).
+			Update("role", member.Role)
+		if result.Error != nil || result.RowsAffected > 0 {
+			return result.Error
+		}
+		return tx.Create(&member).Error
+	})
+	if err != nil {
+		return WorkspaceMember{}, err
+	}
+	return r.GetMember(ctx, member.WorkspaceID, member.UserID)
+}
+
+// RemoveMember исключает пользователя из пространства, а если он в нем
+// не состоял — возвращает gorm.ErrRecordNotFound
+func (r *workspaceRepository) RemoveMember(ctx context.Context, workspaceID, userID uint) error {
+	result := r.db.WithContext(ctx).Where("workspace_id = ? AND user_id = ?", workspaceID, userID).Delete(&WorkspaceMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *workspaceRepository) CountOwners(ctx context.Context, workspaceID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&WorkspaceMember{}).
+		Where("workspace_id = ? AND role = ?", workspaceID, RoleOwner).Where(liveMember).Count(&count).Error
+	return count, err
+}
+
+// DefaultMember выбирает членство пользователя для запросов без X-Workspace-ID:
+// в общем пространстве по умолчанию, иначе в том, где он состоит дольше всего
+func (r *workspaceRepository) DefaultMember(ctx context.Context, userID uint) (WorkspaceMember, error) {
+	var member WorkspaceMember
+	err := r.db.WithContext(ctx).
+		Joins("JOIN workspaces w ON w.id = workspace_members.workspace_id").
+		Where("workspace_members.user_id = ?", userID).
+		Order("w.is_default DESC, workspace_members.created_at, workspace_members.workspace_id").
+		First(&member).Error
+	return member, err
+}
+
+// JoinDefaultWorkspace добавляет пользователя в пространство по умолчанию,
+// создавая его при первой регистрации. Первый участник становится владельцем.
+// Вызывается в транзакции создания пользователя.
+func JoinDefaultWorkspace(tx *gorm.DB, userID uint) error {
+	workspace := Workspace{Name: DefaultWorkspaceName, IsDefault: true}
+	if err := tx.Where("is_default = ?", true).FirstOrCreate(&workspace).Error; err != nil {
+		return err
+	}
+
+	var members int64
+	if err := tx.Model(&WorkspaceMember{}).Where("workspace_id = ?", workspace.ID).Count(&members).Error; err != nil {
+		return err
+	}
+	role := RoleMember
+	if members == 0 {
+		role = RoleOwner
+	}
+	return tx.Create(&WorkspaceMember{WorkspaceID: workspace.ID, UserID: userID, Role: role}).Error
+}
@@ -0,0 +1,262 @@
+package workspaceService
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"newproject/internal/identity"
+	"newproject/internal/tenant"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrWorkspaceNotFound возвращается и для пространств, где вызывающий не состоит
+	ErrWorkspaceNotFound = fmt.Errorf("workspace not found: %w", gorm.ErrRecordNotFound)
+	// ErrMemberNotFound — пользователь не состоит в пространстве
+	ErrMemberNotFound = fmt.Errorf("user is not a member of the workspace: %w", gorm.ErrRecordNotFound)
+	// ErrForbidden — роли вызывающего в пространстве не хватает для действия
+	ErrForbidden = errors.New("forbidden")
+	// ErrInvalidName — пустое или слишком длинное название пространства
+	ErrInvalidName = errors.New("workspace name must be 1 to 64 characters")
+	// ErrInvalidRole — роль не из списка owner/admin/member
+	ErrInvalidRole = errors.New("role must be one of owner, admin, member")
+	// ErrInvalidMember — добавляемого пользователя нет
+	ErrInvalidMember = errors.New("member must be an existing user")
+	// ErrLastOwner — действие оставило бы пространство без владельцев
+	ErrLastOwner = errors.New("cannot remove the last owner of the workspace")
+)
+
+// UserLookup — то, что WorkspaceService нужно знать о пользователях
+type UserLookup interface {
+	// UserExistsAnyWorkspace сообщает, есть ли пользователь с таким ID в системе
+	UserExistsAnyWorkspace(ctx context.Context, id uint) (bool, error)
+}
+
+// TaskReleaser — то, что WorkspaceService нужно от задач
+type TaskReleaser interface {
+	// ReleaseUser снимает пользователя с задач рабочего пространства из контекста.
+	// Если задан reassignTo, задачи, где он исполнитель, переходят к reassignTo.
+	ReleaseUser(ctx context.Context, userID uint, reassignTo *uint) error
+}
+
+type WorkspaceService struct {
+	repo  WorkspaceRepository
+	users UserLookup
+	tasks TaskReleaser
+}
+
+func NewWorkspaceService(repo WorkspaceRepository, users UserLookup, tasks TaskReleaser) *WorkspaceService {
+	return &WorkspaceService{repo: repo, users: users, tasks: tasks}
+}
+
+// GetWorkspaces возвращает пространства, в которых состоит вызывающий
+func (s *WorkspaceService) GetWorkspaces(ctx context.Context) ([]WorkspaceSummary, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetWorkspacesByUserID(ctx, caller.UserID)
+}
+
+// CreateWorkspace создает пространство, владельцем которого становится вызывающий
+func (s *WorkspaceService) CreateWorkspace(ctx context.Context, name string) (WorkspaceSummary, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return WorkspaceSummary{}, err
+	}
+
+	name, err = checkName(name)
+	if err != nil {
+		return WorkspaceSummary{}, err
+	}
+	created, err := s.repo.CreateWorkspace(ctx, Workspace{Name: name}, caller.UserID)
+	if err != nil {
+		return WorkspaceSummary{}, err
+	}
+	return WorkspaceSummary{Workspace: created, Role: RoleOwner}, nil
+}
+
+// UpdateWorkspace переименовывает пространство. Доступно владельцам и администраторам пространства.
+func (s *WorkspaceService) UpdateWorkspace(ctx context.Context, id uint, name string) (WorkspaceSummary, error) {
+	workspace, role, err := s.getWorkspace(ctx, id)
+	if err != nil {
+		return WorkspaceSummary{}, err
+	}
+	if !canManage(ctx, role) {
+		return WorkspaceSummary{}, ErrForbidden
+	}
+
+	workspace.Name, err = checkName(name)
+	if err != nil {
+		return WorkspaceSummary{}, err
+	}
+	updated, err := s.repo.UpdateWorkspace(ctx, workspace)
+	if err != nil {
+		return WorkspaceSummary{}, err
+	}
+	return WorkspaceSummary{Workspace: updated, Role: role}, nil
+}
+
+// GetMembers возвращает участников пространства
+func (s *WorkspaceService) GetMembers(ctx context.Context, id uint) ([]WorkspaceMember, error) {
+	if _, _, err := s.getWorkspace(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetMembers(ctx, id)
+}
+
+// SetMember добавляет пользователя в пространство или меняет его роль.
+// Участниками управляют владельцы и администраторы пространства, но выдавать
+// и снимать роль владельца могут только владельцы. Последний владелец
+// не может лишиться своей роли.
+func (s *WorkspaceService) SetMember(ctx context.Context, id, userID uint, role string) (WorkspaceMember, error) {
+	_, callerRole, err := s.getWorkspace(ctx, id)
+	if err != nil {
+		return WorkspaceMember{}, err
+	}
+	if !canManage(ctx, callerRole) {
+		return WorkspaceMember{}, ErrForbidden
+	}
+	if !validRole(role) {
+		return WorkspaceMember{}, ErrInvalidRole
+	}
+
+	current, err := s.repo.GetMember(ctx, id, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		exists, err := s.users.UserExistsAnyWorkspace(ctx, userID)
+		if err != nil {
+			return WorkspaceMember{}, err
+		}
+		if !exists {
+			return WorkspaceMember{}, ErrInvalidMember
+		}
+	} else if err != nil {
+		return WorkspaceMember{}, err
+	}
+
+	if (role == RoleOwner || current.Role == RoleOwner) && !isOwner(ctx, callerRole) {
+		return WorkspaceMember{}, ErrForbidden
+	}
+	if current.Role == RoleOwner && role != RoleOwner {
+		if err := s.ensureNotLastOwner(ctx, id); err != nil {
+			return WorkspaceMember{}, err
+		}
+	}
+	return s.repo.SetMember(ctx, WorkspaceMember{WorkspaceID: id, UserID: userID, Role: role})
+}
+
+// RemoveMember исключает пользователя из пространства. Выйти сам может любой
+// участник, исключать других — владельцы и администраторы пространства,
+// а владельцев — только владельцы. Последний владелец уйти не может.
+// Исключенный снимается с задач пространства; задачи, где он был исполнителем,
+// переходят к reassignTo, если он задан. Учетная запись остается.
+func (s *WorkspaceService) RemoveMember(ctx context.Context, id, userID uint, reassignTo *uint) error {
+	_, callerRole, err := s.getWorkspace(ctx, id)
+	if err != nil {
+		return err
+	}
+	caller, _ := identity.FromContext(ctx)
+
+	member, err := s.repo.GetMember(ctx, id, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrMemberNotFound
+	} else if err != nil {
+		return err
+	}
+
+	if userID != caller.UserID {
+		if !canManage(ctx, callerRole) || (member.Role == RoleOwner && !isOwner(ctx, callerRole)) {
+			return ErrForbidden
+		}
+	}
+	if member.Role == RoleOwner {
+		if err := s.ensureNotLastOwner(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	if err := s.tasks.ReleaseUser(tenant.WithWorkspace(ctx, id), userID, reassignTo); err != nil {
+		return err
+	}
+	err = s.repo.RemoveMember(ctx, id, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrMemberNotFound
+	}
+	return err
+}
+
+// ResolveWorkspace реализует tenant.Resolver
+func (s *WorkspaceService) ResolveWorkspace(ctx context.Context, userID, requested uint) (uint, string, error) {
+	var member WorkspaceMember
+	var err error
+	if requested == 0 {
+		member, err = s.repo.DefaultMember(ctx, userID)
+	} else {
+		member, err = s.repo.GetMember(ctx, requested, userID)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, "", tenant.ErrNotMember
+	} else if err != nil {
+		return 0, "", err
+	}
+	return member.WorkspaceID, member.Role, nil
+}
+
+// getWorkspace загружает пространство и роль в нем вызывающего. Пространства,
+// где вызывающий не состоит, видны только администраторам системы, с пустой ролью.
+func (s *WorkspaceService) getWorkspace(ctx context.Context, id uint) (Workspace, string, error) {
+	caller, err := identity.Require(ctx)
+	if err != nil {
+		return Workspace{}, "", err
+	}
+
+	workspace, err := s.repo.GetWorkspaceByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Workspace{}, "", ErrWorkspaceNotFound
+	} else if err != nil {
+		return Workspace{}, "", err
+	}
+
+	member, err := s.repo.GetMember(ctx, id, caller.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if !caller.IsPlatformAdmin() {
+			return Workspace{}, "", ErrWorkspaceNotFound
+		}
+	} else if err != nil {
+		return Workspace{}, "", err
+	}
+	return workspace, member.Role, nil
+}
+
+func (s *WorkspaceService) ensureNotLastOwner(ctx context.Context, id uint) error {
+	owners, err := s.repo.CountOwners(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error counting owners: %w", err)
+	}
+	if owners <= 1 {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+// canManage — участниками и настройками управляют владельцы и администраторы
+// пространства, а также администраторы системы
+func canManage(ctx context.Context, role string) bool {
+	return role == RoleOwner || role == RoleAdmin || isOwner(ctx, role)
+}
+
+// isOwner — права владельца есть у владельцев пространства и администраторов системы
+func isOwner(ctx context.Context, role string) bool {
+	caller, _ := identity.FromContext(ctx)
+	return role == RoleOwner || caller.IsPlatformAdmin()
+}
+
+func checkName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 64 {
+		return "", ErrInvalidName
+	}
+	return name, nil
+}
@@ -0,0 +1,130 @@
+package workspaceService
+
+import (
+	"context"
+	"errors"
+	"newproject/internal/identity"
+	"newproject/internal/tenant"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestResolveWorkspaceRequiresMembership(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&Workspace{}, &WorkspaceMember{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	for _, userID := range []uint{1, 2} {
+		if err := JoinDefaultWorkspace(db, userID); err != nil {
+			t.Fatalf("join default: %v", err)
+		}
+	}
+	repo := NewWorkspaceRepository(db)
+	ctx := context.Background()
+	team, err := repo.CreateWorkspace(ctx, Workspace{Name: "team"}, 1)
+	if err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	var defaultID uint
+	db.Model(&Workspace{}).Where("is_default = ?", true).Pluck("id", &defaultID)
+
+	service := NewWorkspaceService(repo, nil, nil)
+	tests := []struct {
+		name      string
+		userID    uint
+		requested uint
+		want      uint
+		wantRole  string
+		wantErr   error
+	}{
+		{name: "default", userID: 2, want: defaultID, wantRole: RoleMember},
+		{name: "default owner", userID: 1, want: defaultID, wantRole: RoleOwner},
+		{name: "member", userID: 1, requested: team.ID, want: team.ID, wantRole: RoleOwner},
+		{name: "not a member", userID: 2, requested: team.ID, wantErr: tenant.ErrNotMember},
+		{name: "unknown workspace", userID: 1, requested: 999, wantErr: tenant.ErrNotMember},
+		{name: "no workspaces", userID: 3, wantErr: tenant.ErrNotMember},
+	}
+	for _, tt := range tests {
+		got, role, err := service.ResolveWorkspace(ctx, tt.userID, tt.requested)
+		if !errors.Is(err, tt.wantErr) || got != tt.want || role != tt.wantRole {
+			t.Errorf("%s: got %d, %q, %v, want %d, %q, %v", tt.name, got, role, err, tt.want, tt.wantRole, tt.wantErr)
+		}
+	}
+
+	if err := repo.RemoveMember(ctx, team.ID, 1); err != nil {
+		t.Fatalf("remove member: %v", err)
+	}
+	if _, _, err := service.ResolveWorkspace(ctx, 1, team.ID); !errors.Is(err, tenant.ErrNotMember) {
+		t.Errorf("removed member: got %v, want %v", err, tenant.ErrNotMember)
+	}
+}
+
+// releases запоминает, с задач каких пространств снимали пользователей
+type releases map[uint][]uint
+
+func (r releases) ReleaseUser(ctx context.Context, userID uint, reassignTo *uint) error {
+	workspaceID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	r[workspaceID] = append(r[workspaceID], userID)
+	return nil
+}
+
+func TestRemoveMemberReleasesTasks(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&Workspace{}, &WorkspaceMember{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	// Живых участников репозиторий ищет по таблице пользователей
+	if err := db.Exec("CREATE TABLE users (id integer PRIMARY KEY, deleted_at datetime)").Error; err != nil {
+		t.Fatalf("create users: %v", err)
+	}
+	if err := db.Exec("INSERT INTO users (id) VALUES (1), (2), (3), (4)").Error; err != nil {
+		t.Fatalf("insert users: %v", err)
+	}
+	repo := NewWorkspaceRepository(db)
+	owner := identity.WithCaller(context.Background(), identity.Caller{UserID: 1, Role: "user"})
+	team, err := repo.CreateWorkspace(owner, Workspace{Name: "team"}, 1)
+	if err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	for _, userID := range []uint{2, 3} {
+		if _, err := repo.SetMember(owner, WorkspaceMember{WorkspaceID: team.ID, UserID: userID, Role: RoleMember}); err != nil {
+			t.Fatalf("add member %d: %v", userID, err)
+		}
+	}
+
+	released := releases{}
+	service := NewWorkspaceService(repo, nil, released)
+	member := identity.WithCaller(context.Background(), identity.Caller{UserID: 2, Role: "user"})
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		userID  uint
+		wantErr error
+	}{
+		{name: "member removes another", ctx: member, userID: 3, wantErr: ErrForbidden},
+		{name: "last owner", ctx: owner, userID: 1, wantErr: ErrLastOwner},
+		{name: "not a member", ctx: owner, userID: 4, wantErr: ErrMemberNotFound},
+		{name: "by owner", ctx: owner, userID: 3},
+		{name: "leave", ctx: member, userID: 2},
+	}
+	for _, tt := range tests {
+		if err := service.RemoveMember(tt.ctx, team.ID, tt.userID, nil); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+	if got := released[team.ID]; len(got) != 2 || got[0] != 3 || got[1] != 2 {
+		t.Errorf("released: got %v, want [3 2]", got)
+	}
+}
@@ -0,0 +1,5 @@
+ALTER TABLE users
+ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'admin'));
+
+-- Первый зарегистрированный пользователь становится администратором
+UPDATE users SET role = 'admin' WHERE id = (SELECT MIN(id) FROM users);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tasks_search_vector;
+ALTER TABLE tasks DROP COLUMN search_vector;
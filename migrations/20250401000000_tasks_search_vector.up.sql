@@ -0,0 +1,5 @@
+ALTER TABLE tasks
+ADD COLUMN search_vector tsvector
+GENERATED ALWAYS AS (to_tsvector('simple', coalesce(task, ''))) STORED;
+
+CREATE INDEX idx_tasks_search_vector ON tasks USING GIN (search_vector);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_tasks_open_due_at;
+ALTER TABLE tasks
+DROP COLUMN completed_at,
+DROP COLUMN priority,
+DROP COLUMN due_at;
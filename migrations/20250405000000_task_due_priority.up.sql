@@ -0,0 +1,9 @@
+ALTER TABLE tasks
+ADD COLUMN due_at TIMESTAMPTZ,
+ADD COLUMN priority VARCHAR(16) NOT NULL DEFAULT 'normal' CHECK (priority IN ('low', 'normal', 'high', 'urgent')),
+ADD COLUMN completed_at TIMESTAMPTZ;
+
+-- Для уже выполненных задач точное время неизвестно, берем время последнего изменения
+UPDATE tasks SET completed_at = updated_at WHERE is_done = true;
+
+CREATE INDEX idx_tasks_open_due_at ON tasks (due_at) WHERE is_done = false;
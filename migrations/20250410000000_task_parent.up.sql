@@ -0,0 +1,4 @@
+ALTER TABLE tasks
+ADD COLUMN parent_id BIGINT REFERENCES tasks(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_tasks_parent_id ON tasks (parent_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_tasks_project_position;
+ALTER TABLE tasks
+DROP COLUMN position,
+DROP COLUMN project_id;
+
+DROP TABLE IF EXISTS projects;
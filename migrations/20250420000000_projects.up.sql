@@ -0,0 +1,19 @@
+CREATE TABLE projects (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    archived_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_projects_user_id ON projects (user_id);
+CREATE INDEX idx_projects_deleted_at ON projects (deleted_at);
+
+ALTER TABLE tasks
+ADD COLUMN project_id BIGINT REFERENCES projects(id) ON DELETE SET NULL,
+ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_tasks_project_position ON tasks (project_id, position);
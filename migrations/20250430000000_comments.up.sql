@@ -0,0 +1,23 @@
+CREATE TABLE comments (
+    id SERIAL PRIMARY KEY,
+    task_id BIGINT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    author_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    edited_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_comments_task_id ON comments (task_id, created_at);
+CREATE INDEX idx_comments_author_id ON comments (author_id);
+CREATE INDEX idx_comments_deleted_at ON comments (deleted_at);
+
+CREATE TABLE comment_edits (
+    id SERIAL PRIMARY KEY,
+    comment_id BIGINT NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    edited_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_comment_edits_comment_id ON comment_edits (comment_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_tasks_series_id;
+ALTER TABLE tasks
+DROP COLUMN series_id,
+DROP COLUMN recurrence;
+
+DROP TABLE IF EXISTS task_series;
@@ -0,0 +1,17 @@
+CREATE TABLE task_series (
+    id SERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    task TEXT NOT NULL,
+    priority VARCHAR(16) NOT NULL DEFAULT 'normal',
+    recurrence VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_task_series_user_id ON task_series (user_id);
+
+ALTER TABLE tasks
+ADD COLUMN recurrence VARCHAR(255) NOT NULL DEFAULT '',
+ADD COLUMN series_id BIGINT REFERENCES task_series(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_tasks_series_id ON tasks (series_id, due_at);
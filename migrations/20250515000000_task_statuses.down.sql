@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_tasks_status_id;
+ALTER TABLE tasks
+DROP COLUMN status_id;
+
+DROP TABLE IF EXISTS task_status_transitions;
+DROP TABLE IF EXISTS task_statuses;
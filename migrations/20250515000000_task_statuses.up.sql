@@ -0,0 +1,54 @@
+CREATE TABLE task_statuses (
+    id SERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(64) NOT NULL,
+    category VARCHAR(16) NOT NULL CHECK (category IN ('todo', 'in_progress', 'done')),
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_task_statuses_user_id ON task_statuses (user_id, position);
+
+CREATE TABLE task_status_transitions (
+    from_id BIGINT NOT NULL REFERENCES task_statuses(id) ON DELETE CASCADE,
+    to_id BIGINT NOT NULL REFERENCES task_statuses(id) ON DELETE CASCADE,
+    PRIMARY KEY (from_id, to_id)
+);
+
+-- Доска по умолчанию для всех существующих пользователей
+INSERT INTO task_statuses (user_id, name, category, position)
+SELECT u.id, d.name, d.category, d.position
+FROM users u
+CROSS JOIN (VALUES
+    ('backlog', 'todo', 1),
+    ('in_progress', 'in_progress', 2),
+    ('review', 'in_progress', 3),
+    ('done', 'done', 4)
+) AS d (name, category, position);
+
+INSERT INTO task_status_transitions (from_id, to_id)
+SELECT f.id, t.id
+FROM task_statuses f
+JOIN task_statuses t ON t.user_id = f.user_id
+JOIN (VALUES
+    ('backlog', 'in_progress'),
+    ('backlog', 'done'),
+    ('in_progress', 'backlog'),
+    ('in_progress', 'review'),
+    ('in_progress', 'done'),
+    ('review', 'in_progress'),
+    ('review', 'done'),
+    ('done', 'in_progress')
+) AS r (from_name, to_name) ON r.from_name = f.name AND r.to_name = t.name;
+
+ALTER TABLE tasks
+ADD COLUMN status_id BIGINT REFERENCES task_statuses(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_tasks_status_id ON tasks (status_id);
+
+-- is_done становится производным: выполненные задачи попадают в done, остальные в backlog
+UPDATE tasks
+SET status_id = s.id
+FROM task_statuses s
+WHERE s.user_id = tasks.user_id
+  AND s.name = CASE WHEN tasks.is_done THEN 'done' ELSE 'backlog' END;
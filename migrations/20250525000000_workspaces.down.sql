@@ -0,0 +1,18 @@
+-- При откате данные всех пространств смешиваются: одинаковые названия меток
+-- одного пользователя в разных пространствах нарушат ограничение
+ALTER TABLE tags DROP CONSTRAINT IF EXISTS idx_tags_workspace_user_name;
+ALTER TABLE tags ADD CONSTRAINT idx_tags_user_name UNIQUE (user_id, name);
+
+DROP INDEX IF EXISTS idx_task_series_workspace_id;
+DROP INDEX IF EXISTS idx_task_statuses_workspace_id;
+DROP INDEX IF EXISTS idx_projects_workspace_id;
+DROP INDEX IF EXISTS idx_tasks_workspace_id;
+
+ALTER TABLE task_series DROP COLUMN workspace_id;
+ALTER TABLE task_statuses DROP COLUMN workspace_id;
+ALTER TABLE tags DROP COLUMN workspace_id;
+ALTER TABLE projects DROP COLUMN workspace_id;
+ALTER TABLE tasks DROP COLUMN workspace_id;
+
+DROP TABLE IF EXISTS workspace_members;
+DROP TABLE IF EXISTS workspaces;
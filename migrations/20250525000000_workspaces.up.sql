@@ -0,0 +1,57 @@
+CREATE TABLE workspaces (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(64) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Пространство по умолчанию может быть только одно
+CREATE UNIQUE INDEX idx_workspaces_default ON workspaces (is_default) WHERE is_default;
+
+CREATE TABLE workspace_members (
+    workspace_id BIGINT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(16) NOT NULL CHECK (role IN ('owner', 'admin', 'member')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE INDEX idx_workspace_members_user_id ON workspace_members (user_id);
+
+-- Все существующие данные переезжают в общее пространство по умолчанию.
+-- Администраторы системы становятся его владельцами, остальные — участниками.
+INSERT INTO workspaces (name, is_default) VALUES ('Default', true);
+
+INSERT INTO workspace_members (workspace_id, user_id, role, created_at)
+SELECT w.id, u.id, CASE WHEN u.role = 'admin' THEN 'owner' ELSE 'member' END, u.created_at
+FROM users u
+CROSS JOIN workspaces w
+WHERE w.is_default;
+
+ALTER TABLE tasks ADD COLUMN workspace_id BIGINT REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE projects ADD COLUMN workspace_id BIGINT REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE tags ADD COLUMN workspace_id BIGINT REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE task_statuses ADD COLUMN workspace_id BIGINT REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE task_series ADD COLUMN workspace_id BIGINT REFERENCES workspaces(id) ON DELETE CASCADE;
+
+UPDATE tasks SET workspace_id = (SELECT id FROM workspaces WHERE is_default);
+UPDATE projects SET workspace_id = (SELECT id FROM workspaces WHERE is_default);
+UPDATE tags SET workspace_id = (SELECT id FROM workspaces WHERE is_default);
+UPDATE task_statuses SET workspace_id = (SELECT id FROM workspaces WHERE is_default);
+UPDATE task_series SET workspace_id = (SELECT id FROM workspaces WHERE is_default);
+
+ALTER TABLE tasks ALTER COLUMN workspace_id SET NOT NULL;
+ALTER TABLE projects ALTER COLUMN workspace_id SET NOT NULL;
+ALTER TABLE tags ALTER COLUMN workspace_id SET NOT NULL;
+ALTER TABLE task_statuses ALTER COLUMN workspace_id SET NOT NULL;
+ALTER TABLE task_series ALTER COLUMN workspace_id SET NOT NULL;
+
+CREATE INDEX idx_tasks_workspace_id ON tasks (workspace_id);
+CREATE INDEX idx_projects_workspace_id ON projects (workspace_id);
+CREATE INDEX idx_task_statuses_workspace_id ON task_statuses (workspace_id);
+CREATE INDEX idx_task_series_workspace_id ON task_series (workspace_id);
+
+-- Метки уникальны в пределах пространства, а не пользователя
+ALTER TABLE tags DROP CONSTRAINT idx_tags_user_name;
+ALTER TABLE tags ADD CONSTRAINT idx_tags_workspace_user_name UNIQUE (workspace_id, user_id, name);
@@ -0,0 +1,7 @@
+-- Сквозная уникальность email несовместима с корзиной: пользователи из нее
+-- удаляются окончательно вместе со своими данными
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_users_deleted_at;
+DROP INDEX IF EXISTS idx_users_email;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
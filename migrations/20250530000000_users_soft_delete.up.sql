@@ -0,0 +1,6 @@
+-- Удаленные пользователи остаются в таблице до очистки корзины, поэтому email
+-- уникален только среди неудаленных: освободившийся адрес можно занять снова
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS uni_users_email;
+CREATE UNIQUE INDEX idx_users_email ON users (email) WHERE deleted_at IS NULL;
+CREATE INDEX idx_users_deleted_at ON users (deleted_at);
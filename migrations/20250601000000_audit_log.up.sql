@@ -0,0 +1,19 @@
+-- Журнал изменений задач и пользователей. Ссылок на пользователей и пространства
+-- нет намеренно: записи переживают очистку корзины и удаление пространства.
+CREATE TABLE audit_entries (
+    id SERIAL PRIMARY KEY,
+    actor_id INTEGER,
+    workspace_id INTEGER,
+    entity VARCHAR(32) NOT NULL,
+    entity_id INTEGER NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    before JSONB,
+    after JSONB,
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_audit_entries_entity ON audit_entries (entity, entity_id);
+CREATE INDEX idx_audit_entries_actor_id ON audit_entries (actor_id);
+CREATE INDEX idx_audit_entries_workspace_id ON audit_entries (workspace_id);
+CREATE INDEX idx_audit_entries_created_at ON audit_entries (created_at);
@@ -0,0 +1,16 @@
+CREATE TABLE task_events (
+    id SERIAL PRIMARY KEY,
+    workspace_id BIGINT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    task_id BIGINT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    actor_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    type VARCHAR(16) NOT NULL CHECK (type IN ('created', 'renamed', 'completed', 'reopened', 'reassigned')),
+    old_value TEXT NOT NULL DEFAULT '',
+    new_value TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_task_events_task_id ON task_events (task_id, created_at);
+
+-- У задач, созданных до появления ленты, в ней будет хотя бы создание
+INSERT INTO task_events (workspace_id, task_id, actor_id, type, created_at)
+SELECT workspace_id, id, user_id, 'created', created_at FROM tasks;
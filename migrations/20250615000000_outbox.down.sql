@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_webhook_deliveries_event_id;
+DROP TABLE IF EXISTS outbox_messages;
@@ -0,0 +1,25 @@
+-- Outbox: события пишутся в транзакции изменения, ретранслятор публикует их
+-- и проставляет published_at. Ссылок на пространства и пользователей нет:
+-- событие об удалении должно дойти до получателей.
+CREATE TABLE outbox_messages (
+    id SERIAL PRIMARY KEY,
+    key VARCHAR(64) NOT NULL,
+    event VARCHAR(32) NOT NULL,
+    workspace_id INTEGER,
+    actor_id INTEGER,
+    data TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    published_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX idx_outbox_messages_key ON outbox_messages (key);
+CREATE INDEX idx_outbox_messages_workspace_id ON outbox_messages (workspace_id);
+CREATE INDEX idx_outbox_messages_next_attempt_at ON outbox_messages (next_attempt_at) WHERE published_at IS NULL;
+CREATE INDEX idx_outbox_messages_published_at ON outbox_messages (published_at);
+
+-- Доставки вебхуков теперь создаются из outbox, event_id — ID сообщения.
+-- По нему повторная публикация находит уже созданные доставки.
+CREATE INDEX idx_webhook_deliveries_event_id ON webhook_deliveries (event_id);